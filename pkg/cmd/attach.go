@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rancher/dolly/pkg/remote"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewAttachCommand() *cobra.Command {
+	return cli.Command(&Attach{}, cobra.Command{
+		Short: "Attach to the main process of a container",
+		Long: `Attach connects to the output of the main process of a container of a service, such as dolly attach -it api. Stdin and a
+terminal need stdin: true and tty: true on the container.`,
+	})
+}
+
+type Attach struct {
+	Container string `name:"container" usage:"specify container name to attach to, defaults to the first container" short:"c"`
+	Index     int    `name:"index" usage:"Pick the running pod at this position, sorted by name, instead of a ready one" default:"-1"`
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	Stdin     bool   `name:"stdin" usage:"pass standard input to the container" short:"i"`
+	Tty       bool   `name:"tty" usage:"stdin is a tty" short:"t"`
+}
+
+func (a *Attach) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("a service is required")
+	}
+
+	namespace, pod, err := pickPod(cmd.Context(), a.Namespace, args[0], a.Index)
+	if err != nil {
+		return err
+	}
+	return remote.Attach(cmd.Context(), RestConfig, K8sInterface, remote.Option{
+		Namespace: namespace,
+		Pod:       pod,
+		Container: a.Container,
+		Stdin:     a.Stdin,
+		TTY:       a.Tty,
+		In:        os.Stdin,
+		Out:       os.Stdout,
+		ErrOut:    os.Stderr,
+	})
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/remote"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewCpCommand() *cobra.Command {
+	return cli.Command(&Cp{}, cobra.Command{
+		Short: "Copy files and directories to and from containers",
+		Long: `Cp copies a file or directory between the local machine and a container, which needs tar. The container side is given as
+service:path, such as dolly cp api:/var/log/app ./logs or dolly cp config.yaml api:/etc/app/.`,
+	})
+}
+
+type Cp struct {
+	Container string `name:"container" usage:"specify container name, defaults to the first container" short:"c"`
+	Index     int    `name:"index" usage:"Pick the running pod at this position, sorted by name, instead of a ready one" default:"-1"`
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+}
+
+func (c *Cp) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("a source and a destination are required")
+	}
+
+	srcService, srcPath := servicePath(args[0])
+	destService, destPath := servicePath(args[1])
+	service := srcService
+	if service == "" {
+		service = destService
+	}
+
+	switch {
+	case srcService != "" && destService != "":
+		return fmt.Errorf("copying between containers is not supported, one side has to be local")
+	case service == "":
+		return fmt.Errorf("one of %s and %s has to be in a container, given as service:path", args[0], args[1])
+	}
+
+	namespace, pod, err := pickPod(cmd.Context(), c.Namespace, service, c.Index)
+	if err != nil {
+		return err
+	}
+	option := remote.Option{
+		Namespace: namespace,
+		Pod:       pod,
+		Container: c.Container,
+	}
+	if srcService != "" {
+		return remote.CopyFromPod(cmd.Context(), RestConfig, K8sInterface, option, srcPath, destPath)
+	}
+	return remote.CopyToPod(cmd.Context(), RestConfig, K8sInterface, option, srcPath, destPath)
+}
+
+// servicePath splits service:path, a local path has no service. A colon after a / is part of a local path, unless the
+// service has a type such as pod/web-0:/tmp.
+func servicePath(arg string) (string, string) {
+	i := strings.Index(arg, ":")
+	if i <= 0 || strings.Count(arg[:i], "/") > 1 || strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "/") {
+		return "", arg
+	}
+	return arg[:i], arg[i+1:]
+}
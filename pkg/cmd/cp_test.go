@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServicePath(t *testing.T) {
+	for _, test := range []struct {
+		arg, service, path string
+	}{
+		{arg: "web:/tmp", service: "web", path: "/tmp"},
+		{arg: "web:", service: "web", path: ""},
+		{arg: "pod/web-0:/tmp", service: "pod/web-0", path: "/tmp"},
+		{arg: "deploy/web:app/config", service: "deploy/web", path: "app/config"},
+		{arg: "./a:b", path: "./a:b"},
+		{arg: "../a:b", path: "../a:b"},
+		{arg: "/tmp/a:b", path: "/tmp/a:b"},
+		{arg: "dir/sub/a:b", path: "dir/sub/a:b"},
+		{arg: ":b", path: ":b"},
+		{arg: "local.txt", path: "local.txt"},
+	} {
+		service, path := servicePath(test.arg)
+		assert.Equal(t, test.service, service, test.arg)
+		assert.Equal(t, test.path, path, test.arg)
+	}
+}
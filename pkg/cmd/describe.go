@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rancher/dolly/pkg/describe"
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/lifecycle"
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewDescribeCommand() *cobra.Command {
+	return cli.Command(&Describe{}, cobra.Command{
+		Short: "Show a service with its pods, endpoints, volumes, events and problems",
+		Long: `Describe shows the workload of a service with its ReplicaSets, pods, Service endpoints, Ingresses, volume claims and recent
+events in one report, such as dolly describe api. It ends with the problems found, such as images that can't be pulled, claims
+that are not bound, failing probes, containers killed for running out of memory and ingresses routing to no endpoints. A
+service is a service of the dollyfile, a workload as deploy/api, sts/db or ds/agent, or a pod.`,
+	})
+}
+
+type Describe struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+}
+
+func (d *Describe) Run(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("require at least one service")
+	}
+
+	services := dollyfile.DefaultServices()
+	first := true
+	for _, arg := range args {
+		target, err := resolve.Resolve(cmd.Context(), K8sInterface, services, d.Namespace, arg)
+		if err != nil {
+			return err
+		}
+
+		workloads := []lifecycle.Workload{{
+			Namespace: target.Namespace,
+			Kind:      target.Type,
+			Name:      target.Name,
+		}}
+		if target.Type != types.PodType {
+			if workloads, err = lifecycle.Workloads(cmd.Context(), K8sInterface, target); err != nil {
+				return err
+			}
+		}
+
+		for _, w := range workloads {
+			report, err := describe.Gather(cmd.Context(), K8sInterface, w.Namespace, w.Kind, w.Name)
+			if err != nil {
+				return err
+			}
+			if !first {
+				fmt.Println()
+			}
+			first = false
+			if err := report.Write(os.Stdout); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
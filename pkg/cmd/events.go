@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rancher/dolly/pkg/describe"
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+func NewEventsCommand() *cobra.Command {
+	return cli.Command(&Events{}, cobra.Command{
+		Short: "Show the events of the objects of the project",
+		Long: `Events prints the recent events of the workloads of the dollyfile of the current directory and of their ReplicaSets,
+pods, Services and Ingresses, or of the services given, such as dolly events api. -f keeps printing new events as they happen.
+Outside of a project every event of the namespace is printed.`,
+	})
+}
+
+type Events struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	Follow    bool   `name:"follow" usage:"Print new events as they happen" short:"f"`
+	File      string `name:"file" usage:"Dollyfile of the project, defaults to the DollyFile of the current directory"`
+}
+
+func (e *Events) Run(cmd *cobra.Command, args []string) error {
+	involved, err := e.involved(cmd.Context(), args)
+	if err != nil {
+		return err
+	}
+
+	// the events are listed from the API directly, so a list that fails returns its error instead of being retried by
+	// an informer
+	list, err := K8sInterface.CoreV1().Events(e.Namespace).List(cmd.Context(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	// the objects of events checked against the objects just gathered, the pods and replicasets created while
+	// following are gathered again once
+	gathered := map[string]bool{}
+	var matched []*v1.Event
+	for i := range list.Items {
+		ref := list.Items[i].InvolvedObject
+		gathered[ref.Kind+"/"+ref.Name] = true
+		if involved == nil || involved.Involves(&list.Items[i]) {
+			matched = append(matched, &list.Items[i])
+		}
+	}
+	describe.SortEvents(matched)
+
+	// the resource version printed of each event, an event is updated when it happens again
+	printed := map[ktypes.UID]string{}
+	show := func(obj interface{}) {
+		event, ok := obj.(*v1.Event)
+		if !ok || printed[event.UID] == event.ResourceVersion {
+			return
+		}
+		if involved != nil && !involved.Involves(event) {
+			ref := event.InvolvedObject
+			if (ref.Kind != "Pod" && ref.Kind != "ReplicaSet") || gathered[ref.Kind+"/"+ref.Name] {
+				return
+			}
+			gathered[ref.Kind+"/"+ref.Name] = true
+			if updated, err := e.involved(cmd.Context(), args); err == nil {
+				involved = updated
+			}
+			if !involved.Involves(event) {
+				return
+			}
+		}
+		printed[event.UID] = event.ResourceVersion
+		fmt.Fprintln(os.Stdout, describe.FormatEvent(event))
+	}
+	for _, event := range matched {
+		show(event)
+	}
+	if !e.Follow {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(K8sInterface, 0, informers.WithNamespace(e.Namespace))
+	factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    show,
+		UpdateFunc: func(_, obj interface{}) { show(obj) },
+	})
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	return nil
+}
+
+// involved returns the objects to print the events of, the objects describe gathers for each service or workload, nil
+// for every object
+func (e *Events) involved(ctx context.Context, args []string) (describe.Involved, error) {
+	if len(args) > 0 {
+		services := dollyfile.DefaultServices()
+		involved := describe.Involved{}
+		for _, arg := range args {
+			target, err := resolve.Resolve(ctx, K8sInterface, services, e.Namespace, arg)
+			if err != nil {
+				return nil, err
+			}
+			if err := gatherTarget(ctx, involved, target); err != nil {
+				return nil, err
+			}
+		}
+		return involved, nil
+	}
+
+	file := e.File
+	if file == "" {
+		if _, err := os.Stat("DollyFile"); err != nil {
+			return nil, nil
+		}
+		file = "DollyFile"
+	}
+	workloads, err := dollyfileWorkloads(file, e.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	involved := describe.Involved{}
+	for key := range workloads {
+		kind, name := kv.Split(key, "/")
+		if err := gatherWorkload(ctx, involved, e.Namespace, kind, name); err != nil {
+			return nil, err
+		}
+	}
+	return involved, nil
+}
+
+// gatherTarget adds the objects of a target, a service being the workload named after it and the pods it selects
+func gatherTarget(ctx context.Context, involved describe.Involved, target resolve.Target) error {
+	if target.Type == types.ServiceType {
+		for _, kind := range []string{types.DeploymentType, types.StatefulSetType, types.DaemonSetType} {
+			if err := gatherWorkload(ctx, involved, target.Namespace, kind, target.Name); err != nil {
+				return err
+			}
+		}
+	} else if err := gatherWorkload(ctx, involved, target.Namespace, target.Type, target.Name); err != nil {
+		return err
+	}
+
+	if target.Selector == nil {
+		return nil
+	}
+	pods, err := K8sInterface.CoreV1().Pods(target.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: target.Selector.String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		involved.Add("Pod", pod.Name)
+	}
+	return nil
+}
+
+// gatherWorkload adds the objects describe gathers for a deployment, statefulset, daemonset or pod that exists, or the
+// object itself for the other kinds
+func gatherWorkload(ctx context.Context, involved describe.Involved, namespace, kind, name string) error {
+	switch kind {
+	case types.DeploymentType, types.StatefulSetType, types.DaemonSetType, types.PodType:
+	default:
+		involved.Add(kind, name)
+		return nil
+	}
+
+	report, err := describe.Gather(ctx, K8sInterface, namespace, kind, name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for key := range report.Involved() {
+		involved[key] = true
+	}
+	return nil
+}
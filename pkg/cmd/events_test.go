@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// eventsService returns a deployment with a replicaset and a pod, named like the ones of kubernetes
+func eventsService(name, hash string) []runtime.Object {
+	labels := map[string]string{"app": name}
+	controller := true
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: ktypes.UID(name)},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+		},
+	}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-" + hash,
+			Namespace: "default",
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       name,
+				UID:        deploy.UID,
+				Controller: &controller,
+			}},
+		},
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-" + hash + "-x2k9p", Namespace: "default", Labels: labels},
+	}
+	return []runtime.Object{deploy, rs, pod}
+}
+
+func TestEventsInvolved(t *testing.T) {
+	objects := append(eventsService("api", "5d9f8b7c6"), eventsService("api-worker", "7c8d9f6b5")...)
+	old := K8sInterface
+	K8sInterface = fake.NewSimpleClientset(objects...)
+	defer func() { K8sInterface = old }()
+
+	involved, err := (&Events{Namespace: "default"}).involved(context.Background(), []string{"api"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, test := range []struct {
+		kind, name string
+		involved   bool
+	}{
+		{kind: "Deployment", name: "api", involved: true},
+		{kind: "ReplicaSet", name: "api-5d9f8b7c6", involved: true},
+		{kind: "Pod", name: "api-5d9f8b7c6-x2k9p", involved: true},
+		{kind: "Deployment", name: "api-worker"},
+		{kind: "ReplicaSet", name: "api-worker-7c8d9f6b5"},
+		{kind: "Pod", name: "api-worker-7c8d9f6b5-x2k9p"},
+	} {
+		event := &v1.Event{InvolvedObject: v1.ObjectReference{Kind: test.kind, Name: test.name}}
+		assert.Equal(t, test.involved, involved.Involves(event), "%s/%s", test.kind, test.name)
+	}
+}
@@ -1,51 +1,100 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
 
-	"github.com/rancher/wrangler/pkg/kv"
-
-	"github.com/rancher/dolly/pkg/kubectl"
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/remote"
+	"github.com/rancher/dolly/pkg/resolve"
 	cli "github.com/rancher/wrangler-cli"
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/exec"
 )
 
 func NewExecCommand() *cobra.Command {
-	exec := cli.Command(&Exec{}, cobra.Command{
+	execCommand := cli.Command(&Exec{}, cobra.Command{
 		Short: "Exec into pods",
+		Long: `Exec runs a command in a container of a service, such as dolly exec -it api sh. The service is a service of the dollyfile, a
+workload as deploy/api, sts/db, ds/agent or job/migrate, or a pod. A ready pod of the service is picked, or asked for if several are
+ready. Flags go before the service, everything after it is the command.`,
 	})
-	return exec
+	// flags of the command, such as sh -c, are not flags of exec
+	execCommand.Flags().SetInterspersed(false)
+	return execCommand
 }
 
 type Exec struct {
-	Container string `name:"container" usage:"specify container name to exec into" short:"c"`
-	Namespace string `name:"namespace" usage:"specify namespace" default:"default"`
+	Container string `name:"container" usage:"specify container name to exec into, defaults to the first container" short:"c"`
+	Index     int    `name:"index" usage:"Pick the running pod at this position, sorted by name, instead of a ready one" default:"-1"`
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
 	Stdin     bool   `name:"stdin" usage:"open standard input" short:"i"`
 	Tty       bool   `name:"tty" usage:"enable tty" short:"t"`
 }
 
 func (e *Exec) Run(cmd *cobra.Command, args []string) error {
 	if len(args) < 2 {
-		return fmt.Errorf("at least two parameters are needed")
+		return fmt.Errorf("a service and a command are required")
 	}
 
-	var namespace string
-	namespace, args[0] = kv.Split(args[0], ":")
-	if namespace != "" {
-		e.Namespace = namespace
+	namespace, pod, err := pickPod(cmd.Context(), e.Namespace, args[0], e.Index)
+	if err != nil {
+		return err
 	}
+	err = remote.Exec(cmd.Context(), RestConfig, K8sInterface, remote.Option{
+		Namespace: namespace,
+		Pod:       pod,
+		Container: e.Container,
+		Command:   args[1:],
+		Stdin:     e.Stdin,
+		TTY:       e.Tty,
+		In:        os.Stdin,
+		Out:       os.Stdout,
+		ErrOut:    os.Stderr,
+	})
+	exitWithCode(err)
+	return err
+}
 
-	args[0] = strings.TrimPrefix(args[0], "pod/")
-	if e.Stdin {
-		args = append([]string{"-i"}, args...)
+// pickPod returns the namespace and a pod of the service, workload or pod of the argument
+func pickPod(ctx context.Context, namespace, arg string, index int) (string, string, error) {
+	target, err := resolve.Resolve(ctx, K8sInterface, dollyfile.DefaultServices(), namespace, arg)
+	if err != nil {
+		return "", "", err
 	}
-	if e.Tty {
-		args = append([]string{"-t"}, args...)
+	var choose func([]v1.Pod) (int, error)
+	if isTerminal(os.Stdin) {
+		choose = choosePod
 	}
+	pod, err := target.Pod(ctx, K8sInterface, index, choose)
+	return target.Namespace, pod, err
+}
+
+// choosePod asks for one of the pods, the first one is the default, also when stdin is closed
+func choosePod(pods []v1.Pod) (int, error) {
+	for i, pod := range pods {
+		fmt.Fprintf(os.Stderr, "%d) %s\n", i, pod.Name)
+	}
+	fmt.Fprint(os.Stderr, "Pick a pod [0]: ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(answer)
+}
 
-	if e.Container != "" {
-		args = append(args, "-c", e.Container, "--")
+// exitWithCode exits with the exit code of the remote command, so scripts can check it
+func exitWithCode(err error) {
+	if exitErr, ok := err.(exec.ExitError); ok && exitErr.Exited() {
+		os.Exit(exitErr.ExitStatus())
 	}
-	return kubectl.Run(e.Namespace, "exec", Kubeconfig, Debug, args...)
 }
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/export"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func NewExportCommand() *cobra.Command {
+	export := cli.Command(&Export{}, cobra.Command{
+		Short: "Export running deployments/statefulsets/daemonsets into a dollyfile",
+		Long: `Export reads the workloads of a namespace together with their Services, Ingresses, ConfigMaps, PodDisruptionBudgets and RBAC and writes them as a dollyfile.
+Workloads are selected as kind/name, such as deployment/web, all workloads of the namespace are exported if none is given.`,
+	})
+	return export
+}
+
+type Export struct {
+	Namespace string `name:"namespace" usage:"Namespace to export from" default:"default" short:"n"`
+	Output    string `name:"output" usage:"File to write the dollyfile to, defaults to stdout" short:"o"`
+}
+
+func (e *Export) Run(cmd *cobra.Command, args []string) error {
+	objs, err := e.objects(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	rf, err := export.Export(objs, args...)
+	if err != nil {
+		return err
+	}
+
+	// hostnames added by dolly up are added again on the next up
+	for name, svc := range rf.Services {
+		var hostnames []string
+		for _, hostname := range svc.Spec.Hostnames {
+			if !strings.HasPrefix(hostname, fmt.Sprintf("%s-%s.", svc.Name, svc.Namespace)) {
+				hostnames = append(hostnames, hostname)
+			}
+		}
+		svc.Spec.Hostnames = hostnames
+		rf.Services[name] = svc
+	}
+
+	content, err := dollyfile.Marshal(rf)
+	if err != nil {
+		return err
+	}
+
+	if e.Output == "" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+	return ioutil.WriteFile(e.Output, content, 0644)
+}
+
+func (e *Export) objects(ctx context.Context) (objs export.Objects, err error) {
+	opts := metav1.ListOptions{}
+
+	deployments, err := K8sInterface.AppsV1().Deployments(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.Deployments = deployments.Items
+
+	statefulsets, err := K8sInterface.AppsV1().StatefulSets(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.StatefulSets = statefulsets.Items
+
+	daemonsets, err := K8sInterface.AppsV1().DaemonSets(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.DaemonSets = daemonsets.Items
+
+	services, err := K8sInterface.CoreV1().Services(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.Services = services.Items
+
+	endpoints, err := K8sInterface.CoreV1().Endpoints(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.Endpoints = endpoints.Items
+
+	configmaps, err := K8sInterface.CoreV1().ConfigMaps(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.ConfigMaps = configmaps.Items
+
+	pvcs, err := K8sInterface.CoreV1().PersistentVolumeClaims(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.PersistentVolumeClaims = pvcs.Items
+
+	ingresses, err := K8sInterface.NetworkingV1().Ingresses(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.Ingresses = ingresses.Items
+
+	roles, err := K8sInterface.RbacV1().Roles(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.Roles = roles.Items
+
+	roleBindings, err := K8sInterface.RbacV1().RoleBindings(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.RoleBindings = roleBindings.Items
+
+	clusterRoles, err := K8sInterface.RbacV1().ClusterRoles().List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.ClusterRoles = clusterRoles.Items
+
+	clusterRoleBindings, err := K8sInterface.RbacV1().ClusterRoleBindings().List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.ClusterRoleBindings = clusterRoleBindings.Items
+
+	pdbs, err := K8sInterface.PolicyV1beta1().PodDisruptionBudgets(e.Namespace).List(ctx, opts)
+	if err != nil {
+		return objs, err
+	}
+	objs.DisruptionBudgets = pdbs.Items
+
+	return objs, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewFmtCommand() *cobra.Command {
+	fmtCmd := cli.Command(&Fmt{}, cobra.Command{
+		Short: "Rewrite dollyfiles in the canonical short form",
+		Long: `Fmt rewrites the given dollyfiles, or the dollyfile set by --file, in the canonical short form with the keys sorted.
+Comments, go template actions and ${VAR} substitutions are kept. A dollyfile read from stdin(-) is written to stdout.`,
+	})
+	return fmtCmd
+}
+
+type Fmt struct {
+	File  string `name:"file" usage:"Path to dollyfile, can point to local file path or stdin(-)" default:"DollyFile" short:"f"`
+	Check bool   `name:"check" usage:"Only check the dollyfiles are formatted, fails listing the files that are not"`
+}
+
+func (f *Fmt) Run(cmd *cobra.Command, args []string) error {
+	files := args
+	if len(files) == 0 {
+		files = []string{f.File}
+	}
+
+	var unformatted []string
+	for _, file := range files {
+		content, err := f.read(file)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := dollyfile.Format(content)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", file, err)
+		}
+
+		switch {
+		case f.Check:
+			if !bytes.Equal(content, formatted) {
+				fmt.Println(file)
+				unformatted = append(unformatted, file)
+			}
+		case file == "-":
+			if _, err := os.Stdout.Write(formatted); err != nil {
+				return err
+			}
+		case !bytes.Equal(content, formatted):
+			if err := ioutil.WriteFile(file, formatted, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(unformatted) > 0 {
+		return fmt.Errorf("%d dollyfile(s) are not formatted, run dolly fmt", len(unformatted))
+	}
+	return nil
+}
+
+func (f *Fmt) read(file string) ([]byte, error) {
+	if file == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(file)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/scaffold"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+func NewInitCommand() *cobra.Command {
+	initCommand := cli.Command(&Init{}, cobra.Command{
+		Short: "Create a starter dollyfile for the project in a directory",
+		Long: `Init inspects a directory, the current one by default, and writes a commented dollyfile with ports, probes and resources.
+The services of a compose file are converted, otherwise the ports come from the EXPOSE lines of the Dockerfile and the defaults
+of the language of go.mod, package.json, pyproject.toml, requirements.txt, pom.xml, build.gradle, Gemfile, composer.json or
+Cargo.toml. If the images can be built from source, init offers to add a build section.`,
+	})
+	return initCommand
+}
+
+type Init struct {
+	File    string `name:"file" usage:"Name of the dollyfile to write in the directory" default:"DollyFile" short:"f"`
+	Build   bool   `name:"build" usage:"Add a build section without asking"`
+	NoBuild bool   `name:"no-build" usage:"Don't add a build section and don't ask"`
+	Force   bool   `name:"force" usage:"Overwrite an existing dollyfile"`
+}
+
+func (i *Init) Run(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	file := filepath.Join(dir, i.File)
+	if _, err := os.Stat(file); err == nil && !i.Force {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", file)
+	}
+
+	project, err := scaffold.Detect(dir)
+	if err != nil {
+		return err
+	}
+
+	build := i.Build
+	if project.HasBuild() && !i.Build && !i.NoBuild && isTerminal(os.Stdin) {
+		if build, err = confirm("Add a build section to build the images from source with dolly build?"); err != nil {
+			return err
+		}
+	}
+
+	if err := ioutil.WriteFile(file, project.Dollyfile(build), 0644); err != nil {
+		return err
+	}
+
+	if len(project.Sources) > 0 {
+		fmt.Printf("Wrote %s from %s\n", file, strings.Join(project.Sources, ", "))
+	} else {
+		fmt.Printf("Wrote %s\n", file)
+	}
+	return nil
+}
+
+func isTerminal(f *os.File) bool {
+	return term.TTY{In: f}.IsTerminalIn()
+}
+
+// confirm asks a yes or no question, yes is the default, also when stdin is closed
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s [Y/n] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
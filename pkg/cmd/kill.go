@@ -2,10 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
-
-	"github.com/rancher/wrangler/pkg/kv"
 
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/resolve"
 	cli "github.com/rancher/wrangler-cli"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +13,7 @@ import (
 func NewKillCommand() *cobra.Command {
 	kill := cli.Command(&Kill{}, cobra.Command{
 		Short: "kill/delete pods",
+		Long:  `Kill deletes pods, or all the pods of services such as dolly kill api, so their workloads recreate them.`,
 	})
 	return kill
 }
@@ -27,15 +27,21 @@ func (k *Kill) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("require at least one argument")
 	}
 
+	services := dollyfile.DefaultServices()
 	for _, arg := range args {
-		var namespace string
-		namespace, arg = kv.Split(arg, ":")
-		if namespace == "" {
-			namespace = k.Namespace
+		target, err := resolve.Resolve(cmd.Context(), K8sInterface, services, k.Namespace, arg)
+		if err != nil {
+			return err
 		}
-		if err := K8sInterface.CoreV1().Pods(namespace).Delete(cmd.Context(), strings.TrimPrefix(arg, "pod/"), metav1.DeleteOptions{}); err != nil {
+		pods, err := target.Pods(cmd.Context(), K8sInterface)
+		if err != nil {
 			return err
 		}
+		for _, pod := range pods {
+			if err := K8sInterface.CoreV1().Pods(pod.Namespace).Delete(cmd.Context(), pod.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
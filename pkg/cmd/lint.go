@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/lint"
+	"github.com/rancher/dolly/pkg/template"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewLintCommand() *cobra.Command {
+	lint := cli.Command(&Lint{}, cobra.Command{
+		Short: "Check a dollyfile against policy rules",
+		Long: `Lint renders the dollyfile and checks the services and Kubernetes objects against the built-in rules: no privileged containers,
+no host network or host path volumes, images pinned to a tag other than latest and from an allowed registry, and a memory limit on
+every container. Custom CEL rules are loaded from a policy file with --policy. Findings are printed as text, json or sarif.`,
+	})
+	return lint
+}
+
+type Lint struct {
+	File            string   `name:"file" usage:"Path to dollyfile, can point to local file path, https links or stdin(-)" default:"DollyFile" short:"f"`
+	AnswerFile      string   `name:"answer-file" usage:"Answer file set for dollyfile" default:"DollyFile-answers" short:"a"`
+	Policy          string   `name:"policy" usage:"Policy file with custom CEL rules" short:"p"`
+	AllowedRegistry []string `name:"allowed-registry" usage:"Registry images must come from, such as registry.example.com or registry.example.com/team"`
+	Disable         []string `name:"disable" usage:"Rule to skip, such as latest-tag"`
+	Output          string   `name:"output" usage:"Output format, one of text, json or sarif" default:"text" short:"o"`
+}
+
+func (l *Lint) Run(cmd *cobra.Command, args []string) error {
+	switch l.Output {
+	case "text", "json", "sarif":
+	default:
+		return fmt.Errorf("invalid output %s, must be text, json or sarif", l.Output)
+	}
+
+	l.AllowedRegistry = stringSliceFlag(cmd, "allowed-registry")
+	l.Disable = stringSliceFlag(cmd, "disable")
+
+	rules := lint.Rules
+	if l.Policy != "" {
+		custom, err := lint.LoadPolicy(l.Policy)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, custom...)
+	}
+
+	content, answers, err := dollyfile.LoadFileAndAnswer(l.File, l.AnswerFile)
+	if err != nil {
+		return err
+	}
+
+	rf, err := dollyfile.Parse(content, "default", template.AnswersFromMap(answers))
+	if err != nil {
+		return err
+	}
+	rf.Plugins = plugins()
+
+	findings, err := lint.Lint(content, rf, rules, lint.Options{
+		AllowedRegistries: l.AllowedRegistry,
+		Disabled:          l.Disable,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := l.print(rules, findings); err != nil {
+		return err
+	}
+	if lint.HasErrors(findings) {
+		return fmt.Errorf("%s does not pass the lint rules", l.File)
+	}
+	return nil
+}
+
+func (l *Lint) print(rules []lint.Rule, findings []lint.Finding) error {
+	switch l.Output {
+	case "json":
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		content, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(content))
+	case "sarif":
+		content, err := lint.SARIF(l.File, rules, findings)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(content))
+	default:
+		for _, finding := range findings {
+			fmt.Printf("%s:%s\n", l.File, finding)
+		}
+	}
+	return nil
+}
@@ -1,131 +1,174 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/rancher/dolly/pkg/dollyfile"
 	"github.com/rancher/dolly/pkg/log"
-	"github.com/rancher/dolly/pkg/table/types"
+	"github.com/rancher/dolly/pkg/resolve"
 	cli "github.com/rancher/wrangler-cli"
-	"github.com/rancher/wrangler/pkg/kv"
 	"github.com/spf13/cobra"
 	"github.com/stern/stern/stern"
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func NewLogCommand() *cobra.Command {
 	logs := cli.Command(&Logs{}, cobra.Command{
-		Short: "Log deployments/daemonsets/statefulsets/pods",
+		Short: "Log services, deployments/daemonsets/statefulsets/jobs/cronjobs and pods",
+		Long: `Logs follows the logs of the pods of services, such as dolly logs api worker. A service is a service of the dollyfile, a
+workload as deploy/api, sts/db, ds/agent, job/migrate or cronjob/backup, or a pod. The logs of finished jobs and pods are
+printed too. --grep and --exclude filter the lines with regexes. Lines that are JSON objects can be filtered by their fields
+with --where level=error and shortened to some fields with --fields level,msg.`,
 	})
 	return logs
 }
 
 type Logs struct {
-	Namespace      string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
-	Container      string `name:"container" usage:"Print the logs of a specific container" short:"c"`
-	InitContainers bool   `name:"init" usage:"Include or exclude init containers" default:"true"`
-	NoColor        bool   `name:"no-color" usage:"Dont show color when logging" default:"false"`
-	Output         string `name:"output" usage:"Output format: [default, raw, json]"`
-	Previous       bool   `name:"previous" usage:"Print the logs for the previous instance of the container in a pod if it exists, excludes running" short:"p"`
-	Since          string `name:"since" desc:"Logs since a certain time, either duration (5s, 2m, 3h) or RFC3339" default:"24h"`
-	Tail           int    `name:"tail" usage:"Number of recent lines to print, -1 for all" default:"200" short:"t"`
-	Timestamps     bool   `name:"timestamps" usage:"Print the logs with timestamp" default:"false"`
+	Namespace      string   `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	Container      string   `name:"container" usage:"Print the logs of a specific container" short:"c"`
+	InitContainers bool     `name:"init" usage:"Include or exclude init containers" default:"true"`
+	NoColor        bool     `name:"no-color" usage:"Dont show color when logging" default:"false"`
+	Output         string   `name:"output" usage:"Output format: [default, raw, json]"`
+	Previous       bool     `name:"previous" usage:"Print the logs for the previous instance of the container in a pod if it exists, excludes running" short:"p"`
+	Since          string   `name:"since" usage:"Logs since a certain time, either a duration (5s, 2m, 3h, 2d) or RFC3339" default:"24h"`
+	Tail           int      `name:"tail" usage:"Number of recent lines to print, -1 for all" default:"200" short:"t"`
+	Timestamps     bool     `name:"timestamps" usage:"Print the logs with timestamp" default:"false"`
+	Grep           []string `name:"grep" usage:"Only print the lines matching a regex, can be given more than once" short:"g"`
+	Exclude        []string `name:"exclude" usage:"Leave out the lines matching a regex, can be given more than once" short:"e"`
+	MaxLogRequests int      `name:"max-log-requests" usage:"Maximum number of containers followed at once" default:"50"`
+	Fields         string   `name:"fields" usage:"Print these fields of JSON lines, such as level,msg"`
+	Where          []string `name:"where" usage:"Only print the JSON lines with a field of a value, such as level=error or level!=debug"`
 }
 
 func (l *Logs) Run(cmd *cobra.Command, args []string) error {
-	config := &stern.Config{
-		LabelSelector:  labels.Everything(),
-		Timestamps:     l.Timestamps,
-		Namespace:      l.Namespace,
-		InitContainers: l.InitContainers,
+	if len(args) == 0 {
+		return fmt.Errorf("require at least one service")
 	}
+	l.Grep = stringSliceFlag(cmd, "grep")
+	l.Exclude = stringSliceFlag(cmd, "exclude")
+	l.Where = stringSliceFlag(cmd, "where")
 
-	var result runtime.Object
-	var err error
-
-	var namespace string
-	namespace, args[0] = kv.Split(args[0], ":")
-	if namespace != "" {
-		l.Namespace = namespace
+	structured := &log.Structured{}
+	if l.Fields != "" {
+		structured.Fields = strings.Split(l.Fields, ",")
 	}
-
-	t, resourceName := kv.Split(args[0], "/")
-	if t == types.DeploymentType {
-		result, err = K8sInterface.AppsV1().Deployments(l.Namespace).Get(cmd.Context(), resourceName, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-	} else if t == types.DaemonSetType {
-		result, err = K8sInterface.AppsV1().DaemonSets(l.Namespace).Get(cmd.Context(), resourceName, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-	} else if t == types.PodType {
-		result, err = K8sInterface.CoreV1().Pods(l.Namespace).Get(cmd.Context(), resourceName, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
+	where, err := log.ParseWhere(l.Where)
+	if err != nil {
+		return err
 	}
+	structured.Where = where
 
-	podName, sel, err := ToPodNameOrSelector(result)
+	template, err := log.Format(l.Output, l.NoColor, structured)
 	if err != nil {
 		return err
 	}
-
-	if podName == "" {
-		config.LabelSelector = sel
-		config.PodQuery, err = regexp.Compile("")
-	} else {
-		config.PodQuery, err = regexp.Compile(regexp.QuoteMeta(podName))
+	since, err := parseSince(l.Since)
+	if err != nil {
+		return err
 	}
-
-	config.Template, err = log.Format(l.Output, l.NoColor)
+	containerQuery, err := regexp.Compile(l.Container)
 	if err != nil {
 		return err
 	}
-
-	tail := int64(l.Tail)
-	config.TailLines = &tail
-
-	config.Since, err = time.ParseDuration(l.Since)
+	include, err := compileAll(l.Grep)
+	if err != nil {
+		return err
+	}
+	exclude, err := compileAll(l.Exclude)
 	if err != nil {
 		return err
 	}
+	tail := int64(l.Tail)
+
+	services := dollyfile.DefaultServices()
+	var configs []*stern.Config
+	for _, arg := range args {
+		target, err := resolve.Resolve(cmd.Context(), K8sInterface, services, l.Namespace, arg)
+		if err != nil {
+			return err
+		}
+
+		config := &stern.Config{
+			LabelSelector:  labels.Everything(),
+			PodQuery:       regexp.MustCompile(""),
+			ContainerQuery: containerQuery,
+			Template:       template,
+			Since:          since,
+			TailLines:      &tail,
+			Timestamps:     l.Timestamps,
+			Namespace:      target.Namespace,
+			InitContainers: l.InitContainers,
+			ContainerState: stern.RUNNING,
+			Include:        include,
+			Exclude:        exclude,
+		}
+		if target.Selector == nil {
+			config.PodQuery = regexp.MustCompile("^" + regexp.QuoteMeta(target.Name) + "$")
+		} else {
+			config.LabelSelector = target.Selector
+		}
 
-	if len(config.ContainerState) == 0 {
 		if l.Previous {
 			config.ContainerState = stern.TERMINATED
-		} else {
-			config.ContainerState = stern.RUNNING
+		} else if finished, err := l.finished(cmd.Context(), target); err != nil {
+			return err
+		} else if finished {
+			// the pods of a job or a crashed pod have no running containers to log
+			config.ContainerState = stern.TERMINATED
 		}
+		configs = append(configs, config)
 	}
 
-	config.ContainerQuery, err = regexp.Compile(l.Container)
+	return log.Follow(cmd.Context(), configs, K8sInterface, l.MaxLogRequests)
+}
+
+// parseSince returns how long ago a time given as a duration, a number of days such as 2d, or an RFC3339 time was
+func parseSince(value string) (time.Duration, error) {
+	if since, err := time.ParseDuration(value); err == nil {
+		return since, nil
+	}
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("invalid --since %s, expected a duration such as 5m or 2d, or an RFC3339 time such as 2006-01-02T15:04:05Z", value)
 	}
-
-	return log.Output(cmd.Context(), config, l.Namespace, K8sInterface)
+	since := time.Since(t)
+	if since < 0 {
+		return 0, fmt.Errorf("--since %s is in the future", value)
+	}
+	// the logs are asked for since a number of seconds, round up to not miss the lines of the second given
+	return since.Truncate(time.Second) + time.Second, nil
 }
 
-func ToPodNameOrSelector(obj runtime.Object) (string, labels.Selector, error) {
-	switch v := obj.(type) {
-	case *corev1.Pod:
-		return v.Name, nil, nil
-	case *appsv1.Deployment:
-		return toSelector(v.Spec.Selector)
-	case *appsv1.DaemonSet:
-		return toSelector(v.Spec.Selector)
+func compileAll(exprs []string) ([]*regexp.Regexp, error) {
+	var result []*regexp.Regexp
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, re)
 	}
-
-	return "", labels.Nothing(), nil
+	return result, nil
 }
 
-func toSelector(sel *metav1.LabelSelector) (string, labels.Selector, error) {
-	l, err := metav1.LabelSelectorAsSelector(sel)
-	return "", l, err
+// finished reports whether the target has pods and none of them runs
+func (l *Logs) finished(ctx context.Context, target resolve.Target) (bool, error) {
+	pods, err := target.Pods(ctx, K8sInterface)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		if resolve.Running(pod) {
+			return false, nil
+		}
+	}
+	return len(pods) > 0, nil
 }
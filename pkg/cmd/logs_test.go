@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSince(t *testing.T) {
+	for _, test := range []struct {
+		value string
+		since time.Duration
+	}{
+		{value: "5m", since: 5 * time.Minute},
+		{value: "1h30m", since: 90 * time.Minute},
+		{value: "2d", since: 48 * time.Hour},
+		{value: "0d", since: 0},
+	} {
+		since, err := parseSince(test.value)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.since, since, test.value)
+	}
+
+	// an RFC3339 time is rounded up to the next second, the time passing while the test runs is allowed
+	value := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	since, err := parseSince(value)
+	assert.NoError(t, err)
+	assert.True(t, since >= time.Hour && since <= time.Hour+2*time.Second, "%s: %s", value, since)
+	assert.Equal(t, time.Duration(0), since%time.Second)
+
+	for _, value := range []string{"", "d", "2days", "yesterday", "2006-01-02", time.Now().Add(time.Hour).Format(time.RFC3339)} {
+		_, err := parseSince(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/portforward"
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/template"
+	dollytypes "github.com/rancher/dolly/pkg/types"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewPortForwardCommand() *cobra.Command {
+	return cli.Command(&PortForward{}, cobra.Command{
+		Short: "Forward the ports of services to localhost",
+		Long: `Port-forward forwards every port of services to a free local port, such as dolly port-forward api db. The port of a
+service is forwarded to a ready pod selected by its Service, the ports of workloads and pods to the ports their containers
+declare. A port is forwarded to another ready pod when its pod is replaced. Without services every service of the dollyfile
+is forwarded.`,
+	})
+}
+
+type PortForward struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	File      string `name:"file" usage:"Dollyfile of the services to forward when none is given" default:"DollyFile" short:"f"`
+}
+
+func (p *PortForward) Run(cmd *cobra.Command, args []string) error {
+	services := dollyfile.DefaultServices()
+	if len(args) == 0 {
+		content, answers, err := dollyfile.LoadFileAndAnswer(p.File, "")
+		if err != nil {
+			return err
+		}
+		rf, err := dollyfile.Parse(content, p.Namespace, template.AnswersFromMap(answers))
+		if err != nil {
+			return err
+		}
+		services = rf.Services
+		args = serviceNames(services)
+	}
+
+	forwards, err := serviceForwards(cmd.Context(), services, p.Namespace, args)
+	if err != nil {
+		return err
+	}
+	if len(forwards) == 0 {
+		return fmt.Errorf("no ports to forward")
+	}
+	if err := portforward.PickPorts(forwards); err != nil {
+		return err
+	}
+	if err := portforward.Print(os.Stdout, forwards); err != nil {
+		return err
+	}
+	portforward.Run(cmd.Context(), RestConfig, K8sInterface, forwards)
+	return nil
+}
+
+func serviceNames(services map[string]dollytypes.Service) []string {
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// serviceForwards returns the ports to forward of the services, workloads and pods of the arguments
+func serviceForwards(ctx context.Context, services map[string]dollytypes.Service, namespace string, args []string) ([]*portforward.Forward, error) {
+	var result []*portforward.Forward
+	for _, arg := range args {
+		target, err := resolve.Resolve(ctx, K8sInterface, services, namespace, arg)
+		if err != nil {
+			return nil, err
+		}
+		forwards, err := portforward.Forwards(ctx, K8sInterface, target)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, forwards...)
+	}
+	return result, nil
+}
@@ -1,16 +1,35 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/table/types"
 	"github.com/rancher/dolly/pkg/tables"
+	"github.com/rancher/dolly/pkg/template"
 	cli "github.com/rancher/wrangler-cli"
 	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
 )
 
+// clearScreen moves the cursor home and clears the terminal, so a watch redraws the table in place
+const clearScreen = "\033[H\033[2J"
+
 func NewPsCommand() *cobra.Command {
 	ps := cli.Command(&Ps{}, cobra.Command{
 		Short: "Show kubernetes deployments/daemonset/statesets",
+		Long: `Ps lists the workloads with their replicas, restarts, ports and rollout status, or their pods with -p. -w keeps the list
+refreshed as the workloads change, -o wide adds the hostnames and load balancer addresses of the workloads and -f only lists the
+workloads of a dollyfile.`,
 	})
 	return ps
 }
@@ -21,6 +40,9 @@ type Ps struct {
 	Pod       bool   `name:"pod" usage:"only show pods" short:"p"`
 	Quiet     bool   `name:"quiet" usage:"only print ID" short:"q"`
 	Format    string `name:"format" usage:"format(yaml/json/jsoncompact/raw)"`
+	Output    string `name:"output" usage:"Output wide to show more columns, or a format as --format" short:"o"`
+	Watch     bool   `name:"watch" usage:"Refresh the list when the workloads change" short:"w"`
+	File      string `name:"file" usage:"Only show the workloads and pods of the services and manifests of a dollyfile" short:"f"`
 }
 
 func (p *Ps) Run(cmd *cobra.Command, args []string) error {
@@ -28,47 +50,277 @@ func (p *Ps) Run(cmd *cobra.Command, args []string) error {
 	if p.All {
 		namespace = ""
 	}
+	if p.Output != "" && p.Output != "wide" {
+		p.Format = p.Output
+	}
 
-	var output []runtime.Object
-	if p.Pod {
-		pods, err := K8sInterface.CoreV1().Pods(namespace).List(cmd.Context(), metav1.ListOptions{})
-		if err != nil {
+	var filter map[string]bool
+	if p.File != "" {
+		var err error
+		if filter, err = dollyfileWorkloads(p.File, p.Namespace); err != nil {
 			return err
 		}
-		for i := range pods.Items {
-			output = append(output, &pods.Items[i])
-		}
-		w := tables.NewPods(namespace, p.Format, p.Quiet)
-		return w.Write(output)
 	}
 
-	deployments, err := K8sInterface.AppsV1().Deployments(namespace).List(cmd.Context(), metav1.ListOptions{})
+	// the first list goes to the API directly, so a list that fails returns its error instead of being retried by the
+	// informers
+	objects, err := p.list(cmd.Context(), namespace)
 	if err != nil {
 		return err
 	}
-
-	daemonsets, err := K8sInterface.AppsV1().DaemonSets(namespace).List(cmd.Context(), metav1.ListOptions{})
-	if err != nil {
+	if err := p.write(objects, namespace, filter); err != nil || !p.Watch {
 		return err
 	}
 
-	statefulsets, err := K8sInterface.AppsV1().StatefulSets(namespace).List(cmd.Context(), metav1.ListOptions{})
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(K8sInterface, 0, informers.WithNamespace(namespace))
+	changed := make(chan struct{}, 1)
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify(changed) },
+		UpdateFunc: func(interface{}, interface{}) { notify(changed) },
+		DeleteFunc: func(interface{}) { notify(changed) },
+	}
+	for _, informer := range p.informers(factory) {
+		informer.AddEventHandler(handler)
+	}
+
+	factory.Start(ctx.Done())
+	for informer, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to list %v", informer)
+		}
+	}
+
+	// redraw at most once a second, a rollout changes the workloads many times
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			select {
+			case <-changed:
+			default:
+				continue
+			}
+			objects, err := p.cached(factory)
+			if err != nil {
+				return err
+			}
+			if err := p.write(objects, namespace, filter); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func notify(changed chan struct{}) {
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
+
+// dollyfileWorkloads returns the workloads of the services and manifests of a dollyfile as type/name
+func dollyfileWorkloads(file, namespace string) (map[string]bool, error) {
+	content, answers, err := dollyfile.LoadFileAndAnswer(file, "")
 	if err != nil {
-		return err
+		return nil, err
+	}
+	rf, err := dollyfile.Parse(content, namespace, template.AnswersFromMap(answers))
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]bool{}
+	for name := range rf.Services {
+		// a service is deployed as one of the workloads named after it
+		for _, kind := range []string{types.DeploymentType, types.StatefulSetType, types.DaemonSetType} {
+			result[kind+"/"+name] = true
+		}
 	}
+	for _, obj := range rf.Kubernetes {
+		if r, err := types.FromObject(obj); err == nil {
+			result[r.Type+"/"+r.Name] = true
+		}
+	}
+	return result, nil
+}
 
+// psObjects are the objects ps prints, listed from the API or from the caches of the informers
+type psObjects struct {
+	deployments  []*appsv1.Deployment
+	daemonsets   []*appsv1.DaemonSet
+	statefulsets []*appsv1.StatefulSet
+	pods         []*v1.Pod
+	services     []*v1.Service
+	ingresses    []*networkingv1.Ingress
+}
+
+func (p *Ps) wide() bool {
+	return p.Output == "wide" && !p.Pod
+}
+
+func (p *Ps) informers(factory informers.SharedInformerFactory) []cache.SharedIndexInformer {
+	result := []cache.SharedIndexInformer{
+		factory.Apps().V1().Deployments().Informer(),
+		factory.Apps().V1().StatefulSets().Informer(),
+		factory.Apps().V1().DaemonSets().Informer(),
+		factory.Core().V1().Pods().Informer(),
+	}
+	if p.wide() {
+		result = append(result,
+			factory.Core().V1().Services().Informer(),
+			factory.Networking().V1().Ingresses().Informer())
+	}
+	return result
+}
+
+// list lists the objects from the API
+func (p *Ps) list(ctx context.Context, namespace string) (*psObjects, error) {
+	result := &psObjects{}
+	opts := metav1.ListOptions{}
+
+	deployments, err := K8sInterface.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 	for i := range deployments.Items {
-		output = append(output, &deployments.Items[i])
+		result.deployments = append(result.deployments, &deployments.Items[i])
 	}
 
+	daemonsets, err := K8sInterface.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 	for i := range daemonsets.Items {
-		output = append(output, &daemonsets.Items[i])
+		result.daemonsets = append(result.daemonsets, &daemonsets.Items[i])
 	}
 
+	statefulsets, err := K8sInterface.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 	for i := range statefulsets.Items {
-		output = append(output, &statefulsets.Items[i])
+		result.statefulsets = append(result.statefulsets, &statefulsets.Items[i])
+	}
+
+	pods, err := K8sInterface.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		result.pods = append(result.pods, &pods.Items[i])
+	}
+
+	if !p.wide() {
+		return result, nil
+	}
+
+	services, err := K8sInterface.CoreV1().Services(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		result.services = append(result.services, &services.Items[i])
+	}
+
+	ingresses, err := K8sInterface.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ingresses.Items {
+		result.ingresses = append(result.ingresses, &ingresses.Items[i])
+	}
+
+	return result, nil
+}
+
+// cached lists the objects from the caches of the informers
+func (p *Ps) cached(factory informers.SharedInformerFactory) (*psObjects, error) {
+	var (
+		result = &psObjects{}
+		err    error
+	)
+
+	if result.deployments, err = factory.Apps().V1().Deployments().Lister().List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if result.daemonsets, err = factory.Apps().V1().DaemonSets().Lister().List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if result.statefulsets, err = factory.Apps().V1().StatefulSets().Lister().List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if result.pods, err = factory.Core().V1().Pods().Lister().List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if !p.wide() {
+		return result, nil
+	}
+	if result.services, err = factory.Core().V1().Services().Lister().List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if result.ingresses, err = factory.Networking().V1().Ingresses().Lister().List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *Ps) write(objects *psObjects, namespace string, filter map[string]bool) error {
+	workloads := p.workloads(objects, filter)
+
+	if p.Watch && p.Format == "" {
+		fmt.Print(clearScreen)
 	}
 
-	w := tables.NewService(namespace, p.Format, p.Quiet)
-	return w.Write(output)
+	if p.Pod {
+		var output []runtime.Object
+		for _, pod := range objects.pods {
+			if filter == nil {
+				output = append(output, pod)
+				continue
+			}
+			for _, workload := range workloads {
+				if tables.SelectsPod(workload, pod) {
+					output = append(output, pod)
+					break
+				}
+			}
+		}
+		w := tables.NewPods(namespace, p.Format, p.Quiet)
+		return w.Write(output)
+	}
+
+	related := &tables.Related{
+		Pods:      objects.pods,
+		Services:  objects.services,
+		Ingresses: objects.ingresses,
+	}
+	w := tables.NewService(namespace, p.Format, p.Quiet, p.Output == "wide", related)
+	return w.Write(workloads)
+}
+
+// workloads returns the deployments, daemonsets and statefulsets of the filter
+func (p *Ps) workloads(objects *psObjects, filter map[string]bool) []runtime.Object {
+	var output []runtime.Object
+	add := func(obj runtime.Object, kind, name string) {
+		if filter == nil || filter[kind+"/"+name] {
+			output = append(output, obj)
+		}
+	}
+
+	for _, obj := range objects.deployments {
+		add(obj, types.DeploymentType, obj.Name)
+	}
+	for _, obj := range objects.daemonsets {
+		add(obj, types.DaemonSetType, obj.Name)
+	}
+	for _, obj := range objects.statefulsets {
+		add(obj, types.StatefulSetType, obj.Name)
+	}
+	return output
 }
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPsListForbidden(t *testing.T) {
+	k8s := fake.NewSimpleClientset()
+	k8s.PrependReactor("list", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, "", nil)
+	})
+	old := K8sInterface
+	K8sInterface = k8s
+	defer func() { K8sInterface = old }()
+
+	_, err := (&Ps{}).list(context.Background(), "default")
+	assert.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+}
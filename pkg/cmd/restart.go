@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/lifecycle"
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func NewRestartCommand() *cobra.Command {
+	return cli.Command(&Restart{}, cobra.Command{
+		Short: "Restart the pods of services with a rolling update",
+		Long: `Restart replaces the pods of services one by one, the way kubectl rollout restart does, such as dolly restart api. Without
+services every deployment, statefulset and daemonset of the dollyfile is restarted.`,
+	})
+}
+
+type Restart struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	File      string `name:"file" usage:"Dollyfile of the services to restart when none is given" default:"DollyFile" short:"f"`
+}
+
+func (r *Restart) Run(cmd *cobra.Command, args []string) error {
+	workloads, err := projectWorkloads(cmd.Context(), r.Namespace, r.File, args)
+	if err != nil {
+		return err
+	}
+	for _, w := range workloads {
+		if err := lifecycle.Restart(cmd.Context(), K8sInterface, w.Namespace, w.Kind, w.Name); err != nil {
+			return err
+		}
+		logrus.Infof("Restarting %s", w)
+	}
+	return nil
+}
+
+// projectWorkloads returns the workloads of the services of the arguments, or the deployments, statefulsets and daemonsets
+// of the dollyfile that exist without arguments
+func projectWorkloads(ctx context.Context, namespace, file string, args []string) ([]lifecycle.Workload, error) {
+	if len(args) > 0 {
+		services := dollyfile.DefaultServices()
+		var result []lifecycle.Workload
+		for _, arg := range args {
+			target, err := resolve.Resolve(ctx, K8sInterface, services, namespace, arg)
+			if err != nil {
+				return nil, err
+			}
+			workloads, err := lifecycle.Workloads(ctx, K8sInterface, target)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, workloads...)
+		}
+		return result, nil
+	}
+
+	filter, err := dollyfileWorkloads(file, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []lifecycle.Workload
+	for key := range filter {
+		kind, name := kv.Split(key, "/")
+		switch kind {
+		case types.DeploymentType, types.StatefulSetType, types.DaemonSetType:
+			candidates = append(candidates, lifecycle.Workload{
+				Namespace: namespace,
+				Kind:      kind,
+				Name:      name,
+			})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].String() < candidates[j].String()
+	})
+	return lifecycle.Existing(ctx, K8sInterface, candidates)
+}
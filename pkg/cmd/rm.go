@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/resolve"
 	"github.com/rancher/dolly/pkg/table/types"
 	cli "github.com/rancher/wrangler-cli"
-	"github.com/rancher/wrangler/pkg/kv"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,6 +16,8 @@ import (
 func NewRmCommand() *cobra.Command {
 	rm := cli.Command(&Rm{}, cobra.Command{
 		Short: "remove resources",
+		Long: `Rm deletes the workloads of services, such as dolly rm api, workloads as deploy/api, sts/db, ds/agent, job/migrate or
+cronjob/backup, and pods.`,
 	})
 	return rm
 }
@@ -21,21 +27,48 @@ type Rm struct {
 }
 
 func (r *Rm) Run(cmd *cobra.Command, args []string) error {
+	services := dollyfile.DefaultServices()
 	for _, arg := range args {
-		t, resourceName := kv.Split(arg, "/")
-		if t == types.DeploymentType {
-			if err := K8sInterface.AppsV1().Deployments(r.Namespace).Delete(cmd.Context(), resourceName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-				return err
-			}
-		} else if t == types.DaemonSetType {
-			if err := K8sInterface.AppsV1().DaemonSets(r.Namespace).Delete(cmd.Context(), resourceName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-				return err
-			}
-		} else if t == types.PodType {
-			if err := K8sInterface.CoreV1().Pods(r.Namespace).Delete(cmd.Context(), resourceName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		target, err := resolve.Resolve(cmd.Context(), K8sInterface, services, r.Namespace, arg)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		kinds := []string{target.Type}
+		if target.Type == types.ServiceType {
+			// a service is deployed as one of the workloads named after it
+			kinds = []string{types.DeploymentType, types.StatefulSetType, types.DaemonSetType, types.JobType}
+		}
+		for _, kind := range kinds {
+			if err := r.delete(cmd.Context(), target.Namespace, kind, target.Name); err != nil && !errors.IsNotFound(err) {
 				return err
 			}
 		}
 	}
 	return nil
 }
+
+func (r *Rm) delete(ctx context.Context, namespace, kind, name string) error {
+	// delete the pods of jobs too, they are orphaned by default
+	propagation := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	}
+	switch kind {
+	case types.DeploymentType:
+		return K8sInterface.AppsV1().Deployments(namespace).Delete(ctx, name, opts)
+	case types.StatefulSetType:
+		return K8sInterface.AppsV1().StatefulSets(namespace).Delete(ctx, name, opts)
+	case types.DaemonSetType:
+		return K8sInterface.AppsV1().DaemonSets(namespace).Delete(ctx, name, opts)
+	case types.JobType:
+		return K8sInterface.BatchV1().Jobs(namespace).Delete(ctx, name, opts)
+	case types.CronJobType:
+		return K8sInterface.BatchV1beta1().CronJobs(namespace).Delete(ctx, name, opts)
+	case types.PodType:
+		return K8sInterface.CoreV1().Pods(namespace).Delete(ctx, name, opts)
+	}
+	return fmt.Errorf("unsupported type %s, use a service name or one of pod, deploy, sts, ds, job or cronjob", kind)
+}
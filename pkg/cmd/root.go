@@ -30,11 +30,28 @@ func New() *cobra.Command {
 		NewPushCommand(),
 		NewPsCommand(),
 		NewExecCommand(),
+		NewAttachCommand(),
+		NewCpCommand(),
 		NewKillCommand(),
 		NewRmCommand(),
 		NewLogCommand(),
 		NewConfigCommand(),
 		NewSecretCommand(),
+		NewExportCommand(),
+		NewFmtCommand(),
+		NewValidateCommand(),
+		NewSchemaCommand(),
+		NewLintCommand(),
+		NewInitCommand(),
+		NewRunCommand(),
+		NewScaleCommand(),
+		NewRestartCommand(),
+		NewStopCommand(),
+		NewStartCommand(),
+		NewDescribeCommand(),
+		NewEventsCommand(),
+		NewUiCommand(),
+		NewPortForwardCommand(),
 	)
 	return root
 }
@@ -63,3 +80,10 @@ func (a *Dolly) PersistentPre(cmd *cobra.Command, args []string) error {
 	Apply = apply.New(k8s.Discovery(), apply.NewClientFactory(config)).WithRateLimiting(20.0).WithSetID("dolly")
 	return nil
 }
+
+// stringSliceFlag returns every value of a slice flag, wrangler-cli keeps only half of the values of slice flags
+// given more than once
+func stringSliceFlag(cmd *cobra.Command, name string) []string {
+	values, _ := cmd.Flags().GetStringSlice(name)
+	return values
+}
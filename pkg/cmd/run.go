@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/remote"
+	"github.com/rancher/dolly/pkg/template"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/convert/deployment"
+	"github.com/rancher/dolly/pkg/types/convert/labels"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/exec"
+)
+
+// runLabel marks the pods of dolly run with the name of their service
+const runLabel = "dolly.rancher.io/run"
+
+// waitingReasons are the reasons of waiting containers that won't start without a change
+var waitingReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+func NewRunCommand() *cobra.Command {
+	runCommand := cli.Command(&Run{}, cobra.Command{
+		Short: "Run a one-off command with the definition of a service",
+		Long: `Run starts a pod with the image, env, configs, secrets, volumes and service account of a service of the dollyfile and runs a
+command in it, such as dolly run api -- ./manage.py migrate. Like docker compose run, the command replaces the args of the container
+and --entrypoint its command. The logs are streamed, or the pod is attached to with -it, and the pod is deleted when the command
+exits. The configs, secrets and service account are the ones applied by dolly up.`,
+	})
+	// flags of the command, such as ls -l, are not flags of run
+	runCommand.Flags().SetInterspersed(false)
+	return runCommand
+}
+
+type Run struct {
+	File       string   `name:"file" usage:"Path to dollyfile, can point to local file path, https links or stdin(-)" default:"DollyFile" short:"f"`
+	AnswerFile string   `name:"answer-file" usage:"Answer file set for dollyfile" default:"DollyFile-answers" short:"a"`
+	Namespace  string   `name:"namespace" usage:"Namespace to run in" default:"default" short:"n"`
+	Entrypoint string   `name:"entrypoint" usage:"Override the command of the container"`
+	Env        []string `name:"env" usage:"Set an environment variable as KEY=VALUE, can be given more than once" short:"e"`
+	Job        bool     `name:"job" usage:"Run the pod as a Job"`
+	Keep       bool     `name:"keep" usage:"Keep the pod after the command exits"`
+	Build      bool     `name:"build" usage:"Build the images of the dollyfile before running"`
+	Stdin      bool     `name:"stdin" usage:"Pass standard input to the command" short:"i"`
+	Tty        bool     `name:"tty" usage:"Allocate a terminal" short:"t"`
+}
+
+func (r *Run) Run(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("a service is required")
+	}
+	r.Env = stringSliceFlag(cmd, "env")
+
+	content, answers, err := dollyfile.LoadFileAndAnswer(r.File, r.AnswerFile)
+	if err != nil {
+		return err
+	}
+	rf, err := dollyfile.Parse(content, r.Namespace, template.AnswersFromMap(answers))
+	if err != nil {
+		return err
+	}
+	if _, ok := rf.Services[args[0]]; !ok {
+		return fmt.Errorf("service %s not found in %s", args[0], r.File)
+	}
+	if r.Build {
+		if err := rf.Build(false); err != nil {
+			return err
+		}
+	}
+
+	pod, err := r.pod(rf.Services[args[0]], rf.DefaultResources, args[1:])
+	if err != nil {
+		return err
+	}
+
+	name, cleanup, err := r.create(cmd.Context(), pod)
+	if err != nil {
+		return err
+	}
+	err = r.run(cmd.Context(), pod.Namespace, name, args[0])
+	if r.Keep {
+		logrus.Infof("Keeping pod %s", name)
+	} else if cleanupErr := cleanup(); cleanupErr != nil {
+		logrus.Warnf("Failed to delete pod %s: %v", name, cleanupErr)
+	}
+	exitWithCode(err)
+	return err
+}
+
+// pod returns the pod running the command in the container of the service. Sidecars are left out, they would keep the pod
+// running after the command exits.
+func (r *Run) pod(service types.Service, defaults *types.DefaultResources, args []string) (*v1.Pod, error) {
+	if service.Spec.Image == "" {
+		return nil, fmt.Errorf("service %s has no image, build it with --build", service.Name)
+	}
+
+	podTemplate := deployment.PopulatePodTemplate(service, defaults)
+	var container *v1.Container
+	for i, c := range podTemplate.Spec.Containers {
+		if c.Name == service.Name {
+			container = &podTemplate.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		return nil, fmt.Errorf("service %s has no container of its own to run the command in", service.Name)
+	}
+
+	if len(args) > 0 {
+		container.Args = args
+	}
+	if r.Entrypoint != "" {
+		command, err := shellwords.Parse(r.Entrypoint)
+		if err != nil {
+			return nil, err
+		}
+		container.Command = command
+	}
+	for _, env := range r.Env {
+		name, value := kv.Split(env, "=")
+		container.Env = setEnv(container.Env, name, value)
+	}
+	// the command doesn't serve the probes of the service
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.StartupProbe = nil
+	container.Stdin = r.Stdin
+	container.StdinOnce = r.Stdin
+	container.TTY = r.Tty
+
+	spec := podTemplate.Spec
+	spec.Containers = []v1.Container{*container}
+	spec.RestartPolicy = v1.RestartPolicyNever
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: service.Name + "-run-",
+			Namespace:    service.Namespace,
+			// without the selector labels of the service, so the pod gets no traffic of the service
+			Labels: labels.Merge(podTemplate.Labels, map[string]string{
+				runLabel: service.Name,
+			}),
+			Annotations: podTemplate.Annotations,
+		},
+		Spec: spec,
+	}, nil
+}
+
+func setEnv(envs []v1.EnvVar, name, value string) []v1.EnvVar {
+	for i, env := range envs {
+		if env.Name == name {
+			envs[i] = v1.EnvVar{
+				Name:  name,
+				Value: value,
+			}
+			return envs
+		}
+	}
+	return append(envs, v1.EnvVar{
+		Name:  name,
+		Value: value,
+	})
+}
+
+// create creates the pod, or a Job of the pod, and returns the name of the pod and a func deleting what was created
+func (r *Run) create(ctx context.Context, pod *v1.Pod) (string, func() error, error) {
+	propagation := metav1.DeletePropagationBackground
+	deleteOptions := metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	}
+
+	if !r.Job {
+		created, err := K8sInterface.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return created.Name, func() error {
+			return K8sInterface.CoreV1().Pods(created.Namespace).Delete(context.Background(), created.Name, deleteOptions)
+		}, nil
+	}
+
+	backoffLimit := int32(0)
+	job, err := K8sInterface.BatchV1().Jobs(pod.Namespace).Create(ctx, &batchv1.Job{
+		ObjectMeta: pod.ObjectMeta,
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      pod.Labels,
+					Annotations: pod.Annotations,
+				},
+				Spec: pod.Spec,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() error {
+		return K8sInterface.BatchV1().Jobs(job.Namespace).Delete(context.Background(), job.Name, deleteOptions)
+	}
+
+	name, err := waitForJobPod(ctx, job)
+	if err != nil {
+		if cleanupErr := cleanup(); cleanupErr != nil {
+			logrus.Warnf("Failed to delete job %s: %v", job.Name, cleanupErr)
+		}
+		return "", nil, err
+	}
+	return name, cleanup, nil
+}
+
+func waitForJobPod(ctx context.Context, job *batchv1.Job) (name string, err error) {
+	selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	err = poll(ctx, func() (bool, error) {
+		pods, err := K8sInterface.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+		})
+		if err != nil || len(pods.Items) == 0 {
+			return false, err
+		}
+		name = pods.Items[0].Name
+		return true, nil
+	})
+	return
+}
+
+// run waits for the container to start, attaches to it or streams its logs and returns the exit error of the command
+func (r *Run) run(ctx context.Context, namespace, name, container string) error {
+	if err := r.waitForContainer(ctx, namespace, name, container, false); err != nil {
+		return err
+	}
+
+	if r.Stdin || r.Tty {
+		err := remote.Attach(ctx, RestConfig, K8sInterface, remote.Option{
+			Namespace: namespace,
+			Pod:       name,
+			Container: container,
+			Stdin:     r.Stdin,
+			TTY:       r.Tty,
+			In:        os.Stdin,
+			Out:       os.Stdout,
+			ErrOut:    os.Stderr,
+		})
+		if _, exited := err.(exec.ExitError); err != nil && !exited {
+			return err
+		}
+	} else {
+		stream, err := K8sInterface.CoreV1().Pods(namespace).GetLogs(name, &v1.PodLogOptions{
+			Container: container,
+			Follow:    true,
+		}).Stream(ctx)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+		if _, err := io.Copy(os.Stdout, stream); err != nil {
+			return err
+		}
+	}
+
+	return r.waitForContainer(ctx, namespace, name, container, true)
+}
+
+// waitForContainer waits for the container to start, or to terminate if terminated is set. A container that can't start
+// and a command exiting with a non zero code are errors.
+func (r *Run) waitForContainer(ctx context.Context, namespace, name, container string, terminated bool) error {
+	return poll(ctx, func() (bool, error) {
+		pod, err := K8sInterface.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != container {
+				continue
+			}
+			state := status.State
+			switch {
+			case state.Waiting != nil && waitingReasons[state.Waiting.Reason]:
+				return false, fmt.Errorf("pod %s can't start: %s: %s", name, state.Waiting.Reason, state.Waiting.Message)
+			case state.Terminated != nil && terminated && state.Terminated.ExitCode != 0:
+				return false, exec.CodeExitError{
+					Err:  fmt.Errorf("command terminated with exit code %d", state.Terminated.ExitCode),
+					Code: int(state.Terminated.ExitCode),
+				}
+			case state.Terminated != nil:
+				return true, nil
+			case state.Running != nil:
+				return !terminated, nil
+			}
+		}
+		if pod.Status.Phase == v1.PodFailed {
+			return false, fmt.Errorf("pod %s failed: %s", name, pod.Status.Message)
+		}
+		return false, nil
+	})
+}
+
+// poll checks the condition every second until it is done, fails or the context is cancelled
+func poll(ctx context.Context, condition wait.ConditionFunc) error {
+	err := wait.PollImmediateUntil(time.Second, condition, ctx.Done())
+	if err == wait.ErrWaitTimeout && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
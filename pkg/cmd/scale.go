@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/lifecycle"
+	"github.com/rancher/dolly/pkg/resolve"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func NewScaleCommand() *cobra.Command {
+	return cli.Command(&Scale{}, cobra.Command{
+		Short: "Set the replicas of services",
+		Long: `Scale sets the replicas of services, such as dolly scale api=3 worker=5. A service is a service of the dollyfile or a
+workload as deploy/api or sts/db.`,
+	})
+}
+
+type Scale struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+}
+
+func (s *Scale) Run(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("require at least one service=replicas")
+	}
+
+	services := dollyfile.DefaultServices()
+	for _, arg := range args {
+		name, count := kv.Split(arg, "=")
+		replicas, err := strconv.ParseInt(count, 10, 32)
+		if err != nil || replicas < 0 {
+			return fmt.Errorf("invalid %s, expected service=replicas such as api=3", arg)
+		}
+
+		target, err := resolve.Resolve(cmd.Context(), K8sInterface, services, s.Namespace, name)
+		if err != nil {
+			return err
+		}
+		workloads, err := lifecycle.Workloads(cmd.Context(), K8sInterface, target)
+		if err != nil {
+			return err
+		}
+		for _, w := range workloads {
+			if err := lifecycle.Scale(cmd.Context(), K8sInterface, w.Namespace, w.Kind, w.Name, int32(replicas)); err != nil {
+				return err
+			}
+			logrus.Infof("Scaled %s to %d", w, replicas)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewSchemaCommand() *cobra.Command {
+	schema := cli.Command(&Schema{}, cobra.Command{
+		Short: "Print the JSON Schema of dollyfiles for editor completion",
+	})
+	return schema
+}
+
+type Schema struct {
+	Output string `name:"output" usage:"File to write the schema to, defaults to stdout" short:"o"`
+}
+
+func (s *Schema) Run(cmd *cobra.Command, args []string) error {
+	content, err := dollyfile.JSONSchema()
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+
+	if s.Output == "" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+	return ioutil.WriteFile(s.Output, content, 0644)
+}
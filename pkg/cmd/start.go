@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/rancher/dolly/pkg/lifecycle"
+	"github.com/rancher/dolly/pkg/table/types"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func NewStartCommand() *cobra.Command {
+	return cli.Command(&Start{}, cobra.Command{
+		Short: "Scale services stopped by stop back to their replicas",
+		Long: `Start restores the replicas services had before dolly stop, such as dolly start api. Without services the whole dollyfile
+is started.`,
+	})
+}
+
+type Start struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	File      string `name:"file" usage:"Dollyfile of the services to start when none is given" default:"DollyFile" short:"f"`
+}
+
+func (s *Start) Run(cmd *cobra.Command, args []string) error {
+	workloads, err := projectWorkloads(cmd.Context(), s.Namespace, s.File, args)
+	if err != nil {
+		return err
+	}
+	for _, w := range workloads {
+		if w.Kind == types.DaemonSetType && len(args) == 0 {
+			continue
+		}
+		replicas, err := lifecycle.Start(cmd.Context(), K8sInterface, w.Namespace, w.Kind, w.Name)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("Started %s with %d replicas", w, replicas)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/rancher/dolly/pkg/lifecycle"
+	"github.com/rancher/dolly/pkg/table/types"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func NewStopCommand() *cobra.Command {
+	return cli.Command(&Stop{}, cobra.Command{
+		Short: "Scale services to zero, remembering their replicas for start",
+		Long: `Stop scales services to zero, such as dolly stop api, and keeps their replicas in an annotation so dolly start brings them
+back. Without services the whole dollyfile is stopped, except its daemonsets which run on every node.`,
+	})
+}
+
+type Stop struct {
+	Namespace string `name:"namespace" usage:"specify namespace" default:"default" short:"n"`
+	File      string `name:"file" usage:"Dollyfile of the services to stop when none is given" default:"DollyFile" short:"f"`
+}
+
+func (s *Stop) Run(cmd *cobra.Command, args []string) error {
+	workloads, err := projectWorkloads(cmd.Context(), s.Namespace, s.File, args)
+	if err != nil {
+		return err
+	}
+	for _, w := range workloads {
+		if w.Kind == types.DaemonSetType && len(args) == 0 {
+			logrus.Infof("Skipping %s, it runs on every node", w)
+			continue
+		}
+		replicas, err := lifecycle.Stop(cmd.Context(), K8sInterface, w.Namespace, w.Kind, w.Name)
+		if err != nil {
+			return err
+		}
+		if replicas == 0 {
+			logrus.Infof("%s is stopped already", w)
+		} else {
+			logrus.Infof("Stopped %s, it had %d replicas", w, replicas)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/rancher/dolly/pkg/remote"
+	"github.com/rancher/dolly/pkg/ui"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/exec"
+)
+
+// shell prefers bash and falls back to sh, the only shell of most images
+var shell = []string{"sh", "-c", "command -v bash >/dev/null && exec bash || exec sh"}
+
+func NewUiCommand() *cobra.Command {
+	return cli.Command(&Ui{}, cobra.Command{
+		Short: "Show a live dashboard of the services in the terminal",
+		Long: `Ui shows the services of the dollyfile of the current directory and their pods with their live status, the logs or the
+recent events of the selected one, and opens a shell in it, restarts it or scales it. Keys: up/down or k/j select, l shows the
+logs, e the events, x opens a shell, r restarts, + and - scale and q quits. -a shows every workload of the namespace.`,
+	})
+}
+
+type Ui struct {
+	All       bool   `name:"all" usage:"Show every workload of the namespace, not only the ones of the dollyfile" short:"a"`
+	Namespace string `name:"namespace" usage:"Namespace of the services" default:"default" short:"n"`
+	File      string `name:"file" usage:"Only show the services and manifests of a dollyfile, defaults to the DollyFile of the current directory" short:"f"`
+}
+
+func (u *Ui) Run(cmd *cobra.Command, args []string) error {
+	file := u.File
+	if file == "" {
+		if _, err := os.Stat("DollyFile"); err == nil {
+			file = "DollyFile"
+		}
+	}
+	var filter map[string]bool
+	if file != "" && !u.All {
+		var err error
+		if filter, err = dollyfileWorkloads(file, u.Namespace); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	app := ui.New(K8sInterface, u.Namespace, filter)
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	return ui.Run(ctx, app, os.Stdin, os.Stdout, func(ctx context.Context, namespace, pod string, terminal *os.File, in io.Reader) error {
+		err := remote.Exec(ctx, RestConfig, K8sInterface, remote.Option{
+			Namespace: namespace,
+			Pod:       pod,
+			Command:   shell,
+			Stdin:     true,
+			TTY:       true,
+			In:        in,
+			Out:       os.Stdout,
+			ErrOut:    os.Stderr,
+			Terminal:  terminal,
+		})
+		// the exit code of the shell is the one of its last command
+		if _, exited := err.(exec.ExitError); exited {
+			return nil
+		}
+		return err
+	})
+}
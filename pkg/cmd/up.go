@@ -6,7 +6,6 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -14,11 +13,17 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/ingresscontroller"
 	"github.com/rancher/dolly/pkg/log"
 	"github.com/rancher/dolly/pkg/portforward"
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
 	"github.com/rancher/dolly/pkg/template"
+	dollytypes "github.com/rancher/dolly/pkg/types"
 	"github.com/rancher/dolly/pkg/types/convert/deployment"
+	"github.com/rancher/dolly/pkg/types/convert/disruption"
 	"github.com/rancher/dolly/pkg/types/convert/ingress"
+	"github.com/rancher/dolly/pkg/types/convert/networkpolicy"
 	"github.com/rancher/dolly/pkg/types/convert/rbac"
 	"github.com/rancher/dolly/pkg/types/convert/service"
 	"github.com/rancher/dolly/pkg/types/convert/volume"
@@ -31,7 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/dynamic"
 )
 
 func NewUpCommand() *cobra.Command {
@@ -47,6 +52,7 @@ type Up struct {
 	NoWatch    bool   `name:"no-watch" usage:"Whether to watch dollyfile and apply changes"`
 	Namespace  string `name:"namespace" u2sage:"Namespace to install" default:"default" short:"n"`
 	AnswerFile string `name:"answer-file" usage:"Answer file set for dollyfile" default:"DollyFile-answers" short:"a"`
+	Ingress    string `name:"ingress" usage:"Ingress controller exposing the hostnames of the services: traefik, nginx, contour, gateway or none. Detected if not set"`
 }
 
 func (u *Up) Run(cmd *cobra.Command, args []string) error {
@@ -59,22 +65,61 @@ func (u *Up) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	controller, err := u.detectIngress(cmd.Context(), rf)
+	if err != nil {
+		return err
+	}
+
 	if err := u.do(rf); err != nil {
 		return err
 	}
 
 	if !u.NoExpose {
-		go u.portForward(cmd.Context(), rf)
+		go u.portForward(cmd.Context(), rf, controller)
 	}
 
 	if !u.NoWatch {
-		ctx, _ := context.WithCancel(cmd.Context())
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
 		go u.Watch(ctx, rf)
 	}
 
 	return u.Log(cmd.Context(), rf)
 }
 
+// detectIngress finds the ingress controller exposing the hostnames of the services and sets the dollyfile up for it. The
+// services aren't exposed by hostname if no controller is found, unless the dollyfile or --ingress names one.
+func (u *Up) detectIngress(ctx context.Context, rf *dollyfile.DollyFile) (*ingresscontroller.Controller, error) {
+	config := dollytypes.Ingress{}
+	if rf.Ingress != nil {
+		config = *rf.Ingress
+	}
+	if u.Ingress == "ingress-nginx" {
+		u.Ingress = string(dollytypes.IngressProviderNginx)
+	}
+	if u.Ingress != "" {
+		config.Provider = dollytypes.IngressProvider(u.Ingress)
+	}
+	rf.Ingress = &config
+
+	dynamicClient, err := dynamic.NewForConfig(RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	controller, err := ingresscontroller.Detect(ctx, K8sInterface, dynamicClient, u.Namespace, &config)
+	if err != nil && config.Provider != "" {
+		return nil, err
+	} else if err != nil {
+		logrus.Warnf("The services are not exposed by hostname: %v", err)
+		return nil, nil
+	}
+	if controller != nil {
+		config.Provider = controller.Provider.Name
+		config.Gateway = controller.Gateway
+	}
+	return controller, nil
+}
+
 func (u *Up) setupRDNS(ctx context.Context) (err error) {
 	RdnsDomain, err = rdns.GetDomain(ctx, K8sInterface)
 	return err
@@ -85,6 +130,7 @@ func (u *Up) Watch(ctx context.Context, rf *dollyfile.DollyFile) {
 	if rf.NeedBuild() {
 		toWatch = filepath.Dir(u.File)
 	}
+	detected := rf.Ingress
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		logrus.Error(err)
@@ -96,12 +142,14 @@ func (u *Up) Watch(ctx context.Context, rf *dollyfile.DollyFile) {
 			// watch for events
 			case event := <-watcher.Events:
 				if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Rename == fsnotify.Rename {
-					rf, err = u.parseDollyFile()
-					if err != nil {
+					if changed, err := u.parseDollyFile(); err != nil {
 						logrus.Errorf("Failed to parse dollyfile, error: %v", err)
-					}
-					if err := u.do(rf); err != nil {
-						logrus.Errorf("Failed to apply dollyfile, error: %v", err)
+					} else {
+						// the ingress controller is the one detected when dolly up started
+						changed.Ingress = detected
+						if err := u.do(changed); err != nil {
+							logrus.Errorf("Failed to apply dollyfile, error: %v", err)
+						}
 					}
 				}
 				if err := watcher.Add(event.Name); err != nil {
@@ -143,14 +191,21 @@ func (u *Up) parseDollyFile() (*dollyfile.DollyFile, error) {
 		rf.Services[k] = svc
 	}
 
-	rf.Plugins = []dollyfile.Plugin{
+	rf.Plugins = plugins()
+	return rf, nil
+}
+
+// plugins are the converters rendering the services of a dollyfile into Kubernetes objects
+func plugins() []dollyfile.Plugin {
+	return []dollyfile.Plugin{
 		deployment.Plugin{},
 		service.Plugin{},
 		rbac.Plugin{},
 		volume.Plugin{},
 		ingress.Plugin{},
+		disruption.Plugin{},
+		networkpolicy.Plugin{},
 	}
-	return rf, nil
 }
 
 func (u *Up) do(rf *dollyfile.DollyFile) error {
@@ -166,49 +221,50 @@ func (u *Up) do(rf *dollyfile.DollyFile) error {
 	return Apply.WithDynamicLookup().WithDefaultNamespace(u.Namespace).ApplyObjects(objects...)
 }
 
-func (u *Up) portForward(ctx context.Context, rf *dollyfile.DollyFile) {
-	for _, svc := range rf.Services {
-		fmt.Printf("http://%s-%s.%s:9080 ----> %s\n", svc.Name, svc.Namespace, RdnsDomain, svc.Name)
-	}
-
-	pod, err := u.waitForIngressPod()
+// portForward forwards the ports of the services to localhost, and the ingress controller to reach the services by their
+// hostnames
+func (u *Up) portForward(ctx context.Context, rf *dollyfile.DollyFile, controller *ingresscontroller.Controller) {
+	forwards, err := serviceForwards(ctx, rf.Services, u.Namespace, serviceNames(rf.Services))
 	if err != nil {
+		logrus.Errorf("Failed to forward the ports of the services, error: %v", err)
 		return
 	}
-	pfOption := portforward.Option{
-		Pod:        pod,
-		Port:       strconv.Itoa(9080),
-		TargetPort: strconv.Itoa(80),
-		Stdout:     Debug == true,
-		ReadyChan:  make(chan struct{}, 1),
-		StopChan:   make(chan struct{}, 1),
+	var hostnames *portforward.Forward
+	if controller != nil {
+		hostnames = ingressForward(controller)
+		forwards = append(forwards, hostnames)
 	}
 
-	k8s := kubernetes.NewForConfigOrDie(RestConfig)
-	if err := portforward.PortForward(RestConfig, k8s, pfOption); err != nil {
-		logrus.Errorf("Failed to setup port-forward for ingress pod, error: %v", err)
+	if err := portforward.PickPorts(forwards); err != nil {
+		logrus.Errorf("Failed to forward the ports of the services, error: %v", err)
+		return
 	}
-
-	return
-}
-
-func (u *Up) waitForIngressPod() (v1.Pod, error) {
-	// this code assumes traefik ingress in k3s
-	for {
-		pods, err := K8sInterface.CoreV1().Pods("kube-system").List(context.Background(), metav1.ListOptions{
-			LabelSelector: "app=traefik",
-			Limit:         1,
-		})
-		if err != nil {
-			return v1.Pod{}, err
+	portforward.Print(os.Stdout, forwards)
+	if hostnames != nil {
+		for _, name := range serviceNames(rf.Services) {
+			svc := rf.Services[name]
+			fmt.Printf("http://%s-%s.%s:%d ----> %s\n", svc.Name, svc.Namespace, RdnsDomain, hostnames.LocalPort, svc.Name)
 		}
+	}
 
-		if len(pods.Items) == 0 {
-			time.Sleep(time.Second * 1)
-			continue
-		}
+	portforward.Run(ctx, RestConfig, K8sInterface, forwards)
+}
 
-		return pods.Items[0], nil
+// ingressForward forwards local port 9080, or a free one, to the HTTP port of the ingress controller
+func ingressForward(controller *ingresscontroller.Controller) *portforward.Forward {
+	return &portforward.Forward{
+		Target: resolve.Target{
+			Namespace: controller.Namespace,
+			Name:      string(controller.Provider.Name),
+			Type:      types.ServiceType,
+			Selector:  controller.Selector,
+		},
+		Port: v1.ServicePort{
+			Name:       "http",
+			Port:       80,
+			TargetPort: controller.Port,
+		},
+		LocalPort: 9080,
 	}
 }
 
@@ -226,7 +282,7 @@ func (u *Up) Log(ctx context.Context, rf *dollyfile.DollyFile) error {
 		return err
 	}
 
-	template, err := log.Format("", false)
+	template, err := log.Format("", false, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/template"
+	cli "github.com/rancher/wrangler-cli"
+	"github.com/spf13/cobra"
+)
+
+func NewValidateCommand() *cobra.Command {
+	validate := cli.Command(&Validate{}, cobra.Command{
+		Short: "Validate a dollyfile without applying it",
+		Long: `Validate checks the dollyfile against the schema and reports unknown keys, invalid values, configs that are not defined,
+routes pointing at unknown services and duplicate ports, each with the line and column it was found at.`,
+	})
+	return validate
+}
+
+type Validate struct {
+	File       string `name:"file" usage:"Path to dollyfile, can point to local file path, https links or stdin(-)" default:"DollyFile" short:"f"`
+	AnswerFile string `name:"answer-file" usage:"Answer file set for dollyfile" default:"DollyFile-answers" short:"a"`
+}
+
+func (v *Validate) Run(cmd *cobra.Command, args []string) error {
+	content, answers, err := dollyfile.LoadFileAndAnswer(v.File, v.AnswerFile)
+	if err != nil {
+		return err
+	}
+
+	problems, err := dollyfile.Check(content, template.AnswersFromMap(answers))
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range problems {
+		fmt.Printf("%s:%s\n", v.File, problem)
+	}
+	if dollyfile.HasErrors(problems) {
+		return fmt.Errorf("%s is not valid", v.File)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const header = `// Code generated by pkg/codegen. DO NOT EDIT.
+
+package %s
+
+// The SwaggerDoc methods return the doc comments of the types and their fields by json name, the same as the Kubernetes
+// types do. They are the descriptions of the JSON Schema of the Dollyfile.
+`
+
+// docs are the files whose doc comments describe the fields of a Dollyfile, by the file they are generated to
+var docs = map[string][]string{
+	"pkg/types/zz_generated_swagger_doc.go": {
+		"pkg/types/service_type.go",
+		"pkg/types/route_types.go",
+		"pkg/types/rbac.go",
+	},
+	"pkg/dollyfile/zz_generated_swagger_doc.go": {
+		"pkg/dollyfile/riofile.go",
+	},
+}
+
+func main() {
+	for output, sources := range docs {
+		buf := &bytes.Buffer{}
+		fmt.Fprintf(buf, header, filepath.Base(filepath.Dir(output)))
+
+		for _, source := range sources {
+			if err := writeSwaggerDocs(buf, source); err != nil {
+				logrus.Fatal(err)
+			}
+		}
+
+		content, err := format.Source(buf.Bytes())
+		if err != nil {
+			logrus.Fatalf("failed to format %s: %v", output, err)
+		}
+		if err := ioutil.WriteFile(output, content, 0644); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+}
+
+// writeSwaggerDocs writes a SwaggerDoc method for every documented struct of the file
+func writeSwaggerDocs(buf *bytes.Buffer, source string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), source, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = gen.Doc
+			}
+
+			var lines []string
+			if text := docText(doc); text != "" {
+				lines = append(lines, fmt.Sprintf("%q: %s,", "", strconv.Quote(text)))
+			}
+			for _, field := range structType.Fields.List {
+				name := jsonName(field)
+				if text := docText(field.Doc); name != "" && text != "" {
+					lines = append(lines, fmt.Sprintf("%q: %s,", name, strconv.Quote(text)))
+				}
+			}
+			if len(lines) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(buf, "\nfunc (%s) SwaggerDoc() map[string]string {\nreturn map[string]string{\n%s\n}\n}\n",
+				typeSpec.Name.Name, strings.Join(lines, "\n"))
+		}
+	}
+	return nil
+}
+
+// jsonName returns the json name of a field, or "" for embedded and inlined fields
+func jsonName(field *ast.Field) string {
+	if len(field.Names) == 0 || !field.Names[0].IsExported() {
+		return ""
+	}
+
+	tag := ""
+	if field.Tag != nil {
+		tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+	}
+	if strings.Contains(tag, ",inline") {
+		return ""
+	}
+	name := strings.Split(tag, ",")[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Names[0].Name
+	}
+	return name
+}
+
+// docText joins the lines of a comment, leaving out the TODOs and +markers
+func docText(doc *ast.CommentGroup) string {
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "TODO") || strings.HasPrefix(line, "+") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
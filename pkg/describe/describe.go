@@ -0,0 +1,303 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/table/types"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// revisionAnnotation is the revision of the rollout of a deployment a replicaset belongs to
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// imagePullReasons are the reasons of containers waiting for an image that can't be pulled
+var imagePullReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// Report holds a workload, or a pod, and the objects around it
+type Report struct {
+	Namespace string
+	Kind      string
+	Name      string
+	// Workload is nil for a pod
+	Workload    runtime.Object
+	ReplicaSets []*appsv1.ReplicaSet
+	Pods        []*v1.Pod
+	Services    []*v1.Service
+	// Endpoints of the services by name, missing if the service has none
+	Endpoints map[string]*v1.Endpoints
+	Ingresses []*networkingv1.Ingress
+	Claims    []*v1.PersistentVolumeClaim
+	// MissingClaims are the claims used by the pods that don't exist
+	MissingClaims []string
+	Events        []*v1.Event
+}
+
+// Gather gets a deployment, statefulset, daemonset or pod and the objects around it
+func Gather(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string) (*Report, error) {
+	r := &Report{
+		Namespace: namespace,
+		Kind:      kind,
+		Name:      name,
+		Endpoints: map[string]*v1.Endpoints{},
+	}
+
+	var (
+		selector *metav1.LabelSelector
+		template *v1.PodTemplateSpec
+		err      error
+	)
+	switch kind {
+	case types.DeploymentType:
+		obj, getErr := k8s.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			r.Workload, selector, template = obj, obj.Spec.Selector, &obj.Spec.Template
+		}
+	case types.StatefulSetType:
+		obj, getErr := k8s.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			r.Workload, selector, template = obj, obj.Spec.Selector, &obj.Spec.Template
+		}
+	case types.DaemonSetType:
+		obj, getErr := k8s.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			r.Workload, selector, template = obj, obj.Spec.Selector, &obj.Spec.Template
+		}
+	case types.PodType:
+		obj, getErr := k8s.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			r.Pods = []*v1.Pod{obj}
+			template = &v1.PodTemplateSpec{
+				ObjectMeta: obj.ObjectMeta,
+				Spec:       obj.Spec,
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s/%s is not a deployment, statefulset, daemonset or pod", kind, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if selector != nil {
+		if err := r.gatherPods(ctx, k8s, selector); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.gatherServices(ctx, k8s, template.Labels); err != nil {
+		return nil, err
+	}
+	if err := r.gatherClaims(ctx, k8s, template); err != nil {
+		return nil, err
+	}
+	return r, r.gatherEvents(ctx, k8s)
+}
+
+func (r *Report) gatherPods(ctx context.Context, k8s kubernetes.Interface, labelSelector *metav1.LabelSelector) error {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return err
+	}
+	options := metav1.ListOptions{
+		LabelSelector: selector.String(),
+	}
+
+	pods, err := k8s.CoreV1().Pods(r.Namespace).List(ctx, options)
+	if err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		r.Pods = append(r.Pods, &pods.Items[i])
+	}
+	sort.Slice(r.Pods, func(i, j int) bool {
+		return r.Pods[i].Name < r.Pods[j].Name
+	})
+
+	if r.Kind != types.DeploymentType {
+		return nil
+	}
+	owner, err := meta.Accessor(r.Workload)
+	if err != nil {
+		return err
+	}
+	replicaSets, err := k8s.AppsV1().ReplicaSets(r.Namespace).List(ctx, options)
+	if err != nil {
+		return err
+	}
+	for i, rs := range replicaSets.Items {
+		if controller := metav1.GetControllerOf(&rs); controller != nil && controller.UID == owner.GetUID() {
+			r.ReplicaSets = append(r.ReplicaSets, &replicaSets.Items[i])
+		}
+	}
+	// the most recent revision first
+	sort.Slice(r.ReplicaSets, func(i, j int) bool {
+		return revision(r.ReplicaSets[i]) > revision(r.ReplicaSets[j])
+	})
+	return nil
+}
+
+func revision(rs *appsv1.ReplicaSet) int {
+	n, _ := strconv.Atoi(rs.Annotations[revisionAnnotation])
+	return n
+}
+
+// gatherServices gets the services selecting the pods, their endpoints and the ingresses routing to them
+func (r *Report) gatherServices(ctx context.Context, k8s kubernetes.Interface, podLabels map[string]string) error {
+	services, err := k8s.CoreV1().Services(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for i, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(podLabels)) {
+			continue
+		}
+		r.Services = append(r.Services, &services.Items[i])
+		names[svc.Name] = true
+
+		endpoints, err := k8s.CoreV1().Endpoints(r.Namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		r.Endpoints[svc.Name] = endpoints
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	ingresses, err := k8s.NetworkingV1().Ingresses(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range ingresses.Items {
+		for _, backend := range Backends(&ingresses.Items[i]) {
+			if names[backend.Name] {
+				r.Ingresses = append(r.Ingresses, &ingresses.Items[i])
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Backends returns the services an ingress routes to
+func Backends(ingress *networkingv1.Ingress) []*networkingv1.IngressServiceBackend {
+	var result []*networkingv1.IngressServiceBackend
+	if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+		result = append(result, backend.Service)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				result = append(result, path.Backend.Service)
+			}
+		}
+	}
+	return result
+}
+
+// gatherClaims gets the claims of the volumes of the pod template and the pods, the pods of a statefulset have a claim
+// each
+func (r *Report) gatherClaims(ctx context.Context, k8s kubernetes.Interface, template *v1.PodTemplateSpec) error {
+	var names []string
+	seen := map[string]bool{}
+	add := func(volumes []v1.Volume) {
+		for _, volume := range volumes {
+			if claim := volume.PersistentVolumeClaim; claim != nil && !seen[claim.ClaimName] {
+				seen[claim.ClaimName] = true
+				names = append(names, claim.ClaimName)
+			}
+		}
+	}
+	add(template.Spec.Volumes)
+	for _, pod := range r.Pods {
+		add(pod.Spec.Volumes)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		claim, err := k8s.CoreV1().PersistentVolumeClaims(r.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			r.MissingClaims = append(r.MissingClaims, name)
+			continue
+		} else if err != nil {
+			return err
+		}
+		r.Claims = append(r.Claims, claim)
+	}
+	return nil
+}
+
+// Involved returns the objects of the report, the ones its events are about
+func (r *Report) Involved() Involved {
+	involved := Involved{}
+	involved.Add(r.Kind, r.Name)
+	for _, rs := range r.ReplicaSets {
+		involved.Add("ReplicaSet", rs.Name)
+	}
+	for _, pod := range r.Pods {
+		involved.Add("Pod", pod.Name)
+	}
+	for _, svc := range r.Services {
+		involved.Add("Service", svc.Name)
+		involved.Add("Endpoints", svc.Name)
+	}
+	for _, ingress := range r.Ingresses {
+		involved.Add("Ingress", ingress.Name)
+	}
+	for _, claim := range r.Claims {
+		involved.Add("PersistentVolumeClaim", claim.Name)
+	}
+	return involved
+}
+
+// gatherEvents gets the events of the objects of the report
+func (r *Report) gatherEvents(ctx context.Context, k8s kubernetes.Interface) error {
+	involved := r.Involved()
+	events, err := k8s.CoreV1().Events(r.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range events.Items {
+		if involved.Involves(&events.Items[i]) {
+			r.Events = append(r.Events, &events.Items[i])
+		}
+	}
+	SortEvents(r.Events)
+	return nil
+}
+
+// objectKey identifies an object by kind and name, the kinds of events being the ones of the API or the types of dolly
+func objectKey(kind, name string) string {
+	switch kind {
+	case types.DeploymentType:
+		kind = "Deployment"
+	case types.StatefulSetType:
+		kind = "StatefulSet"
+	case types.DaemonSetType:
+		kind = "DaemonSet"
+	case types.PodType:
+		kind = "Pod"
+	}
+	return strings.ToLower(kind) + "/" + name
+}
@@ -0,0 +1,56 @@
+package describe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// EventTime returns when the event was last seen
+func EventTime(event *v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// SortEvents sorts events from the oldest to the most recent
+func SortEvents(events []*v1.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return EventTime(events[i]).Before(EventTime(events[j]))
+	})
+}
+
+// FormatEvent returns a line of an event, with how long ago it was last seen
+func FormatEvent(event *v1.Event) string {
+	message := strings.Join(strings.Fields(event.Message), " ")
+	if event.Count > 1 {
+		message = fmt.Sprintf("%s (x%d)", message, event.Count)
+	}
+	return fmt.Sprintf("%-5s %-8s %-24s %s: %s", duration.HumanDuration(time.Since(EventTime(event))), event.Type,
+		event.Reason, objectName(event.InvolvedObject), message)
+}
+
+func objectName(ref v1.ObjectReference) string {
+	return strings.ToLower(ref.Kind) + "/" + ref.Name
+}
+
+// Involved are the objects to show the events of, by kind and name
+type Involved map[string]bool
+
+// Add adds an object, the kind being the one of the API or the type of dolly
+func (i Involved) Add(kind, name string) {
+	i[objectKey(kind, name)] = true
+}
+
+// Involves reports whether the event is about one of the objects
+func (i Involved) Involves(event *v1.Event) bool {
+	return i[objectKey(event.InvolvedObject.Kind, event.InvolvedObject.Name)]
+}
@@ -0,0 +1,148 @@
+package describe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/tables"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Problems returns the common reasons of failing pods found in the report, such as images that can't be pulled, unbound
+// claims, failing probes, containers killed for running out of memory and ingresses routing to services without
+// endpoints
+func (r *Report) Problems() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if r.Workload != nil && tables.RolloutStatus(r.Workload) == "Stalled" {
+		add("%s/%s: the rollout is stalled, it made no progress within its deadline", r.Kind, r.Name)
+	}
+
+	for _, pod := range r.Pods {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionFalse {
+				add("pod %s can't be scheduled: %s", pod.Name, oneLine(cond.Message))
+			}
+		}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			waiting := status.State.Waiting
+			switch {
+			case waiting == nil:
+			case imagePullReasons[waiting.Reason]:
+				add("pod %s: container %s can't pull image %s: %s", pod.Name, status.Name, status.Image, oneLine(waiting.Message))
+			case waiting.Reason == "CrashLoopBackOff":
+				add("pod %s: container %s keeps crashing, it restarted %d times", pod.Name, status.Name, status.RestartCount)
+			case waiting.Reason == "CreateContainerConfigError" || waiting.Reason == "CreateContainerError":
+				add("pod %s: container %s can't be created: %s", pod.Name, status.Name, oneLine(waiting.Message))
+			}
+
+			if oomKilled(status) {
+				add("pod %s: container %s ran out of memory and was OOMKilled, its memory limit is %s", pod.Name,
+					status.Name, memoryLimit(pod, status.Name))
+			}
+		}
+	}
+
+	probes := map[string]bool{}
+	for _, event := range r.Events {
+		if event.Reason != "Unhealthy" || event.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		message := oneLine(event.Message)
+		// one problem per probe of a pod, the messages of a probe differ by the output of each failure
+		probe := event.InvolvedObject.Name + "/" + strings.SplitN(message, ":", 2)[0]
+		if !probes[probe] {
+			probes[probe] = true
+			add("pod %s: %s", event.InvolvedObject.Name, message)
+		}
+	}
+
+	for _, name := range r.MissingClaims {
+		add("pvc %s used by the pods does not exist", name)
+	}
+	for _, claim := range r.Claims {
+		if claim.Status.Phase == v1.ClaimBound {
+			continue
+		}
+		problem := fmt.Sprintf("pvc %s is %s", claim.Name, claim.Status.Phase)
+		if event := r.lastEvent("PersistentVolumeClaim", claim.Name); event != nil {
+			problem += ": " + oneLine(event.Message)
+		}
+		problems = append(problems, problem)
+	}
+
+	for _, svc := range r.Services {
+		if r.readyEndpoints(svc.Name) > 0 {
+			continue
+		}
+		ingresses := r.ingressesOf(svc.Name)
+		if len(ingresses) == 0 {
+			add("service %s has no ready endpoints", svc.Name)
+		} else {
+			add("ingress %s routes to service %s which has no ready endpoints", strings.Join(ingresses, ", "), svc.Name)
+		}
+	}
+
+	return problems
+}
+
+func oomKilled(status v1.ContainerStatus) bool {
+	if terminated := status.State.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+		return true
+	}
+	terminated := status.LastTerminationState.Terminated
+	return terminated != nil && terminated.Reason == "OOMKilled"
+}
+
+func memoryLimit(pod *v1.Pod, container string) string {
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if c.Name != container {
+			continue
+		}
+		if limit, ok := c.Resources.Limits[v1.ResourceMemory]; ok {
+			return limit.String()
+		}
+	}
+	return "not set"
+}
+
+func (r *Report) readyEndpoints(service string) int {
+	endpoints := r.Endpoints[service]
+	if endpoints == nil {
+		return 0
+	}
+	ready := 0
+	for _, subset := range endpoints.Subsets {
+		ready += len(subset.Addresses)
+	}
+	return ready
+}
+
+func (r *Report) ingressesOf(service string) []string {
+	var result []string
+	for _, ingress := range r.Ingresses {
+		for _, backend := range Backends(ingress) {
+			if backend.Name == service {
+				result = append(result, ingress.Name)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (r *Report) lastEvent(kind, name string) *v1.Event {
+	for i := len(r.Events) - 1; i >= 0; i-- {
+		if r.Events[i].InvolvedObject.Kind == kind && r.Events[i].InvolvedObject.Name == name {
+			return r.Events[i]
+		}
+	}
+	return nil
+}
+
+func oneLine(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
@@ -0,0 +1,199 @@
+package describe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rancher/dolly/pkg/tables"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// recentEvents is the number of events written
+const recentEvents = 20
+
+// Write writes the report as sections of tables, the problems last
+func (r *Report) Write(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	if r.Workload != nil {
+		fmt.Fprintf(w, "%s/%s in %s\n", r.Kind, r.Name, r.Namespace)
+		fmt.Fprintf(w, "  Image:\t%s\n", tables.FormatImage(r.Workload))
+		fmt.Fprintf(w, "  Ready:\t%s\n", tables.FormatReady(r.Workload))
+		fmt.Fprintf(w, "  Status:\t%s\n", tables.RolloutStatus(r.Workload))
+	} else {
+		fmt.Fprintf(w, "pod/%s in %s\n", r.Name, r.Namespace)
+	}
+
+	if len(r.ReplicaSets) > 0 {
+		fmt.Fprintf(w, "\nReplicaSets\n  NAME\tREVISION\tREADY\tIMAGE\tAGE\n")
+		for _, rs := range r.ReplicaSets {
+			desired := int32(1)
+			if rs.Spec.Replicas != nil {
+				desired = *rs.Spec.Replicas
+			}
+			image := ""
+			if len(rs.Spec.Template.Spec.Containers) > 0 {
+				image = rs.Spec.Template.Spec.Containers[0].Image
+			}
+			fmt.Fprintf(w, "  %s\t%d\t%d/%d\t%s\t%s\n", rs.Name, revision(rs), rs.Status.ReadyReplicas, desired, image,
+				age(rs.CreationTimestamp))
+		}
+	}
+
+	fmt.Fprintf(w, "\nPods\n")
+	if len(r.Pods) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	} else {
+		fmt.Fprintf(w, "  NAME\tREADY\tSTATUS\tRESTARTS\tNODE\tIP\tAGE\n")
+	}
+	for _, pod := range r.Pods {
+		ready, _ := tables.PodReady(pod)
+		restarts := int32(0)
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\t%s\t%s\n", pod.Name, ready, podStatus(pod), restarts, pod.Spec.NodeName,
+			pod.Status.PodIP, age(pod.CreationTimestamp))
+	}
+
+	if len(r.Services) > 0 {
+		fmt.Fprintf(w, "\nServices\n  NAME\tTYPE\tCLUSTER-IP\tPORTS\tENDPOINTS\n")
+		for _, svc := range r.Services {
+			var ports []string
+			for _, port := range svc.Spec.Ports {
+				ports = append(ports, fmt.Sprintf("%d->%s/%s", port.Port, port.TargetPort.String(), port.Protocol))
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", svc.Name, svc.Spec.Type, svc.Spec.ClusterIP, strings.Join(ports, ","),
+				formatEndpoints(r.Endpoints[svc.Name]))
+		}
+	}
+
+	if len(r.Ingresses) > 0 {
+		fmt.Fprintf(w, "\nIngresses\n  NAME\tCLASS\tROUTES\n")
+		for _, ingress := range r.Ingresses {
+			class := ""
+			if ingress.Spec.IngressClassName != nil {
+				class = *ingress.Spec.IngressClassName
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", ingress.Name, class, strings.Join(routes(ingress.Spec), ","))
+		}
+	}
+
+	if len(r.Claims) > 0 {
+		fmt.Fprintf(w, "\nVolumes\n  CLAIM\tSTATUS\tVOLUME\tCAPACITY\tSTORAGECLASS\n")
+		for _, claim := range r.Claims {
+			capacity := ""
+			if size, ok := claim.Status.Capacity[v1.ResourceStorage]; ok {
+				capacity = size.String()
+			}
+			class := ""
+			if claim.Spec.StorageClassName != nil {
+				class = *claim.Spec.StorageClassName
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", claim.Name, claim.Status.Phase, claim.Spec.VolumeName, capacity,
+				class)
+		}
+	}
+
+	fmt.Fprintf(w, "\nEvents\n")
+	events := r.Events
+	if len(events) > recentEvents {
+		events = events[len(events)-recentEvents:]
+	}
+	if len(events) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	for _, event := range events {
+		fmt.Fprintf(w, "  %s\n", FormatEvent(event))
+	}
+
+	fmt.Fprintf(w, "\nProblems\n")
+	problems := r.Problems()
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "  none found\n")
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(w, "  ! %s\n", problem)
+	}
+
+	return w.Flush()
+}
+
+func age(t metav1.Time) string {
+	return duration.HumanDuration(time.Since(t.Time))
+}
+
+// podStatus returns the phase of a pod, or why a container of it is not running
+func podStatus(pod *v1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" && waiting.Reason != "PodInitializing" {
+			return waiting.Reason
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			return terminated.Reason
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+func formatEndpoints(endpoints *v1.Endpoints) string {
+	if endpoints == nil {
+		return "none"
+	}
+	var ready, notReady []string
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			ready = append(ready, address.IP)
+		}
+		for _, address := range subset.NotReadyAddresses {
+			notReady = append(notReady, address.IP)
+		}
+	}
+	sort.Strings(ready)
+	result := strings.Join(ready, ",")
+	if result == "" {
+		result = "none"
+	}
+	if len(notReady) > 0 {
+		sort.Strings(notReady)
+		result += fmt.Sprintf(" (not ready: %s)", strings.Join(notReady, ","))
+	}
+	return result
+}
+
+func routes(spec networkingv1.IngressSpec) []string {
+	var result []string
+	for _, rule := range spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				continue
+			}
+			port := path.Backend.Service.Port.Name
+			if port == "" {
+				port = fmt.Sprint(path.Backend.Service.Port.Number)
+			}
+			result = append(result, fmt.Sprintf("%s%s->%s:%s", host, path.Path, path.Backend.Service.Name, port))
+		}
+	}
+	if backend := spec.DefaultBackend; backend != nil && backend.Service != nil {
+		result = append(result, "default->"+backend.Service.Name)
+	}
+	return result
+}
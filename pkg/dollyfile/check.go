@@ -0,0 +1,483 @@
+package dollyfile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/template"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/utils"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	yamlErrorLine = regexp.MustCompile(`line ([0-9]+)`)
+	typePattern   = regexp.MustCompile(`^(array|map)\[(.*)\]$`)
+
+	// freeFormSchemas are the schemas whose keys are data rather than fields, such as the keys of a config
+	freeFormSchemas = map[string]bool{
+		"configMap": true,
+	}
+)
+
+// Problem is an error or a warning found in a Dollyfile, positioned at the key or value it is about
+type Problem struct {
+	Line    int
+	Column  int
+	Warning bool
+	Message string
+}
+
+func (p Problem) String() string {
+	level := "error"
+	if p.Warning {
+		level = "warning"
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, level, p.Message)
+}
+
+// Check validates a Dollyfile against the schema and reports unknown keys, invalid values and references to configs,
+// services and ports that don't exist. Unlike Parse it reports every problem with its position instead of stopping at
+// the first one. The positions are the ones of the Dollyfile after the template is evaluated.
+func Check(contents []byte, answers template.AnswerCallback) ([]Problem, error) {
+	if k8s, _, err := isK8SYaml(contents); err == nil && k8s {
+		return nil, nil
+	}
+
+	t := template.Template{
+		Content: contents,
+	}
+	content, err := t.Parse(answers)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(content, doc); err != nil {
+		line := 0
+		if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		c.problems = append(c.problems, Problem{Line: line, Message: err.Error()})
+		return c.problems, nil
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		c.errorf(root, "dollyfile must be a map")
+		return c.problems, nil
+	}
+
+	c.object(root, Schema.Schema("DollyFile"), "template")
+	if meta := mapValue(root, "template"); meta != nil && meta.Kind == yaml.MappingNode {
+		c.object(meta, Schema.Schema("templateMeta"))
+	}
+	if len(c.problems) == 0 {
+		c.references(root, content, answers)
+	}
+
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		if c.problems[i].Line != c.problems[j].Line {
+			return c.problems[i].Line < c.problems[j].Line
+		}
+		return c.problems[i].Column < c.problems[j].Column
+	})
+	return c.problems, nil
+}
+
+// HasErrors returns whether any of the problems is not a warning
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if !p.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+type checker struct {
+	problems []Problem
+}
+
+func (c *checker) errorf(node *yaml.Node, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) warnf(node *yaml.Node, format string, args ...interface{}) {
+	c.errorf(node, format, args...)
+	c.problems[len(c.problems)-1].Warning = true
+}
+
+// object checks the keys of a map against the fields of the schema, the aliases of the fields are part of the schema
+func (c *checker) object(node *yaml.Node, schema *schemas.Schema, ignore ...string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if contains(ignore, key.Value) {
+			continue
+		}
+		// snake_case keys are converted to camelCase by the JSONKeys mapper
+		if !hasField(schema, key.Value) {
+			c.errorf(key, "unknown key %s%s", key.Value, suggestion(key.Value, schema))
+			continue
+		}
+		c.field(key, value, schema)
+	}
+}
+
+func hasField(schema *schemas.Schema, key string) bool {
+	_, ok := schema.ResourceFields[key]
+	_, converted := schema.ResourceFields[convert.ToJSONKey(key)]
+	return ok || converted
+}
+
+// field checks a value by running it through the mappers of the schema and comparing the result with the type of the
+// field. Maps and lists of objects are checked key by key first, which gives the position of nested problems.
+func (c *checker) field(key, value *yaml.Node, schema *schemas.Schema) {
+	var v interface{}
+	if err := value.Decode(&v); err != nil {
+		c.errorf(value, "%s: %v", key.Value, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		key.Value: v,
+	}
+	mapErr := schema.Mapper.ToInternal(data)
+	_, field, internal, ok := internalField(schema, data)
+	if !ok {
+		if mapErr != nil {
+			c.errorf(value, "%s: %v", key.Value, mapErr)
+		}
+		return
+	}
+
+	before := len(c.problems)
+	c.nested(field.Type, value)
+	if len(c.problems) > before {
+		return
+	}
+
+	if mapErr != nil {
+		c.errorf(c.failingItem(key, value, schema), "%s: %v", key.Value, mapErr)
+		return
+	}
+	if expected, ok := checkType(field.Type, internal); !ok {
+		c.errorf(value, "%s must be %s", key.Value, expected)
+	}
+}
+
+// nested checks the maps in a value whose type is a schema, such as the services of the Dollyfile or the ports of a
+// service written as objects. Values written in a short form are checked by the mappers.
+func (c *checker) nested(fieldType string, value *yaml.Node) {
+	// docker compose healthchecks are converted to a probe by the mapper
+	if fieldType == "probe" && (mapValue(value, "test") != nil || mapValue(value, "disable") != nil) {
+		return
+	}
+	if match := typePattern.FindStringSubmatch(fieldType); match != nil {
+		schema := Schema.Schema(match[2])
+		if schema == nil || freeFormSchemas[schema.ID] {
+			return
+		}
+		switch {
+		case match[1] == "array" && value.Kind == yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind == yaml.MappingNode {
+					c.object(item, schema)
+				}
+			}
+		case match[1] == "map" && value.Kind == yaml.MappingNode:
+			for i := 1; i < len(value.Content); i += 2 {
+				if value.Content[i].Kind == yaml.MappingNode {
+					c.object(value.Content[i], schema)
+				}
+			}
+		}
+		return
+	}
+
+	if schema := Schema.Schema(fieldType); schema != nil && !freeFormSchemas[schema.ID] && value.Kind == yaml.MappingNode {
+		c.object(value, schema)
+	}
+}
+
+// failingItem returns the item of a list that fails to convert, or the whole value
+func (c *checker) failingItem(key, value *yaml.Node, schema *schemas.Schema) *yaml.Node {
+	if value.Kind != yaml.SequenceNode {
+		return value
+	}
+	for _, item := range value.Content {
+		var v interface{}
+		if err := item.Decode(&v); err != nil {
+			return item
+		}
+		data := map[string]interface{}{
+			key.Value: []interface{}{v},
+		}
+		if err := schema.Mapper.ToInternal(data); err != nil {
+			return item
+		}
+	}
+	return value
+}
+
+// internalField finds the field a key is converted to by the mappers, embedded fields are moved into a nested map
+func internalField(schema *schemas.Schema, data map[string]interface{}) (string, schemas.Field, interface{}, bool) {
+	for k, v := range data {
+		if field, ok := schema.ResourceFields[k]; ok && field.Type != "" {
+			return k, field, v, true
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			if name, field, value, ok := internalField(schema, m); ok {
+				return name, field, value, true
+			}
+		}
+	}
+	return "", schemas.Field{}, nil, false
+}
+
+// checkType compares a converted value with the type of the field, returning the expected type if it doesn't match
+func checkType(fieldType string, value interface{}) (string, bool) {
+	if value == nil {
+		return "", true
+	}
+
+	switch v := value.(type) {
+	case string:
+		switch fieldType {
+		case "string", "intOrString", "json":
+			return "", true
+		case "int":
+			_, err := strconv.ParseInt(v, 10, 64)
+			return "a number", err == nil
+		case "boolean":
+			_, err := strconv.ParseBool(v)
+			return "true or false", err == nil
+		}
+	case bool:
+		switch fieldType {
+		case "boolean", "json":
+			return "", true
+		case "string":
+			return "a string", false
+		}
+	case int, int64, float64:
+		switch fieldType {
+		case "int", "intOrString", "json", "string":
+			return "", true
+		}
+	case []interface{}:
+		if strings.HasPrefix(fieldType, "array[") || fieldType == "json" {
+			return "", true
+		}
+	case map[string]interface{}:
+		if strings.HasPrefix(fieldType, "map[") || Schema.Schema(fieldType) != nil || fieldType == "json" {
+			return "", true
+		}
+	default:
+		return "", true
+	}
+
+	switch {
+	case strings.HasPrefix(fieldType, "array["):
+		return "a list", false
+	case strings.HasPrefix(fieldType, "map[") || Schema.Schema(fieldType) != nil:
+		return "a map", false
+	case fieldType == "int":
+		return "a number", false
+	case fieldType == "boolean":
+		return "true or false", false
+	}
+	return "a " + fieldType, false
+}
+
+// suggestion returns a hint with the field of the schema closest to an unknown key
+func suggestion(key string, schema *schemas.Schema) string {
+	var (
+		best        string
+		maxDistance = len(key) / 3
+		distance    = -1
+	)
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	for name := range schema.ResourceFields {
+		d := levenshtein(strings.ToLower(key), strings.ToLower(name))
+		if d > maxDistance {
+			continue
+		}
+		if distance < 0 || d < distance || (d == distance && name < best) {
+			best, distance = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %s?", best)
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minimum(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+// references checks the services, routes and configs the Dollyfile refers to, once the values are known to be valid
+func (c *checker) references(root *yaml.Node, content []byte, answers template.AnswerCallback) {
+	data, err := parseData(content, answers)
+	if err != nil {
+		c.errorf(root, "%v", err)
+		return
+	}
+	if err := Schema.Schema("DollyFile").Mapper.ToInternal(data); err != nil {
+		c.errorf(root, "%v", err)
+		return
+	}
+	rf := &DollyFile{}
+	if err := convert.ToObj(data, rf); err != nil {
+		c.errorf(root, "%v", err)
+		return
+	}
+	for k, v := range rf.Services {
+		v.Name = k
+		rf.Services[k] = v
+	}
+
+	if services := mapValue(root, "services"); services != nil {
+		for i := 0; i+1 < len(services.Content); i += 2 {
+			key, value := services.Content[i], services.Content[i+1]
+			service, ok := rf.Services[key.Value]
+			if !ok {
+				continue
+			}
+			c.service(rf, service, key, value)
+		}
+	}
+
+	if routes := mapValue(root, "routes"); routes != nil {
+		for i := 0; i+1 < len(routes.Content); i += 2 {
+			key := routes.Content[i]
+			for _, route := range rf.Routes[key.Value].Spec.Routes {
+				destinations := route.To
+				if route.Mirror != nil {
+					destinations = append(destinations, types.WeightedDestination{Destination: *route.Mirror})
+				}
+				for _, to := range destinations {
+					if !hasBackend(rf, to.App) {
+						c.errorf(key, "route %s points to unknown service %s", key.Value, to.App)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *checker) service(rf *DollyFile, service types.Service, key, value *yaml.Node) {
+	if err := rf.validateConnections(service); err != nil {
+		c.errorf(fieldKey(key, value, "connects", "connect", "allowFrom", "allow_from"), "%v", err)
+	}
+	if err := validateWorkload(service); err != nil {
+		c.errorf(key, "%v", err)
+	}
+	if err := validateVolumes(service); err != nil {
+		c.errorf(fieldKey(key, value, "volumes", "volume"), "%v", err)
+	}
+
+	ports := map[string]bool{}
+	for _, container := range utils.ToNamedContainers(service) {
+		for _, mount := range container.Configs {
+			c.config(rf, mount.Name, fieldKey(key, value, "configs", "config"))
+		}
+		for _, env := range container.Env {
+			if env.ConfigMapName != "" {
+				c.config(rf, env.ConfigMapName, fieldKey(key, value, "env", "environment"))
+			}
+		}
+		for _, volume := range container.Volumes {
+			if volume.Projected == nil {
+				continue
+			}
+			for _, name := range volume.Projected.Configs {
+				c.config(rf, name, fieldKey(key, value, "volumes", "volume"))
+			}
+		}
+
+		for _, port := range container.Ports {
+			serviceType := port.ServiceType
+			if serviceType == "" {
+				serviceType = service.Spec.ServiceType
+			}
+			port := stringers.NormalizeContainerPort(port)
+			id := fmt.Sprintf("%d/%s", port.Port, utils.Protocol(port.Protocol))
+			if ports[id+string(serviceType)] {
+				c.errorf(fieldKey(key, value, "ports", "port"), "service %s declares port %s more than once", service.Name, id)
+			}
+			ports[id+string(serviceType)] = true
+		}
+	}
+}
+
+func (c *checker) config(rf *DollyFile, name string, node *yaml.Node) {
+	if _, ok := rf.Configs[name]; !ok {
+		c.warnf(node, "config %s is not defined in the dollyfile, it must exist in the namespace", name)
+	}
+}
+
+// hasBackend returns whether a route destination is a service, the converters name the backend after the app of the
+// service
+func hasBackend(rf *DollyFile, app string) bool {
+	for name, service := range rf.Services {
+		if name == app || service.Spec.App == app {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldKey returns the key of the first of the fields found in the service, or the key of the service itself
+func fieldKey(key, value *yaml.Node, names ...string) *yaml.Node {
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if contains(names, value.Content[i].Value) {
+			return value.Content[i]
+		}
+	}
+	return key
+}
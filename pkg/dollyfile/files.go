@@ -9,28 +9,18 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/rancher/dolly/pkg/scaffold"
+	"github.com/rancher/dolly/pkg/template"
+	"github.com/rancher/dolly/pkg/types"
 	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/sirupsen/logrus"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	defaultDollyfile   = "dolly.yaml"
-	defaultDollyAnswer = "dolly-answer.yaml"
-
-	defaultDollyfileContent = `
-services:
-  %s:
-    image: ./
-    ports: 80:8080/http`
-
-	defaultDollyfileContentWithDockerfile = `
-services:
-  %s:
-    build:
-      dockerfile: %s
-      context: %s
-    ports: 80:8080/http`
+	defaultDollyfile   = "DollyFile"
+	defaultDollyAnswer = "DollyFile-answers"
 )
 
 func LoadFileAndAnswer(path string, answerPath string) ([]byte, map[string]string, error) {
@@ -49,6 +39,9 @@ func LoadFileAndAnswer(path string, answerPath string) ([]byte, map[string]strin
 func LoadDollyfile(path string) ([]byte, error) {
 	if path != "" {
 		content, err := readFile(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s not found, run dolly init to create a dollyfile", path)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -58,14 +51,42 @@ func LoadDollyfile(path string) ([]byte, error) {
 			return content, nil
 		}
 		// named Dockerfile
-		return []byte(fmt.Sprintf(defaultDollyfileContentWithDockerfile, getCurrentDir(), filepath.Base(path), filepath.Dir(path))), nil
+		project, err := scaffold.FromDockerfile(getCurrentDir(), path)
+		if err != nil {
+			return nil, err
+		}
+		return project.Dollyfile(true), nil
 	}
 	// assumed DollyFile
 	if _, err := os.Stat(defaultDollyfile); err == nil {
 		return ioutil.ReadFile(defaultDollyfile)
 	}
 	// assumed Dockerfile
-	return []byte(fmt.Sprintf(defaultDollyfileContent, getCurrentDir())), nil
+	project, err := scaffold.Detect(".")
+	if err != nil {
+		return nil, err
+	}
+	return project.Dollyfile(true), nil
+}
+
+// DefaultServices returns the services of the DollyFile of the current directory by name, nil if there is none or it
+// can't be parsed
+func DefaultServices() map[string]types.Service {
+	content, err := ioutil.ReadFile(defaultDollyfile)
+	if err != nil {
+		return nil
+	}
+	answers, err := LoadAnswer("")
+	if err != nil {
+		logrus.Debugf("ignoring %s: %v", defaultDollyfile, err)
+		return nil
+	}
+	rf, err := Parse(content, "", template.AnswersFromMap(answers))
+	if err != nil {
+		logrus.Debugf("ignoring %s: %v", defaultDollyfile, err)
+		return nil
+	}
+	return rf.Services
 }
 
 func LoadAnswer(path string) (map[string]string, error) {
@@ -0,0 +1,280 @@
+package dollyfile
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/wrangler/pkg/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+const templatePlaceholder = "__DOLLY_TEMPLATE_%d__"
+
+var (
+	templateAction       = regexp.MustCompile(`{{.*?}}`)
+	templatePlaceholders = regexp.MustCompile(`__DOLLY_TEMPLATE_([0-9]+)__`)
+	templateLines        = regexp.MustCompile(`(?m)^(\s*)# __DOLLY_TEMPLATE_([0-9]+)__$`)
+
+	// formattedKeys are the top level keys that are canonicalised through the schema, the others are free form
+	formattedKeys = map[string]bool{
+		"defaultResources": true,
+		"ingress":          true,
+		"isolation":        true,
+	}
+)
+
+// Format prints a Dollyfile in the canonical short form of the stringers with the keys sorted. Comments, go template
+// actions and ${VAR} substitutions are kept, values that can only be parsed once the template is evaluated are left
+// as they are.
+func Format(contents []byte) ([]byte, error) {
+	if k8s, _, err := isK8SYaml(contents); err == nil && k8s {
+		return contents, nil
+	}
+
+	protected, actions := protectTemplates(contents)
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(protected, doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return contents, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("dollyfile must be a map, found %s", root.Tag)
+	}
+	// a comment at the top of the file stays there when the keys are sorted
+	if len(root.Content) > 0 && doc.HeadComment == "" {
+		doc.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+
+	formatFields(root, Schema.Schema("DollyFile"), formattedKeys)
+	if services := mapValue(root, "services"); services != nil && services.Kind == yaml.MappingNode {
+		for i := 1; i < len(services.Content); i += 2 {
+			formatService(services.Content[i])
+		}
+	}
+	sortKeys(root)
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return restoreTemplates(buf.Bytes(), actions), nil
+}
+
+func formatService(service *yaml.Node) {
+	if service.Kind != yaml.MappingNode {
+		return
+	}
+
+	containers := mapValue(service, "containers")
+	if containers != nil && containers.Kind == yaml.SequenceNode {
+		for _, container := range containers.Content {
+			if container.Kind == yaml.MappingNode {
+				formatFields(container, Schema.Schema("namedContainer"), nil)
+			}
+		}
+	}
+
+	formatFields(service, Schema.Schema("service"), nil, "containers")
+}
+
+// formatFields runs every field of the map through the schema mappers and back, which turns aliases and long forms
+// into the canonical key and short form. Fields are formatted one by one so comments of untouched fields are kept.
+func formatFields(node *yaml.Node, schema *schemas.Schema, only map[string]bool, skip ...string) {
+	// go template actions are attached to whichever node yaml finds next to them, rewriting any value around them can
+	// move an action to another key
+	if hasTemplateComment(node) {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if (only != nil && !only[key.Value]) || contains(skip, key.Value) {
+			continue
+		}
+		var original interface{}
+		if err := value.Decode(&original); err != nil {
+			continue
+		}
+
+		data := map[string]interface{}{
+			key.Value: original,
+		}
+		// values that fail to parse, such as ${VAR} substitutions, are left as they are
+		if err := schema.Mapper.ToInternal(data); err != nil {
+			continue
+		}
+		schema.Mapper.FromInternal(data)
+		if len(data) != 1 {
+			continue
+		}
+
+		for name, formatted := range data {
+			// a single value is not turned into a list of one
+			if list, ok := formatted.([]interface{}); ok && len(list) == 1 && value.Kind == yaml.ScalarNode {
+				formatted = list[0]
+			}
+			if name == key.Value && sameValue(original, formatted) {
+				continue
+			}
+
+			replacement, err := toNode(formatted)
+			if err != nil {
+				continue
+			}
+			key.Value = name
+			key.HeadComment = joinComments(key.HeadComment, nestedComments(value))
+			replacement.LineComment = value.LineComment
+			node.Content[i+1] = replacement
+		}
+	}
+}
+
+func toNode(value interface{}) (*yaml.Node, error) {
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(content, doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) != 1 {
+		return nil, fmt.Errorf("invalid value %v", value)
+	}
+	return doc.Content[0], nil
+}
+
+func sameValue(a, b interface{}) bool {
+	left, err := yaml.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := yaml.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(left, right)
+}
+
+// nestedComments collects the comments inside a value that is replaced, they are moved above its key
+func nestedComments(node *yaml.Node) string {
+	var comments []string
+	for _, child := range node.Content {
+		comments = append(comments, child.HeadComment, child.LineComment, nestedComments(child), child.FootComment)
+	}
+	comments = append(comments, node.HeadComment, node.FootComment)
+	return joinComments(comments...)
+}
+
+func joinComments(comments ...string) string {
+	var result []string
+	for _, comment := range comments {
+		if comment != "" {
+			result = append(result, comment)
+		}
+	}
+	return strings.Join(result, "\n")
+}
+
+// sortKeys sorts the keys of every map, maps holding go template actions anywhere below them keep their order
+func sortKeys(node *yaml.Node) {
+	for _, child := range node.Content {
+		sortKeys(child)
+	}
+	if node.Kind != yaml.MappingNode || hasTemplateComment(node) {
+		return
+	}
+
+	var pairs [][2]*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i][0].Value < pairs[j][0].Value
+	})
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
+
+// hasTemplateComment reports whether a go template action line became a comment of the node or of any node below it
+func hasTemplateComment(node *yaml.Node) bool {
+	return templatePlaceholders.MatchString(joinComments(node.HeadComment, node.LineComment, nestedComments(node)))
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// protectTemplates replaces the go template actions, which are not valid yaml, with placeholders. Lines holding only
+// actions become comments, other actions become plain values.
+func protectTemplates(contents []byte) ([]byte, []string) {
+	var (
+		actions []string
+		lines   = strings.Split(string(contents), "\n")
+	)
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") && templateAction.ReplaceAllString(trimmed, "") == "" {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + "# " + fmt.Sprintf(templatePlaceholder, len(actions))
+			actions = append(actions, trimmed)
+			continue
+		}
+		lines[i] = templateAction.ReplaceAllStringFunc(line, func(action string) string {
+			actions = append(actions, action)
+			return fmt.Sprintf(templatePlaceholder, len(actions)-1)
+		})
+	}
+
+	return []byte(strings.Join(lines, "\n")), actions
+}
+
+func restoreTemplates(contents []byte, actions []string) []byte {
+	action := func(index string) string {
+		i, err := strconv.Atoi(index)
+		if err != nil || i >= len(actions) {
+			return index
+		}
+		return actions[i]
+	}
+
+	contents = templateLines.ReplaceAllFunc(contents, func(line []byte) []byte {
+		match := templateLines.FindSubmatch(line)
+		return append([]byte(string(match[1])), action(string(match[2]))...)
+	})
+	return templatePlaceholders.ReplaceAllFunc(contents, func(placeholder []byte) []byte {
+		return []byte(action(string(templatePlaceholders.FindSubmatch(placeholder)[1])))
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
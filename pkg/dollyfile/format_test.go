@@ -0,0 +1,69 @@
+package dollyfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatKeepsTemplateBlocks(t *testing.T) {
+	formatted, err := Format([]byte(`services:
+  web:
+    image: nginx
+    scale: 2
+{{- if .Values.db }}
+  db:
+    image: postgres
+{{- end }}
+  worker:
+    image: worker
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, `services:
+  web:
+    image: nginx
+    scale: 2
+    {{- if .Values.db }}
+  db:
+    image: postgres
+    {{- end }}
+  worker:
+    image: worker
+`, string(formatted))
+}
+
+func TestFormatKeepsTemplateRange(t *testing.T) {
+	formatted, err := Format([]byte(`services:
+  web:
+    image: nginx
+    env:
+    {{- range .Values.env }}
+    - {{ . }}
+    {{- end }}
+    cpus: 100m
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, `services:
+  web:
+    image: nginx
+    env:
+    {{- range .Values.env }}
+    - {{ . }}
+    {{- end }}
+    cpus: 100m
+`, string(formatted))
+}
+
+func TestFormatSortsKeysWithoutTemplates(t *testing.T) {
+	formatted, err := Format([]byte(`services:
+  web:
+    scale: 2
+    image: nginx
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, `services:
+  web:
+    image: nginx
+    scale: 2
+`, string(formatted))
+}
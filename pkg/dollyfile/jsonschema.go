@@ -0,0 +1,419 @@
+package dollyfile
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const definitionsRef = "#/definitions/"
+
+var (
+	// objectsToSliceMappers are the field mappers accepting a list of strings in a short form or of objects, or a
+	// single string
+	objectsToSliceMappers = map[string]bool{
+		"configs":       true,
+		"secrets":       true,
+		"dnsOptions":    true,
+		"env":           true,
+		"ports":         true,
+		"hosts":         true,
+		"volumes":       true,
+		"sysctls":       true,
+		"tolerations":   true,
+		"topologyRules": true,
+		"permissions":   true,
+	}
+
+	quantity = jsonObject{
+		"type": []string{"number", "string"},
+	}
+
+	composeHealthcheck = jsonObject{
+		"type":        "object",
+		"description": "Docker compose healthcheck",
+		"properties": jsonObject{
+			"test":         jsonObject{"type": []string{"string", "array"}, "items": jsonObject{"type": "string"}},
+			"interval":     jsonObject{"type": "string"},
+			"timeout":      jsonObject{"type": "string"},
+			"retries":      jsonObject{"type": "integer"},
+			"start_period": jsonObject{"type": "string"},
+			"disable":      jsonObject{"type": "boolean"},
+		},
+		"anyOf": []jsonObject{
+			{"required": []string{"test"}},
+			{"required": []string{"disable"}},
+		},
+		"additionalProperties": false,
+	}
+)
+
+type jsonObject map[string]interface{}
+
+// fieldInfo is what the Go type tells about a field beyond its schema, its description and field mappers
+type fieldInfo struct {
+	description string
+	mappers     map[string][]string
+	goType      reflect.Type
+}
+
+// JSONSchema generates a JSON Schema of the Dollyfile from the schema and the Go types, for the completion and hover
+// docs of editors. Aliases are properties of their own and the short forms of the field mappers are alternatives of the
+// long form. The descriptions are the doc comments of the fields, generated into the SwaggerDoc methods of the types.
+func JSONSchema() ([]byte, error) {
+	g := &generator{
+		types:       map[string]reflect.Type{},
+		definitions: jsonObject{},
+	}
+	g.index(reflect.TypeOf(DollyFile{}))
+	g.index(reflect.TypeOf(types.TemplateMeta{}))
+
+	g.definition("DollyFile")
+	g.definition("templateMeta")
+	root := g.definitions["DollyFile"].(jsonObject)
+	root["properties"].(jsonObject)["template"] = describe(jsonObject{"$ref": definitionsRef + "templateMeta"},
+		"Templating of the Dollyfile, evaluated before it is parsed")
+
+	return json.MarshalIndent(jsonObject{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"title":       "Dollyfile",
+		"$ref":        definitionsRef + "DollyFile",
+		"definitions": g.definitions,
+	}, "", "  ")
+}
+
+type generator struct {
+	types       map[string]reflect.Type
+	definitions jsonObject
+}
+
+// index maps the schema IDs to the Go types of the Dollyfile, named the way the schema names them
+func (g *generator) index(t reflect.Type) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	id := convert.LowerTitle(t.Name())
+	if t == reflect.TypeOf(DollyFile{}) {
+		id = "DollyFile"
+	}
+	if _, ok := g.types[id]; ok {
+		return
+	}
+	g.types[id] = t
+
+	for i := 0; i < t.NumField(); i++ {
+		g.index(t.Field(i).Type)
+	}
+}
+
+func (g *generator) definition(id string) {
+	if _, ok := g.definitions[id]; ok {
+		return
+	}
+	schema := Schema.Schema(id)
+	if schema == nil {
+		return
+	}
+	if freeFormSchemas[id] {
+		g.definitions[id] = jsonObject{
+			"type":                 "object",
+			"additionalProperties": jsonObject{"type": []string{"string", "number", "boolean"}},
+		}
+		return
+	}
+
+	definition := jsonObject{
+		"type":                 "object",
+		"additionalProperties": false,
+		// snake_case keys are converted to camelCase by the JSONKeys mapper
+		"patternProperties": jsonObject{
+			"^[a-z0-9]+(_[a-z0-9]+)+$": jsonObject{},
+		},
+	}
+	g.definitions[id] = definition
+
+	infos := map[string]fieldInfo{}
+	if t, ok := g.types[id]; ok {
+		collectFields(t, schema, infos)
+		if description := swaggerDoc(t)[""]; description != "" {
+			definition["description"] = description
+		}
+	}
+
+	properties := jsonObject{}
+	for name, field := range schema.ResourceFields {
+		if field.Type == "" {
+			continue
+		}
+		info := infos[name]
+		properties[name] = describe(g.property(field.Type, info), info.description)
+
+		for _, alias := range append(info.mappers["alias"], info.mappers["output"]...) {
+			if _, ok := schema.ResourceFields[alias]; !ok {
+				continue
+			}
+			properties[alias] = describe(g.property(field.Type, info), strings.TrimSpace("Alias of "+name+". "+info.description))
+		}
+		if _, ok := info.mappers["hostNetwork"]; ok {
+			properties["net"] = jsonObject{
+				"type":        "string",
+				"enum":        []string{"host"},
+				"description": "Set to host to use the network of the host, same as hostNetwork",
+			}
+		}
+	}
+	definition["properties"] = properties
+}
+
+// property returns the schema of a field, with the short forms accepted by its field mappers as alternatives
+func (g *generator) property(fieldType string, info fieldInfo) jsonObject {
+	long := g.typeSchema(fieldType)
+	if opts, ok := info.mappers["enum"]; ok {
+		var values []string
+		for _, opt := range opts {
+			alias, value := kv.Split(opt, "=")
+			values = append(values, alias)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+		long = enum(values)
+	}
+	if _, ok := info.mappers["quantity"]; ok {
+		long = quantity
+	}
+	// quantities also unmarshal from numbers, such as the values of a resource list
+	if t := info.goType; t != nil && t.Kind() == reflect.Map && t.Elem() == reflect.TypeOf(resource.Quantity{}) {
+		long = jsonObject{"type": "object", "additionalProperties": quantity}
+	}
+
+	var mappers []string
+	for mapper := range info.mappers {
+		mappers = append(mappers, mapper)
+	}
+	sort.Strings(mappers)
+
+	alternatives := []jsonObject{long}
+	for _, mapper := range mappers {
+		switch {
+		case mapper == "requestLimit":
+			alternatives = append(alternatives, jsonObject{
+				"type": "object",
+				"properties": jsonObject{
+					"request": quantity,
+					"limit":   quantity,
+				},
+				"additionalProperties": false,
+			})
+		case mapper == "probe":
+			alternatives = append(alternatives, jsonObject{"type": "string"}, composeHealthcheck)
+		case mapper == "envmap":
+			alternatives = append(alternatives, jsonObject{
+				"type":                 "object",
+				"additionalProperties": jsonObject{"type": []string{"string", "number", "boolean"}},
+			})
+		case mapper == "stringMap":
+			alternatives = append(alternatives, jsonObject{"type": "string"}, jsonObject{"type": "array", "items": jsonObject{"type": "string"}})
+		case mapper == "handler" || mapper == "duration" || mapper == "stringSlice" || mapper == "shlex":
+			alternatives = append(alternatives, jsonObject{"type": "string"})
+		case objectsToSliceMappers[mapper]:
+			if items, ok := long["items"].(jsonObject); ok {
+				long["items"] = oneOf(jsonObject{"type": "string"}, items)
+			}
+			alternatives = append(alternatives, jsonObject{"type": "string"})
+		}
+	}
+
+	return oneOf(alternatives...)
+}
+
+// enum accepts the values for completion, and any case and - or _ the way the enum mapper does
+func enum(values []string) jsonObject {
+	var patterns []string
+	for _, value := range unique(values) {
+		var pattern strings.Builder
+		for _, r := range strings.NewReplacer("-", "", "_", "").Replace(value) {
+			lower, upper := strings.ToLower(string(r)), strings.ToUpper(string(r))
+			if lower == upper {
+				pattern.WriteString(regexp.QuoteMeta(lower))
+			} else {
+				pattern.WriteString("[" + lower + upper + "]")
+			}
+			pattern.WriteString("[-_]*")
+		}
+		patterns = append(patterns, pattern.String())
+	}
+
+	return jsonObject{
+		"anyOf": []jsonObject{
+			{"enum": unique(values)},
+			{"type": "string", "pattern": "^[-_]*(" + strings.Join(patterns, "|") + ")$"},
+		},
+	}
+}
+
+// describe returns the property with a description, a reference is wrapped as keywords next to $ref are ignored
+func describe(property jsonObject, description string) jsonObject {
+	if description == "" {
+		return property
+	}
+	if ref, ok := property["$ref"]; ok {
+		return jsonObject{
+			"allOf":       []jsonObject{{"$ref": ref}},
+			"description": description,
+		}
+	}
+
+	result := jsonObject{
+		"description": description,
+	}
+	for k, v := range property {
+		result[k] = v
+	}
+	return result
+}
+
+func (g *generator) typeSchema(fieldType string) jsonObject {
+	switch fieldType {
+	case "string":
+		return jsonObject{"type": "string"}
+	case "int":
+		return jsonObject{"type": "integer"}
+	case "float":
+		return jsonObject{"type": "number"}
+	case "boolean":
+		return jsonObject{"type": "boolean"}
+	case "intOrString":
+		return jsonObject{"type": []string{"integer", "string"}}
+	case "json", "date":
+		return jsonObject{}
+	}
+
+	if match := typePattern.FindStringSubmatch(fieldType); match != nil {
+		if match[1] == "array" {
+			return jsonObject{"type": "array", "items": g.typeSchema(match[2])}
+		}
+		return jsonObject{"type": "object", "additionalProperties": g.typeSchema(match[2])}
+	}
+
+	if Schema.Schema(fieldType) == nil {
+		return jsonObject{}
+	}
+	g.definition(fieldType)
+	return jsonObject{"$ref": definitionsRef + fieldType}
+}
+
+// oneOf returns the schema accepting exactly one of the alternatives, which are deduplicated
+func oneOf(alternatives ...jsonObject) jsonObject {
+	var (
+		result []jsonObject
+		seen   = map[string]bool{}
+	)
+	for _, alternative := range alternatives {
+		key, _ := json.Marshal(alternative)
+		if !seen[string(key)] {
+			seen[string(key)] = true
+			result = append(result, alternative)
+		}
+	}
+	if len(result) == 1 {
+		return result[0]
+	}
+	return jsonObject{"oneOf": result}
+}
+
+// collectFields collects the descriptions and field mappers of the fields of a type by json name. Embedded structs and
+// structs the mappers embed, such as the spec of a service, are collected after the fields of the type, which hide
+// fields of the same name the way they do for json.
+func collectFields(t reflect.Type, schema *schemas.Schema, result map[string]fieldInfo) {
+	var (
+		docs     = swaggerDoc(t)
+		embedded []reflect.Type
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case name == "-" || field.PkgPath != "":
+			continue
+		case (field.Anonymous && name == "") || strings.Contains(tag, ",inline"):
+			embedded = append(embedded, fieldType)
+			continue
+		case name == "":
+			name = field.Name
+		}
+
+		if _, ok := schema.ResourceFields[name]; !ok && fieldType.Kind() == reflect.Struct {
+			embedded = append(embedded, fieldType)
+			continue
+		}
+		if _, ok := result[name]; ok {
+			continue
+		}
+		result[name] = fieldInfo{
+			description: docs[name],
+			mappers:     parseMappers(field.Tag.Get("mapper")),
+			goType:      fieldType,
+		}
+	}
+
+	for _, t := range embedded {
+		collectFields(t, schema, result)
+	}
+}
+
+// parseMappers parses a mapper tag such as enum=a|b,alias=c into the mappers and their options
+func parseMappers(tag string) map[string][]string {
+	result := map[string][]string{}
+	for _, mapper := range strings.Split(tag, ",") {
+		if mapper == "" {
+			continue
+		}
+		name, opts := kv.Split(mapper, "=")
+		result[name] = nil
+		if opts != "" {
+			result[name] = strings.Split(opts, "|")
+		}
+	}
+	return result
+}
+
+// swaggerDoc returns the descriptions of the Kubernetes types and the types with generated SwaggerDoc methods
+func swaggerDoc(t reflect.Type) map[string]string {
+	if doc, ok := reflect.Zero(t).Interface().(interface{ SwaggerDoc() map[string]string }); ok {
+		return doc.SwaggerDoc()
+	}
+	return nil
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -2,6 +2,7 @@ package mappers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rancher/wrangler/pkg/data"
@@ -36,9 +37,15 @@ func (e EnvMapper) ToInternal(data data.Object) error {
 		return nil
 	}
 
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	var result []interface{}
-	for k, v := range m {
-		item := fmt.Sprintf("%s%s%s", k, e.Sep, v)
+	for _, k := range keys {
+		item := fmt.Sprintf("%s%s%s", k, e.Sep, m[k])
 		result = append(result, item)
 	}
 
@@ -0,0 +1,60 @@
+package mappers
+
+import (
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"github.com/rancher/wrangler/pkg/schemas/mappers"
+	"github.com/sirupsen/logrus"
+)
+
+// HandlerMapper accepts a command or http url for a lifecycle hook field
+type HandlerMapper struct {
+	mappers.DefaultMapper
+}
+
+func NewHandler(field string, _ ...string) schemas.Mapper {
+	return HandlerMapper{
+		DefaultMapper: mappers.DefaultMapper{
+			Field: field,
+		},
+	}
+}
+
+func (h HandlerMapper) FromInternal(data data.Object) {
+	v, ok := data[h.Field]
+	if !ok || v == nil {
+		return
+	}
+
+	handler := stringers.HandlerStringer{}
+	if err := convert.ToObj(v, &handler.Handler); err != nil {
+		logrus.Errorf("Failed to unmarshal lifecycle hook: %v", err)
+		return
+	}
+
+	if str, ok := handler.MaybeString().(string); ok {
+		data[h.Field] = str
+	}
+}
+
+func (h HandlerMapper) ToInternal(data data.Object) error {
+	v, ok := data[h.Field]
+	if !ok {
+		return nil
+	}
+
+	str, ok := v.(string)
+	if !ok {
+		return nil
+	}
+
+	handler, err := stringers.ParseHandler(str)
+	if err != nil {
+		return err
+	}
+
+	data[h.Field], err = convert.EncodeToMap(handler)
+	return err
+}
@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"github.com/rancher/wrangler/pkg/schemas/mappers"
+)
+
+// Output writes a field under one of its aliases when converting back to the Dollyfile format
+type Output struct {
+	mappers.DefaultMapper
+	Name string
+}
+
+func NewOutput(field string, opts ...string) schemas.Mapper {
+	o := Output{
+		DefaultMapper: mappers.DefaultMapper{
+			Field: field,
+		},
+	}
+	if len(opts) > 0 {
+		o.Name = opts[0]
+	}
+	return o
+}
+
+func (o Output) FromInternal(data data.Object) {
+	v, ok := data[o.Field]
+	if !ok || o.Name == "" {
+		return
+	}
+	delete(data, o.Field)
+	data[o.Name] = v
+}
@@ -0,0 +1,80 @@
+package mappers
+
+import (
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"github.com/rancher/wrangler/pkg/schemas/mappers"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ProbeMapper accepts the short probe syntax and docker compose healthchecks for a probe field
+type ProbeMapper struct {
+	mappers.DefaultMapper
+}
+
+func NewProbe(field string, _ ...string) schemas.Mapper {
+	return ProbeMapper{
+		DefaultMapper: mappers.DefaultMapper{
+			Field: field,
+		},
+	}
+}
+
+func (p ProbeMapper) FromInternal(data data.Object) {
+	v, ok := data[p.Field]
+	if !ok || v == nil {
+		return
+	}
+
+	probe := stringers.ProbeStringer{}
+	if err := convert.ToObj(v, &probe.Probe); err != nil {
+		logrus.Errorf("Failed to unmarshal probe: %v", err)
+		return
+	}
+
+	if str, ok := probe.MaybeString().(string); ok {
+		data[p.Field] = str
+	}
+}
+
+func (p ProbeMapper) ToInternal(data data.Object) error {
+	v, ok := data[p.Field]
+	if !ok {
+		return nil
+	}
+
+	var (
+		probe *v1.Probe
+		err   error
+	)
+
+	switch value := v.(type) {
+	case string:
+		parsed, err := stringers.ParseProbe(value)
+		if err != nil {
+			return err
+		}
+		probe = &parsed
+	case map[string]interface{}:
+		if _, compose := value["test"]; !compose && !convert.ToBool(value["disable"]) {
+			return nil
+		}
+		probe, err = stringers.ParseComposeHealthcheck(value)
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if probe == nil {
+		delete(data, p.Field)
+		return nil
+	}
+
+	data[p.Field], err = convert.EncodeToMap(probe)
+	return err
+}
@@ -3,20 +3,42 @@ package mappers
 import (
 	"github.com/rancher/dolly/pkg/dollyfile/stringers"
 	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
 	"github.com/rancher/wrangler/pkg/schemas"
 	"github.com/rancher/wrangler/pkg/schemas/mappers"
 )
 
 type QuantityMapper struct {
 	mappers.DefaultMapper
+	// Milli stores the quantity in milli units, used for cpu
+	Milli bool
 }
 
 func NewQuantity(field string, args ...string) schemas.Mapper {
-	return QuantityMapper{
+	q := QuantityMapper{
 		DefaultMapper: mappers.DefaultMapper{
 			Field: field,
 		},
 	}
+
+	for _, arg := range args {
+		if arg == "milli" {
+			q.Milli = true
+		}
+	}
+
+	return q
+}
+
+func (d QuantityMapper) FromInternal(data data.Object) {
+	v, ok := data[d.Field]
+	if !ok {
+		return
+	}
+
+	if n, err := convert.ToNumber(v); err == nil {
+		data[d.Field] = stringers.FormatResourceQuantity(n, d.Milli)
+	}
 }
 
 func (d QuantityMapper) ToInternal(data data.Object) error {
@@ -25,15 +47,11 @@ func (d QuantityMapper) ToInternal(data data.Object) error {
 		return nil
 	}
 
-	if s, ok := v.(string); ok {
-		q, err := stringers.ParseQuantity(s)
-		if err != nil {
-			return err
-		}
-		if !q.IsZero() {
-			data[d.Field], _ = q.AsInt64()
-		}
+	q, err := stringers.ParseResourceQuantity(v, d.Milli)
+	if err != nil {
+		return err
 	}
+	data[d.Field] = q
 
 	return nil
 }
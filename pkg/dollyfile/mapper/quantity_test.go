@@ -0,0 +1,78 @@
+package mappers
+
+import (
+	"testing"
+
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityCPU(t *testing.T) {
+	mapper := NewQuantity("cpuMillis", "milli")
+	for value, millis := range map[interface{}]int64{
+		1:        1000,
+		0.5:      500,
+		"0.5":    500,
+		"500m":   500,
+		"2":      2000,
+		int64(3): 3000,
+	} {
+		obj := data.Object{"cpuMillis": value}
+		assert.NoError(t, mapper.ToInternal(obj), "%v", value)
+		assert.Equal(t, millis, obj["cpuMillis"], "%v", value)
+	}
+
+	assert.Error(t, mapper.ToInternal(data.Object{"cpuMillis": "half"}))
+}
+
+func TestQuantityMemory(t *testing.T) {
+	mapper := NewQuantity("memoryBytes")
+	for value, bytes := range map[interface{}]int64{
+		1024:   1024,
+		"1024": 1024,
+		"64Mi": 64 * 1024 * 1024,
+		"1G":   1000 * 1000 * 1000,
+	} {
+		obj := data.Object{"memoryBytes": value}
+		assert.NoError(t, mapper.ToInternal(obj), "%v", value)
+		assert.Equal(t, bytes, obj["memoryBytes"], "%v", value)
+	}
+}
+
+func TestRequestLimitCPU(t *testing.T) {
+	split := NewRequestLimit("cpuMillis", "cpuLimitMillis", "milli")
+	quantity := NewQuantity("cpuMillis", "milli")
+
+	for _, test := range []struct {
+		value          interface{}
+		request, limit int64
+	}{
+		{value: "500:1000", request: 500000, limit: 1000000},
+		{value: "100m:1", request: 100, limit: 1000},
+		{value: "0.5:1.5", request: 500, limit: 1500},
+		{value: map[string]interface{}{"request": 1, "limit": 2}, request: 1000, limit: 2000},
+		{value: map[string]interface{}{"request": 0.25, "limit": "500m"}, request: 250, limit: 500},
+		{value: map[string]interface{}{"request": "100m", "limit": 1.5}, request: 100, limit: 1500},
+	} {
+		obj := data.Object{"cpuMillis": test.value}
+		if assert.NoError(t, split.ToInternal(obj), "%v", test.value) && assert.NoError(t, quantity.ToInternal(obj), "%v", test.value) {
+			assert.Equal(t, test.request, obj["cpuMillis"], "%v", test.value)
+			assert.Equal(t, test.limit, obj["cpuLimitMillis"], "%v", test.value)
+		}
+	}
+}
+
+func TestRequestLimitFormat(t *testing.T) {
+	split := NewRequestLimit("cpuMillis", "cpuLimitMillis", "milli")
+	quantity := NewQuantity("cpuMillis", "milli")
+
+	obj := data.Object{"cpuMillis": int64(100), "cpuLimitMillis": int64(1500)}
+	quantity.FromInternal(obj)
+	split.FromInternal(obj)
+	assert.Equal(t, data.Object{"cpuMillis": "100m:1500m"}, obj)
+
+	obj = data.Object{"cpuMillis": int64(2000)}
+	quantity.FromInternal(obj)
+	split.FromInternal(obj)
+	assert.Equal(t, data.Object{"cpuMillis": "2"}, obj)
+}
@@ -0,0 +1,104 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"github.com/rancher/wrangler/pkg/schemas/mappers"
+)
+
+// RequestLimit splits a `request:limit` string or a {request, limit} object into the request field and
+// a separate limit field
+type RequestLimit struct {
+	mappers.DefaultMapper
+	LimitField string
+	Milli      bool
+}
+
+func NewRequestLimit(field string, opts ...string) schemas.Mapper {
+	r := RequestLimit{
+		DefaultMapper: mappers.DefaultMapper{
+			Field: field,
+		},
+	}
+
+	for _, opt := range opts {
+		if opt == "milli" {
+			r.Milli = true
+		} else {
+			r.LimitField = opt
+		}
+	}
+
+	return r
+}
+
+func (d RequestLimit) FromInternal(data data.Object) {
+	limit, ok := data[d.LimitField]
+	if !ok {
+		return
+	}
+
+	request := ""
+	if v, ok := data[d.Field]; ok {
+		request = d.format(v)
+	}
+
+	delete(data, d.LimitField)
+	data[d.Field] = request + ":" + d.format(limit)
+}
+
+// format formats a value in bytes or milli units, strings are already formatted by the quantity mapper of the field
+func (d RequestLimit) format(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if n, err := convert.ToNumber(v); err == nil {
+		return stringers.FormatResourceQuantity(n, d.Milli)
+	}
+	return convert.ToString(v)
+}
+
+func (d RequestLimit) ToInternal(data data.Object) error {
+	v, ok := data[d.Field]
+	if !ok {
+		return nil
+	}
+
+	var request, limit interface{}
+	switch value := v.(type) {
+	case string:
+		if !strings.Contains(value, ":") {
+			return nil
+		}
+		request, limit = kv.Split(value, ":")
+	case map[string]interface{}:
+		request, limit = value["request"], value["limit"]
+	default:
+		return nil
+	}
+
+	delete(data, d.Field)
+	if !convert.IsEmptyValue(request) {
+		// the request is left to the quantity mapper of the field
+		data[d.Field] = request
+	}
+
+	if !convert.IsEmptyValue(limit) {
+		q, err := stringers.ParseResourceQuantity(limit, d.Milli)
+		if err != nil {
+			return err
+		}
+		data[d.LimitField] = q
+	}
+
+	return nil
+}
+
+func (d RequestLimit) ModifySchema(schema *schemas.Schema, schemas *schemas.Schemas) error {
+	return mappers.ValidateField(d.LimitField, schema)
+}
@@ -0,0 +1,62 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/kv"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"github.com/rancher/wrangler/pkg/schemas/mappers"
+)
+
+// StringMap accepts a `key=value,key=value` string or a list of `key=value` strings for a map field
+type StringMap struct {
+	mappers.DefaultMapper
+	Sep string
+}
+
+func NewStringMap(field string, opts ...string) schemas.Mapper {
+	m := StringMap{
+		DefaultMapper: mappers.DefaultMapper{
+			Field: field,
+		},
+		Sep: "=",
+	}
+
+	for _, opt := range opts {
+		if strings.HasPrefix(opt, "sep=") {
+			m.Sep = strings.TrimPrefix(opt, "sep=")
+		}
+	}
+
+	return m
+}
+
+func (m StringMap) ToInternal(data data.Object) error {
+	v, ok := data[m.Field]
+	if !ok {
+		return nil
+	}
+
+	var items []string
+	switch value := v.(type) {
+	case string:
+		items = strings.Split(value, ",")
+	case []interface{}:
+		items = convert.ToStringSlice(value)
+	default:
+		return nil
+	}
+
+	result := map[string]interface{}{}
+	for _, item := range items {
+		k, v := kv.Split(strings.TrimSpace(item), m.Sep)
+		if k != "" {
+			result[k] = v
+		}
+	}
+
+	data[m.Field] = result
+	return nil
+}
@@ -0,0 +1,54 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/schemas"
+	"github.com/rancher/wrangler/pkg/schemas/mappers"
+)
+
+// StringSlice accepts a comma separated string for a list of strings
+type StringSlice struct {
+	mappers.DefaultMapper
+}
+
+func NewStringSlice(field string, _ ...string) schemas.Mapper {
+	return StringSlice{
+		DefaultMapper: mappers.DefaultMapper{
+			Field: field,
+		},
+	}
+}
+
+func (s StringSlice) FromInternal(data data.Object) {
+	v, ok := data[s.Field]
+	if !ok {
+		return
+	}
+
+	parts := convert.ToStringSlice(v)
+	if len(parts) == 1 {
+		data[s.Field] = parts[0]
+	}
+}
+
+func (s StringSlice) ToInternal(data data.Object) error {
+	v, ok := data[s.Field]
+	if !ok {
+		return nil
+	}
+
+	if str, ok := v.(string); ok {
+		var result []interface{}
+		for _, part := range strings.Split(str, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+		data[s.Field] = result
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package dollyfile
+
+import (
+	"github.com/rancher/wrangler/pkg/data"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"sigs.k8s.io/yaml"
+)
+
+// Marshal converts a DollyFile into the compact Dollyfile format, the reverse of Parse
+func Marshal(rf *DollyFile) ([]byte, error) {
+	obj, err := convert.EncodeToMap(rf)
+	if err != nil {
+		return nil, err
+	}
+
+	Schema.Schema("DollyFile").Mapper.FromInternal(obj)
+
+	// names are the keys of the maps and the namespace is set when the Dollyfile is applied
+	for _, service := range data.Object(obj).Map("services") {
+		service := convert.ToMapInterface(service)
+		delete(service, "name")
+		delete(service, "namespace")
+	}
+	for _, config := range data.Object(obj).Map("configs") {
+		config := convert.ToMapInterface(config)
+		delete(config, "metadata")
+		delete(config, "kind")
+		delete(config, "apiVersion")
+	}
+
+	return yaml.Marshal(obj)
+}
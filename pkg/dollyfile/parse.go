@@ -60,7 +60,7 @@ func renderK8sObject(rf *DollyFile, namespace string) (*DollyFile, error) {
 		v.Namespace = namespace
 		rf.Services[k] = v
 	}
-	return rf, nil
+	return rf, rf.Validate()
 }
 
 func isK8SYaml(contents []byte) (bool, []runtime.Object, error) {
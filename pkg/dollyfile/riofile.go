@@ -18,12 +18,27 @@ type Plugin interface {
 }
 
 type DollyFile struct {
-	Services   map[string]types.Service `json:"services,omitempty"`
-	Configs    map[string]v1.ConfigMap  `json:"configs,omitempty"`
-	Routes     map[string]types.Router  `json:"routes,omitempty"`
-	Kubernetes []runtime.Object         `json:"kubernetes,omitempty"`
-	Manifest   string                   `json:"manifest,omitempty"`
-	Plugins    []Plugin                 `json:"-"`
+	// Services to run, by name
+	Services map[string]types.Service `json:"services,omitempty"`
+	// ConfigMaps to create, by name. The keys and values are the data of the ConfigMap
+	Configs map[string]v1.ConfigMap `json:"configs,omitempty"`
+	// Routes creating an Ingress for the hostnames and paths of services, by name
+	Routes map[string]types.Router `json:"routes,omitempty"`
+	// Kubernetes objects applied together with the services
+	Kubernetes []runtime.Object `json:"kubernetes,omitempty"`
+	// Kubernetes manifest applied together with the services, as yaml
+	Manifest string `json:"manifest,omitempty"`
+
+	Plugins []Plugin `json:"-"`
+
+	// Resource requests of containers that don't specify any
+	DefaultResources *types.DefaultResources `json:"defaultResources,omitempty"`
+
+	// Network isolation of the services. strict denies all traffic that is not declared with connects or allowFrom
+	Isolation types.Isolation `json:"isolation,omitempty" mapper:"enum=strict|none"`
+
+	// Ingress controller exposing the hostnames of the services, detected by dolly up if not set
+	Ingress *types.Ingress `json:"ingress,omitempty"`
 }
 
 func (r *DollyFile) Objects() []runtime.Object {
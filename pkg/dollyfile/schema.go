@@ -23,17 +23,30 @@ func init() {
 		Init(services).
 		Init(configs).
 		TypeName("DollyFile", DollyFile{}).
-		MustImport(DollyFile{})
+		MustImport(DollyFile{}).
+		MustImport(types.TemplateMeta{})
+
+	// the hostnames of a service share the json name hostname with the hostname of the pod, which they hide
+	service := Schema.Schema("service")
+	hostname := service.ResourceFields["hostname"]
+	hostname.Type = "array[string]"
+	service.ResourceFields["hostname"] = hostname
 }
 
 func mappers(schemas *schemas.Schemas) *schemas.Schemas {
 	return objectToSlice(schemas).
 		AddFieldMapper("alias", m.NewAlias).
+		AddFieldMapper("output", dollyfilemapper.NewOutput).
 		AddFieldMapper("duration", dollyfilemapper.NewDuration).
+		AddFieldMapper("probe", dollyfilemapper.NewProbe).
+		AddFieldMapper("handler", dollyfilemapper.NewHandler).
 		AddFieldMapper("quantity", dollyfilemapper.NewQuantity).
+		AddFieldMapper("requestLimit", dollyfilemapper.NewRequestLimit).
 		AddFieldMapper("enum", m.NewEnum).
 		AddFieldMapper("hostNetwork", dollyfilemapper.NewHostNetwork).
 		AddFieldMapper("envmap", dollyfilemapper.NewEnvMap).
+		AddFieldMapper("stringMap", dollyfilemapper.NewStringMap).
+		AddFieldMapper("stringSlice", dollyfilemapper.NewStringSlice).
 		AddFieldMapper("shlex", dollyfilemapper.NewShlex)
 }
 
@@ -87,6 +100,27 @@ func objectToSlice(schemas *schemas.Schemas) *schemas.Schemas {
 		func(str string) (interface{}, error) {
 			return stringers.ParseVolume(str)
 		}))
+	schemas.AddFieldMapper("sysctls", dollyfilemapper.NewObjectsToSliceFactory(
+		func() dollyfilemapper.MaybeStringer {
+			return &stringers.SysctlStringer{}
+		},
+		func(str string) (interface{}, error) {
+			return stringers.ParseSysctl(str)
+		}))
+	schemas.AddFieldMapper("tolerations", dollyfilemapper.NewObjectsToSliceFactory(
+		func() dollyfilemapper.MaybeStringer {
+			return &stringers.TolerationStringer{}
+		},
+		func(str string) (interface{}, error) {
+			return stringers.ParseToleration(str)
+		}))
+	schemas.AddFieldMapper("topologyRules", dollyfilemapper.NewObjectsToSliceFactory(
+		func() dollyfilemapper.MaybeStringer {
+			return &stringers.TopologyRuleStringer{}
+		},
+		func(str string) (interface{}, error) {
+			return stringers.ParseTopologyRule(str)
+		}))
 	schemas.AddFieldMapper("permissions", dollyfilemapper.NewObjectsToSliceFactory(
 		func() dollyfilemapper.MaybeStringer {
 			return &stringers.PermissionStringer{}
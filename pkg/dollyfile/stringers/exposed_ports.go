@@ -14,6 +14,9 @@ var (
 	keywords = map[string]bool{
 		"hostport": true,
 		"expose":   true,
+		"internal": true,
+		"nodeport": true,
+		"type":     true,
 	}
 )
 
@@ -85,8 +88,17 @@ func (e ContainerPortStringer) MaybeString() interface{} {
 		buf.WriteString(",hostport")
 	}
 
+	if e.NodePort > 0 {
+		buf.WriteString(fmt.Sprintf(",nodePort=%d", e.NodePort))
+	}
+
+	if e.ServiceType != "" {
+		buf.WriteString(",type=")
+		buf.WriteString(string(e.ServiceType))
+	}
+
 	if e.Name != "" {
-		if keywords[e.Name] {
+		if keywords[strings.ToLower(e.Name)] {
 			buf.WriteString(",name=")
 			buf.WriteString(e.Name)
 		} else {
@@ -113,7 +125,7 @@ func ParsePort(spec string) (result v1.ContainerPort, err error) {
 	parts := strings.Split(spec, ",")
 
 	for k, v := range kv.SplitMapFromSlice(parts[1:]) {
-		switch k {
+		switch strings.ToLower(k) {
 		case "name":
 			result.Name = v
 		case "expose":
@@ -122,6 +134,17 @@ func ParsePort(spec string) (result v1.ContainerPort, err error) {
 			result.Expose = &[]bool{false}[0]
 		case "hostport":
 			result.HostPort = true
+		case "nodeport":
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return result, errors.Wrapf(err, "failed to parse nodePort %s", v)
+			}
+			result.NodePort = int32(n)
+		case "type":
+			result.ServiceType, err = ParseServiceType(v)
+			if err != nil {
+				return result, err
+			}
 		default:
 			result.Name = k
 		}
@@ -0,0 +1,45 @@
+package stringers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+	v1 "k8s.io/api/core/v1"
+)
+
+type HandlerStringer struct {
+	v1.Handler
+}
+
+// MaybeString returns the short form of the lifecycle hook, or the hook itself if it uses fields the short form can't express
+func (h HandlerStringer) MaybeString() interface{} {
+	if target, ok := handlerTarget(h.Handler, ""); ok {
+		return target
+	}
+	return h.Handler
+}
+
+// ParseHandler parses a lifecycle hook, either `http://[host]:port/path`, `https://[host]:port/path` or a command
+func ParseHandler(handler string) (result v1.Handler, err error) {
+	switch {
+	case strings.HasPrefix(handler, probeHTTP), strings.HasPrefix(handler, probeHTTPS),
+		strings.HasPrefix(handler, probeCmd), strings.HasPrefix(handler, probeExec):
+		probe, err := ParseProbe(handler)
+		return probe.Handler, err
+	case strings.HasPrefix(handler, probeTCP):
+		return result, fmt.Errorf("lifecycle hook %s can not be a tcp check", handler)
+	}
+
+	args, err := shellwords.Parse(handler)
+	if err != nil {
+		return result, fmt.Errorf("invalid lifecycle hook %s: %v", handler, err)
+	}
+	if len(args) == 0 {
+		return result, fmt.Errorf("lifecycle hook is missing a command")
+	}
+	result.Exec = &v1.ExecAction{
+		Command: args,
+	}
+	return result, nil
+}
@@ -0,0 +1,247 @@
+package stringers
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"github.com/rancher/wrangler/pkg/kv"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	probeHTTP  = "http://"
+	probeHTTPS = "https://"
+	probeTCP   = "tcp://"
+	probeCmd   = "cmd:"
+	probeExec  = "exec:"
+)
+
+type ProbeStringer struct {
+	v1.Probe
+}
+
+// MaybeString returns the short form of the probe, or the probe itself if it uses fields the short form can't express
+func (p ProbeStringer) MaybeString() interface{} {
+	target, ok := p.target()
+	if !ok {
+		return p.Probe
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString(target)
+	writeSeconds(buf, "delay", p.InitialDelaySeconds)
+	writeSeconds(buf, "interval", p.PeriodSeconds)
+	writeSeconds(buf, "timeout", p.TimeoutSeconds)
+	writeInt(buf, "threshold", p.FailureThreshold)
+	writeInt(buf, "success", p.SuccessThreshold)
+	return buf.String()
+}
+
+func (p ProbeStringer) target() (string, bool) {
+	return handlerTarget(p.Handler, probeCmd)
+}
+
+func handlerTarget(h v1.Handler, cmdPrefix string) (string, bool) {
+	switch {
+	case h.HTTPGet != nil && h.TCPSocket == nil && h.Exec == nil:
+		if len(h.HTTPGet.HTTPHeaders) > 0 {
+			return "", false
+		}
+		prefix := probeHTTP
+		if h.HTTPGet.Scheme == v1.URISchemeHTTPS {
+			prefix = probeHTTPS
+		}
+		return prefix + net.JoinHostPort(h.HTTPGet.Host, h.HTTPGet.Port.String()) + h.HTTPGet.Path, true
+	case h.TCPSocket != nil && h.HTTPGet == nil && h.Exec == nil:
+		return probeTCP + net.JoinHostPort(h.TCPSocket.Host, h.TCPSocket.Port.String()), true
+	case h.Exec != nil && h.HTTPGet == nil && h.TCPSocket == nil:
+		var args []string
+		for _, arg := range h.Exec.Command {
+			if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`,=") {
+				arg = strconv.Quote(arg)
+			}
+			args = append(args, arg)
+		}
+		return cmdPrefix + strings.Join(args, " "), true
+	}
+	return "", false
+}
+
+func writeSeconds(buf *strings.Builder, key string, seconds int32) {
+	if seconds > 0 {
+		buf.WriteString(fmt.Sprintf(",%s=%s", key, time.Duration(seconds)*time.Second))
+	}
+}
+
+func writeInt(buf *strings.Builder, key string, value int32) {
+	if value > 0 {
+		buf.WriteString(fmt.Sprintf(",%s=%d", key, value))
+	}
+}
+
+// ParseProbe parses `http://[host]:port/path`, `https://[host]:port/path`, `tcp://[host]:port` or `cmd:command args`
+// followed by the options delay, interval, timeout, threshold and success, for example `http://:8080/ping,interval=10s,threshold=3`
+func ParseProbe(probe string) (result v1.Probe, err error) {
+	target, opts := splitProbeOptions(probe)
+
+	switch {
+	case strings.HasPrefix(target, probeHTTP), strings.HasPrefix(target, probeHTTPS):
+		scheme, address := kv.Split(target, "://")
+		hostPort, path := address, "/"
+		if i := strings.Index(address, "/"); i >= 0 {
+			hostPort, path = address[:i], address[i:]
+		}
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil || port == "" {
+			return result, fmt.Errorf("invalid http probe %s, must be http://[host]:port/path", target)
+		}
+		result.HTTPGet = &v1.HTTPGetAction{
+			Host: host,
+			Port: intstr.FromString(port),
+			Path: path,
+		}
+		if scheme == "https" {
+			result.HTTPGet.Scheme = v1.URISchemeHTTPS
+		}
+	case strings.HasPrefix(target, probeTCP):
+		host, port, err := net.SplitHostPort(strings.TrimPrefix(target, probeTCP))
+		if err != nil {
+			return result, fmt.Errorf("invalid tcp probe %s: %v", target, err)
+		}
+		result.TCPSocket = &v1.TCPSocketAction{
+			Host: host,
+			Port: intstr.FromString(port),
+		}
+	case strings.HasPrefix(target, probeCmd), strings.HasPrefix(target, probeExec):
+		_, cmd := kv.Split(target, ":")
+		args, err := shellwords.Parse(cmd)
+		if err != nil {
+			return result, fmt.Errorf("invalid command probe %s: %v", target, err)
+		}
+		if len(args) == 0 {
+			return result, fmt.Errorf("command probe %s is missing a command", target)
+		}
+		result.Exec = &v1.ExecAction{
+			Command: args,
+		}
+	default:
+		return result, fmt.Errorf("%s does not match format http://[host]:port/path, tcp://[host]:port or cmd:command", target)
+	}
+
+	for _, opt := range opts {
+		k, v := kv.Split(opt, "=")
+		switch strings.ToLower(k) {
+		case "delay", "initialdelay":
+			result.InitialDelaySeconds, err = parseSeconds(v)
+		case "interval", "period":
+			result.PeriodSeconds, err = parseSeconds(v)
+		case "timeout":
+			result.TimeoutSeconds, err = parseSeconds(v)
+		case "threshold", "retries":
+			result.FailureThreshold, err = parseInt32(v)
+		case "success":
+			result.SuccessThreshold, err = parseInt32(v)
+		}
+		if err != nil {
+			return result, fmt.Errorf("invalid probe option %s: %v", opt, err)
+		}
+	}
+
+	return result, nil
+}
+
+// splitProbeOptions splits the trailing options from the probe target, commands are allowed to contain commas
+func splitProbeOptions(probe string) (string, []string) {
+	parts := strings.Split(probe, ",")
+	i := len(parts)
+	for i > 1 && isProbeOption(parts[i-1]) {
+		i--
+	}
+	return strings.TrimSpace(strings.Join(parts[:i], ",")), parts[i:]
+}
+
+func isProbeOption(opt string) bool {
+	k, _ := kv.Split(opt, "=")
+	switch strings.ToLower(k) {
+	case "delay", "initialdelay", "interval", "period", "timeout", "threshold", "retries", "success":
+		return strings.Contains(opt, "=")
+	}
+	return false
+}
+
+// ParseComposeHealthcheck translates a docker compose healthcheck. A nil probe is returned for a disabled healthcheck
+func ParseComposeHealthcheck(healthcheck map[string]interface{}) (*v1.Probe, error) {
+	if convert.ToBool(healthcheck["disable"]) {
+		return nil, nil
+	}
+
+	result := &v1.Probe{}
+	switch test := healthcheck["test"].(type) {
+	case string:
+		result.Exec = &v1.ExecAction{
+			Command: []string{"/bin/sh", "-c", test},
+		}
+	case []interface{}:
+		args := convert.ToStringSlice(test)
+		if len(args) == 0 || args[0] == "NONE" {
+			return nil, nil
+		}
+		switch args[0] {
+		case "CMD":
+			result.Exec = &v1.ExecAction{
+				Command: args[1:],
+			}
+		case "CMD-SHELL":
+			result.Exec = &v1.ExecAction{
+				Command: []string{"/bin/sh", "-c", strings.Join(args[1:], " ")},
+			}
+		default:
+			return nil, fmt.Errorf("healthcheck test must start with NONE, CMD or CMD-SHELL, got %s", args[0])
+		}
+	default:
+		return nil, fmt.Errorf("healthcheck test must be a string or a list")
+	}
+
+	var err error
+	for key, target := range map[string]*int32{
+		"interval":     &result.PeriodSeconds,
+		"timeout":      &result.TimeoutSeconds,
+		"start_period": &result.InitialDelaySeconds,
+	} {
+		if v, ok := healthcheck[key]; ok {
+			if *target, err = parseSeconds(convert.ToString(v)); err != nil {
+				return nil, fmt.Errorf("invalid healthcheck %s: %v", key, err)
+			}
+		}
+	}
+
+	if v, ok := healthcheck["retries"]; ok {
+		if result.FailureThreshold, err = parseInt32(convert.ToString(v)); err != nil {
+			return nil, fmt.Errorf("invalid healthcheck retries: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+func parseSeconds(value string) (int32, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return int32(n), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return int32(d.Round(time.Second) / time.Second), nil
+}
+
+func parseInt32(value string) (int32, error) {
+	n, err := strconv.Atoi(value)
+	return int32(n), err
+}
@@ -1,6 +1,11 @@
 package stringers
 
-import "k8s.io/apimachinery/pkg/api/resource"
+import (
+	"strconv"
+
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
 
 func ParseQuantity(num string) (result resource.Quantity, err error) {
 	if num == "" {
@@ -9,3 +14,29 @@ func ParseQuantity(num string) (result resource.Quantity, err error) {
 
 	return resource.ParseQuantity(num)
 }
+
+// ParseResourceQuantity parses a quantity into whole units, or into milli units if milli is set. Plain numbers
+// are whole units, so a cpu of 0.5 is 500 milli units
+func ParseResourceQuantity(value interface{}, milli bool) (int64, error) {
+	s := convert.ToString(value)
+	if f, ok := value.(float64); ok {
+		// yaml floats such as 0.5, without an exponent the quantity parser doesn't accept
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	q, err := ParseQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	if milli {
+		return q.MilliValue(), nil
+	}
+	return q.Value(), nil
+}
+
+func FormatResourceQuantity(value int64, milli bool) string {
+	if milli {
+		return resource.NewMilliQuantity(value, resource.DecimalSI).String()
+	}
+	return resource.NewQuantity(value, resource.BinarySI).String()
+}
@@ -0,0 +1,185 @@
+package stringers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/wrangler/pkg/kv"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	topologyKeys = map[string]string{
+		"zone":     corev1.LabelZoneFailureDomainStable,
+		"region":   corev1.LabelZoneRegionStable,
+		"host":     corev1.LabelHostname,
+		"hostname": corev1.LabelHostname,
+		"node":     corev1.LabelHostname,
+	}
+	taintEffects = map[string]corev1.TaintEffect{
+		"noschedule":       corev1.TaintEffectNoSchedule,
+		"prefernoschedule": corev1.TaintEffectPreferNoSchedule,
+		"noexecute":        corev1.TaintEffectNoExecute,
+	}
+)
+
+// TopologyKey resolves the zone, region and host shorthands to their well known node labels
+func TopologyKey(topology string) string {
+	if key, ok := topologyKeys[strings.ToLower(topology)]; ok {
+		return key
+	}
+	return topology
+}
+
+type TolerationStringer struct {
+	corev1.Toleration
+}
+
+func (t TolerationStringer) MaybeString() interface{} {
+	buf := &strings.Builder{}
+	if t.Key == "" && t.Operator == corev1.TolerationOpExists {
+		buf.WriteString("*")
+	} else {
+		buf.WriteString(t.Key)
+	}
+
+	if t.Operator != corev1.TolerationOpExists {
+		buf.WriteString("=")
+		buf.WriteString(t.Value)
+	}
+
+	if t.Effect != "" {
+		buf.WriteString(":")
+		buf.WriteString(string(t.Effect))
+	}
+
+	if t.TolerationSeconds != nil {
+		buf.WriteString(fmt.Sprintf(",seconds=%d", *t.TolerationSeconds))
+	}
+
+	return buf.String()
+}
+
+func ParseTolerations(tolerations ...string) (result []corev1.Toleration, err error) {
+	for _, toleration := range tolerations {
+		t, err := ParseToleration(toleration)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return
+}
+
+// ParseToleration parses `key[=value][:effect][,seconds=N]`, a toleration without value tolerates any value
+// of the key and `*` tolerates every taint
+func ParseToleration(toleration string) (result corev1.Toleration, err error) {
+	parts := strings.Split(toleration, ",")
+	keyValue, effect := kv.Split(parts[0], ":")
+	key, value := kv.Split(keyValue, "=")
+
+	result.Key = key
+	if strings.Contains(keyValue, "=") {
+		result.Operator = corev1.TolerationOpEqual
+		result.Value = value
+	} else {
+		result.Operator = corev1.TolerationOpExists
+	}
+
+	if key == "*" {
+		result.Key = ""
+		result.Operator = corev1.TolerationOpExists
+	} else if key == "" {
+		return result, fmt.Errorf("%s does not match format key[=value][:effect]", toleration)
+	}
+
+	if effect != "" {
+		result.Effect = taintEffects[strings.ToLower(effect)]
+		if result.Effect == "" {
+			return result, fmt.Errorf("invalid taint effect %s, must be NoSchedule, PreferNoSchedule or NoExecute", effect)
+		}
+	}
+
+	for k, v := range kv.SplitMapFromSlice(parts[1:]) {
+		switch strings.ToLower(k) {
+		case "seconds", "tolerationseconds":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return result, fmt.Errorf("failed to parse toleration seconds %s: %v", v, err)
+			}
+			result.TolerationSeconds = &n
+		default:
+			return result, fmt.Errorf("invalid toleration option %s", k)
+		}
+	}
+
+	return result, nil
+}
+
+type TopologyRuleStringer struct {
+	v1.TopologyRule
+}
+
+func (t TopologyRuleStringer) MaybeString() interface{} {
+	buf := &strings.Builder{}
+	buf.WriteString(t.Topology)
+
+	if t.Required {
+		buf.WriteString(",required")
+	}
+
+	if t.MaxSkew > 0 {
+		buf.WriteString(fmt.Sprintf(",maxSkew=%d", t.MaxSkew))
+	}
+
+	if t.Weight > 0 {
+		buf.WriteString(fmt.Sprintf(",weight=%d", t.Weight))
+	}
+
+	return buf.String()
+}
+
+func ParseTopologyRules(rules ...string) (result []v1.TopologyRule, err error) {
+	for _, rule := range rules {
+		r, err := ParseTopologyRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return
+}
+
+// ParseTopologyRule parses `topology[,required][,maxSkew=N][,weight=N]`
+func ParseTopologyRule(rule string) (result v1.TopologyRule, err error) {
+	parts := strings.Split(rule, ",")
+	result.Topology = strings.TrimSpace(parts[0])
+	if result.Topology == "" {
+		return result, fmt.Errorf("%s is missing a topology such as zone or host", rule)
+	}
+
+	for k, v := range kv.SplitMapFromSlice(parts[1:]) {
+		switch strings.ToLower(k) {
+		case "required":
+			result.Required = v != "false"
+		case "maxskew":
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return result, fmt.Errorf("failed to parse maxSkew %s: %v", v, err)
+			}
+			result.MaxSkew = int32(n)
+		case "weight":
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return result, fmt.Errorf("failed to parse weight %s: %v", v, err)
+			}
+			result.Weight = int32(n)
+		default:
+			return result, fmt.Errorf("invalid topology option %s", k)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package stringers
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	AppArmorRuntimeDefault  = "runtime/default"
+	AppArmorUnconfined      = "unconfined"
+	AppArmorLocalhostPrefix = "localhost/"
+)
+
+// ParseSeccompProfile parses `runtime/default`, `unconfined` or `localhost/<path>` into a seccomp profile
+func ParseSeccompProfile(profile string) (*v1.SeccompProfile, error) {
+	switch strings.ToLower(profile) {
+	case "":
+		return nil, nil
+	case "runtime/default", "runtimedefault", "default":
+		return &v1.SeccompProfile{
+			Type: v1.SeccompProfileTypeRuntimeDefault,
+		}, nil
+	case "unconfined":
+		return &v1.SeccompProfile{
+			Type: v1.SeccompProfileTypeUnconfined,
+		}, nil
+	}
+
+	if strings.HasPrefix(profile, "localhost/") {
+		path := strings.TrimPrefix(profile, "localhost/")
+		return &v1.SeccompProfile{
+			Type:             v1.SeccompProfileTypeLocalhost,
+			LocalhostProfile: &path,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not a valid seccomp profile, must be runtime/default, unconfined or localhost/<path>", profile)
+}
+
+func SeccompProfileString(profile *v1.SeccompProfile) string {
+	if profile == nil {
+		return ""
+	}
+	switch profile.Type {
+	case v1.SeccompProfileTypeRuntimeDefault:
+		return "runtime/default"
+	case v1.SeccompProfileTypeUnconfined:
+		return "unconfined"
+	case v1.SeccompProfileTypeLocalhost:
+		if profile.LocalhostProfile != nil {
+			return "localhost/" + *profile.LocalhostProfile
+		}
+	}
+	return ""
+}
+
+// ParseAppArmorProfile validates an AppArmor profile, which is set as a pod annotation
+func ParseAppArmorProfile(profile string) (string, error) {
+	switch strings.ToLower(profile) {
+	case "", AppArmorRuntimeDefault, AppArmorUnconfined:
+		return strings.ToLower(profile), nil
+	case "default":
+		return AppArmorRuntimeDefault, nil
+	}
+
+	if strings.HasPrefix(profile, AppArmorLocalhostPrefix) && len(profile) > len(AppArmorLocalhostPrefix) {
+		return profile, nil
+	}
+
+	return "", fmt.Errorf("%s is not a valid AppArmor profile, must be runtime/default, unconfined or localhost/<profile>", profile)
+}
@@ -0,0 +1,27 @@
+package stringers
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/rancher/dolly/pkg/types"
+)
+
+var (
+	serviceTypeNames = map[string]v1.ServiceType{
+		"clusterip":    v1.ServiceTypeClusterIP,
+		"nodeport":     v1.ServiceTypeNodePort,
+		"loadbalancer": v1.ServiceTypeLoadBalancer,
+		"none":         v1.ServiceTypeHeadless,
+		"headless":     v1.ServiceTypeHeadless,
+		"":             "",
+	}
+)
+
+func ParseServiceType(serviceType string) (v1.ServiceType, error) {
+	ret, ok := serviceTypeNames[strings.ToLower(serviceType)]
+	if !ok {
+		return ret, fmt.Errorf("%s is not a valid service type, must be ClusterIP, NodePort, LoadBalancer or None", serviceType)
+	}
+	return ret, nil
+}
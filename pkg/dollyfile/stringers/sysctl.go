@@ -0,0 +1,39 @@
+package stringers
+
+import (
+	"fmt"
+
+	"github.com/rancher/wrangler/pkg/kv"
+	v1 "k8s.io/api/core/v1"
+)
+
+type SysctlStringer struct {
+	v1.Sysctl
+}
+
+func (s SysctlStringer) MaybeString() interface{} {
+	return fmt.Sprintf("%s=%s", s.Name, s.Value)
+}
+
+func ParseSysctls(sysctls ...string) (result []v1.Sysctl, err error) {
+	for _, sysctl := range sysctls {
+		s, err := ParseSysctl(sysctl)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return
+}
+
+func ParseSysctl(sysctl string) (v1.Sysctl, error) {
+	name, value := kv.Split(sysctl, "=")
+	if name == "" || value == "" {
+		return v1.Sysctl{}, fmt.Errorf("%s does not match format name=value", sysctl)
+	}
+
+	return v1.Sysctl{
+		Name:  name,
+		Value: value,
+	}, nil
+}
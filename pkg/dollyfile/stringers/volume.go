@@ -2,6 +2,7 @@ package stringers
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -10,6 +11,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+const nfsPrefix = "nfs://"
+
 var (
 	hostPathTypes = map[string]string{
 		"directoryorcreate": "DirectoryOrCreate",
@@ -20,6 +23,22 @@ var (
 		"chardevice":        "CharDevice",
 		"blockdevice":       "BlockDevice",
 	}
+	accessModes = map[string]corev1.PersistentVolumeAccessMode{
+		"rwo":           corev1.ReadWriteOnce,
+		"readwriteonce": corev1.ReadWriteOnce,
+		"rox":           corev1.ReadOnlyMany,
+		"readonlymany":  corev1.ReadOnlyMany,
+		"rwx":           corev1.ReadWriteMany,
+		"readwritemany": corev1.ReadWriteMany,
+	}
+	// DownwardAPIFields maps the pod metadata of a projected volume to its field path
+	DownwardAPIFields = map[string]string{
+		"labels":      "metadata.labels",
+		"annotations": "metadata.annotations",
+		"name":        "metadata.name",
+		"namespace":   "metadata.namespace",
+		"uid":         "metadata.uid",
+	}
 )
 
 func ParseVolumes(vols ...string) (result []v1.Volume, err error) {
@@ -35,25 +54,32 @@ func ParseVolumes(vols ...string) (result []v1.Volume, err error) {
 
 func ParseVolume(v string) (volume v1.Volume, err error) {
 	parts := strings.Split(v, ",")
-	name, path := kv.Split(parts[0], ":")
-	if path != "" {
-		volume.Path = path
-		if strings.HasPrefix(name, "/") {
-			volume.HostPath = name
-		} else {
-			volume.Name = name
+	if strings.HasPrefix(parts[0], nfsPrefix) {
+		if err := parseNFS(&volume, parts[0]); err != nil {
+			return volume, err
 		}
 	} else {
-		volume.Path = name
+		name, path := kv.Split(parts[0], ":")
+		if path != "" {
+			volume.Path = path
+			if strings.HasPrefix(name, "/") {
+				volume.HostPath = name
+			} else {
+				volume.Name = name
+			}
+		} else {
+			volume.Path = name
+		}
 	}
 
-	for k, v := range kv.SplitMapFromSlice(parts[1:]) {
-		k = strings.ToLower(k)
-		switch k {
+	// options are iterated in order as config, secret, downward, accessMode and attr may be repeated
+	for _, part := range parts[1:] {
+		k, v := kv.Split(part, "=")
+		switch strings.ToLower(k) {
 		case "persistent":
 			value, _ := strconv.ParseBool(v)
-			volume.Persistent = value
-		case "hosttype":
+			volume.Persistent = value || v == ""
+		case "hosttype", "hostpathtype":
 			if volume.HostPath == "" && volume.Name != "" {
 				volume.HostPath = volume.Name
 				volume.Name = ""
@@ -65,19 +91,92 @@ func ParseVolume(v string) (volume v1.Volume, err error) {
 			}
 			hpt := corev1.HostPathType(hostPathType)
 			volume.HostPathType = &hpt
+		case "size", "sizelimit":
+			volume.Size = v
+		case "ro", "readonly":
+			volume.ReadOnly = v == "" || v == "true"
+		case "tmpfs":
+			volume.Tmpfs = v == "" || v == "true"
+		case "ephemeral":
+			volume.Ephemeral = v == "" || v == "true"
+		case "driver":
+			volume.Driver = v
+		case "accessmode", "accessmodes":
+			mode, ok := accessModes[strings.ToLower(v)]
+			if !ok {
+				return volume, fmt.Errorf("invalid access mode %s, must be ReadWriteOnce, ReadOnlyMany or ReadWriteMany", v)
+			}
+			volume.AccessModes = append(volume.AccessModes, mode)
+		case "storageclass":
+			volume.StorageClass = v
+		case "config", "secret", "downward", "downwardapi":
+			if err := addProjection(&volume, strings.ToLower(k), v); err != nil {
+				return volume, err
+			}
+		default:
+			if strings.HasPrefix(k, "attr.") {
+				if volume.DriverAttributes == nil {
+					volume.DriverAttributes = map[string]string{}
+				}
+				volume.DriverAttributes[strings.TrimPrefix(k, "attr.")] = v
+				continue
+			}
+			return volume, fmt.Errorf("invalid volume option %s", k)
 		}
 	}
 
 	return volume, nil
 }
 
+// parseNFS parses `nfs://server/export:/path`
+func parseNFS(volume *v1.Volume, str string) error {
+	source := strings.TrimPrefix(str, nfsPrefix)
+	i := strings.LastIndex(source, ":")
+	if i < 0 {
+		return fmt.Errorf("%s does not match format nfs://server/export:/path", str)
+	}
+	source, volume.Path = source[:i], source[i+1:]
+	volume.NFSServer, volume.NFSPath = kv.Split(source, "/")
+	volume.NFSPath = "/" + volume.NFSPath
+	if volume.NFSServer == "" || volume.Path == "" {
+		return fmt.Errorf("%s does not match format nfs://server/export:/path", str)
+	}
+	return nil
+}
+
+func addProjection(volume *v1.Volume, kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("projected %s of volume %s is missing a name", kind, volume.Path)
+	}
+	if volume.Projected == nil {
+		volume.Projected = &v1.ProjectedVolume{}
+	}
+	switch kind {
+	case "config":
+		volume.Projected.Configs = append(volume.Projected.Configs, name)
+	case "secret":
+		volume.Projected.Secrets = append(volume.Projected.Secrets, name)
+	default:
+		if _, ok := DownwardAPIFields[name]; !ok {
+			return fmt.Errorf("invalid downward API field %s, must be labels, annotations, name, namespace or uid", name)
+		}
+		volume.Projected.DownwardAPI = append(volume.Projected.DownwardAPI, name)
+	}
+	return nil
+}
+
 type VolumeStringer struct {
 	v1.Volume
 }
 
 func (v VolumeStringer) MaybeString() interface{} {
 	buf := &strings.Builder{}
-	if v.Name != "" {
+	if v.NFSServer != "" {
+		buf.WriteString(nfsPrefix)
+		buf.WriteString(v.NFSServer)
+		buf.WriteString(v.NFSPath)
+		buf.WriteString(":")
+	} else if v.Name != "" {
 		buf.WriteString(v.Name)
 		buf.WriteString(":")
 	} else if v.HostPath != "" {
@@ -99,5 +198,61 @@ func (v VolumeStringer) MaybeString() interface{} {
 		buf.WriteString(",persistent")
 	}
 
+	if v.Ephemeral {
+		buf.WriteString(",ephemeral")
+	}
+
+	if v.Tmpfs {
+		buf.WriteString(",tmpfs")
+	}
+
+	if v.Size != "" {
+		buf.WriteString(",size=")
+		buf.WriteString(v.Size)
+	}
+
+	for _, mode := range v.AccessModes {
+		buf.WriteString(",accessMode=")
+		buf.WriteString(string(mode))
+	}
+
+	if v.StorageClass != "" {
+		buf.WriteString(",storageClass=")
+		buf.WriteString(v.StorageClass)
+	}
+
+	if v.Driver != "" {
+		buf.WriteString(",driver=")
+		buf.WriteString(v.Driver)
+	}
+
+	var attrs []string
+	for k := range v.DriverAttributes {
+		attrs = append(attrs, k)
+	}
+	sort.Strings(attrs)
+	for _, k := range attrs {
+		buf.WriteString(fmt.Sprintf(",attr.%s=%s", k, v.DriverAttributes[k]))
+	}
+
+	if v.Projected != nil {
+		for _, config := range v.Projected.Configs {
+			buf.WriteString(",config=")
+			buf.WriteString(config)
+		}
+		for _, secret := range v.Projected.Secrets {
+			buf.WriteString(",secret=")
+			buf.WriteString(secret)
+		}
+		for _, field := range v.Projected.DownwardAPI {
+			buf.WriteString(",downward=")
+			buf.WriteString(field)
+		}
+	}
+
+	if v.ReadOnly {
+		buf.WriteString(",ro")
+	}
+
 	return buf.String()
 }
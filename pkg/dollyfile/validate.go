@@ -0,0 +1,145 @@
+package dollyfile
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/utils"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Validate checks the services of the Dollyfile for settings that can't be converted and references to unknown services
+func (r *DollyFile) Validate() error {
+	var names []string
+	for name := range r.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := r.Services[name]
+		if err := r.validateConnections(service); err != nil {
+			return err
+		}
+		if err := validateWorkload(service); err != nil {
+			return err
+		}
+		if err := validateVolumes(service); err != nil {
+			return err
+		}
+		if err := validateSecurity(service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *DollyFile) validateConnections(service types.Service) error {
+	for _, target := range service.Spec.Connects {
+		if _, ok := r.Services[target]; !ok {
+			return fmt.Errorf("service %s connects to unknown service %s", service.Name, target)
+		}
+	}
+	for _, source := range service.Spec.AllowFrom {
+		if _, _, err := net.ParseCIDR(source); err == nil {
+			continue
+		}
+		if _, ok := r.Services[source]; !ok {
+			return fmt.Errorf("service %s allows traffic from unknown service %s, must be a service or CIDR", service.Name, source)
+		}
+	}
+	return nil
+}
+
+func validateWorkload(service types.Service) error {
+	if service.Spec.Global && service.Spec.Kind != "" && service.Spec.Kind != types.WorkloadDaemonSet {
+		return fmt.Errorf("service %s is global, which requires kind DaemonSet instead of %s", service.Name, service.Spec.Kind)
+	}
+
+	kind := utils.WorkloadKind(service)
+	switch {
+	case service.Spec.UpdateStrategy == types.UpdateStrategyRecreate && kind != types.WorkloadDeployment:
+		return fmt.Errorf("service %s: updateStrategy Recreate is only supported by a Deployment, not a %s", service.Name, kind)
+	case service.Spec.UpdateStrategy == types.UpdateStrategyOnDelete && kind == types.WorkloadDeployment:
+		return fmt.Errorf("service %s: updateStrategy OnDelete is not supported by a Deployment", service.Name)
+	case service.Spec.Partition != nil && kind != types.WorkloadStatefulSet:
+		return fmt.Errorf("service %s: partition is only supported by a StatefulSet, not a %s", service.Name, kind)
+	case service.Spec.PodManagement != "" && kind != types.WorkloadStatefulSet:
+		return fmt.Errorf("service %s: podManagement is only supported by a StatefulSet, not a %s", service.Name, kind)
+	case service.Spec.Disruption != nil && service.Spec.Disruption.MinAvailable != nil && service.Spec.Disruption.MaxUnavailable != nil:
+		return fmt.Errorf("service %s: disruption sets both minAvailable and maxUnavailable, only one of them is allowed", service.Name)
+	}
+
+	return nil
+}
+
+func validateVolumes(service types.Service) error {
+	for _, container := range utils.ToNamedContainers(service) {
+		for _, volume := range container.Volumes {
+			if volume.Size == "" {
+				continue
+			}
+			if _, err := resource.ParseQuantity(volume.Size); err != nil {
+				return fmt.Errorf("service %s: invalid size %s of volume %s: %v", service.Name, volume.Size, volume.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSecurity(service types.Service) error {
+	for _, container := range utils.ToNamedContainers(service) {
+		if csc := container.ContainerSecurityContext; csc != nil {
+			if _, err := stringers.ParseSeccompProfile(csc.SeccompProfile); err != nil {
+				return fmt.Errorf("service %s: invalid seccomp profile of container %s: %v", service.Name, container.Name, err)
+			}
+			if _, err := stringers.ParseAppArmorProfile(csc.AppArmorProfile); err != nil {
+				return fmt.Errorf("service %s: invalid AppArmor profile of container %s: %v", service.Name, container.Name, err)
+			}
+		}
+	}
+
+	if service.Spec.Security == types.SecurityPresetRestricted {
+		return validateRestricted(service)
+	}
+	return nil
+}
+
+// validateRestricted rejects the settings the restricted Pod Security Standard doesn't allow, which the preset can't
+// change without breaking the service
+func validateRestricted(service types.Service) error {
+	if service.Spec.HostNetwork {
+		return fmt.Errorf("service %s: the restricted security preset doesn't allow the host network", service.Name)
+	}
+	for _, sysctl := range service.Spec.Sysctls {
+		if !types.RestrictedSysctls[sysctl.Name] {
+			return fmt.Errorf("service %s: the restricted security preset doesn't allow sysctl %s", service.Name, sysctl.Name)
+		}
+	}
+
+	for _, container := range utils.ToNamedContainers(service) {
+		for _, port := range container.Ports {
+			if port.HostPort {
+				return fmt.Errorf("service %s: container %s uses host port %d, which the restricted security preset doesn't allow", service.Name, container.Name, port.Port)
+			}
+		}
+		for _, volume := range container.Volumes {
+			if volume.HostPath != "" {
+				return fmt.Errorf("service %s: container %s mounts host path %s, which the restricted security preset doesn't allow", service.Name, container.Name, volume.HostPath)
+			}
+		}
+		if container.ContainerSecurityContext == nil {
+			continue
+		}
+		for _, capability := range container.CapAdd {
+			if !types.RestrictedCapabilities[capability] {
+				return fmt.Errorf("service %s: container %s adds capability %s, which the restricted security preset doesn't allow", service.Name, container.Name, capability)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package dollyfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRestricted(t *testing.T) {
+	for spec, err := range map[string]string{
+		"hostNetwork: true":                    "service web: the restricted security preset doesn't allow the host network",
+		"sysctls: [kernel.msgmax=65536]":       "service web: the restricted security preset doesn't allow sysctl kernel.msgmax",
+		"ports: 80:8080/http,hostport":         "service web: container web uses host port 80, which the restricted security preset doesn't allow",
+		"volumes: /var/run/docker.sock:/sock":  "service web: container web mounts host path /var/run/docker.sock, which the restricted security preset doesn't allow",
+		"capAdd: [NET_ADMIN]":                  "service web: container web adds capability NET_ADMIN, which the restricted security preset doesn't allow",
+		"capAdd: [NET_BIND_SERVICE]":           "",
+		"sysctls: [net.ipv4.tcp_syncookies=1]": "",
+	} {
+		_, parseErr := Parse([]byte("services:\n  web:\n    image: nginx\n    security: restricted\n    "+spec+"\n"), "default", nil)
+		if err == "" {
+			assert.NoError(t, parseErr, spec)
+		} else {
+			assert.EqualError(t, parseErr, err, spec)
+		}
+	}
+}
+
+func TestValidateSecurityProfiles(t *testing.T) {
+	_, err := Parse([]byte("services:\n  web:\n    image: nginx\n    seccomp: bogus\n"), "default", nil)
+	assert.EqualError(t, err, "service web: invalid seccomp profile of container web: bogus is not a valid seccomp profile, must be runtime/default, unconfined or localhost/<path>")
+
+	_, err = Parse([]byte("services:\n  web:\n    image: nginx\n    hostNetwork: true\n"), "default", nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,19 @@
+// Code generated by pkg/codegen. DO NOT EDIT.
+
+package dollyfile
+
+// The SwaggerDoc methods return the doc comments of the types and their fields by json name, the same as the Kubernetes
+// types do. They are the descriptions of the JSON Schema of the Dollyfile.
+
+func (DollyFile) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"services":         "Services to run, by name",
+		"configs":          "ConfigMaps to create, by name. The keys and values are the data of the ConfigMap",
+		"routes":           "Routes creating an Ingress for the hostnames and paths of services, by name",
+		"kubernetes":       "Kubernetes objects applied together with the services",
+		"manifest":         "Kubernetes manifest applied together with the services, as yaml",
+		"defaultResources": "Resource requests of containers that don't specify any",
+		"isolation":        "Network isolation of the services. strict denies all traffic that is not declared with connects or allowFrom",
+		"ingress":          "Ingress controller exposing the hostnames of the services, detected by dolly up if not set",
+	}
+}
@@ -0,0 +1,358 @@
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/convert/deployment"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// containers converts the first container of the pod to the container of the service and the others to sidecars
+func containers(service *types.Service, w workload) {
+	spec := w.template.Spec
+	bestEffort := isBestEffort(spec)
+
+	for i, c := range spec.Containers {
+		container := toContainer(c, w)
+		if i == 0 {
+			if c.Name != service.Name {
+				warnf(w, "container %s is renamed to %s", c.Name, service.Name)
+			}
+			service.Spec.Container = container
+			continue
+		}
+		service.Spec.Sidecars = append(service.Spec.Sidecars, types.NamedContainer{
+			Name:      c.Name,
+			Container: container,
+		})
+	}
+
+	for _, c := range spec.InitContainers {
+		service.Spec.Sidecars = append(service.Spec.Sidecars, types.NamedContainer{
+			Name:      c.Name,
+			Init:      true,
+			Container: toContainer(c, w),
+		})
+	}
+
+	if bestEffort {
+		service.Spec.QOS = v1.PodQOSBestEffort
+	}
+}
+
+func toContainer(c v1.Container, w workload) types.Container {
+	container := types.Container{
+		Image:      c.Image,
+		Command:    c.Command,
+		Args:       c.Args,
+		WorkingDir: c.WorkingDir,
+		Stdin:      c.Stdin,
+		StdinOnce:  c.StdinOnce,
+		TTY:        c.TTY,
+		Env:        env(c, w),
+	}
+
+	if !isDefaultPullPolicy(c) {
+		container.ImagePullPolicy = c.ImagePullPolicy
+	}
+
+	if len(c.EnvFrom) > 0 {
+		warnf(w, "envFrom of container %s is not supported and is dropped", c.Name)
+	}
+
+	for _, port := range c.Ports {
+		if port.HostPort > 0 {
+			container.Ports = append(container.Ports, types.ContainerPort{
+				Name:       port.Name,
+				Protocol:   types.Protocol(port.Protocol),
+				Port:       port.HostPort,
+				TargetPort: port.ContainerPort,
+				HostPort:   true,
+			})
+		}
+	}
+
+	resources(&container, c.Resources)
+	probes(&container, c)
+	lifecycle(&container, c, w)
+	container.ContainerSecurityContext = securityContext(c, w.template.Annotations, isRestricted(w.template.Spec.SecurityContext))
+	mounts(&container, c, w)
+
+	return container
+}
+
+// isDefaultPullPolicy returns whether the pull policy is the one the converters or Kubernetes default to, Kubernetes
+// defaults versioned images to IfNotPresent
+func isDefaultPullPolicy(c v1.Container) bool {
+	def := deployment.DefaultPullPolicy(c.Image)
+	return c.ImagePullPolicy == def || (def == "" && c.ImagePullPolicy == v1.PullIfNotPresent)
+}
+
+// isBestEffort returns whether no container of the pod has cpu or memory resources, which the converters only keep with
+// the BestEffort class
+func isBestEffort(spec v1.PodSpec) bool {
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			_, request := c.Resources.Requests[name]
+			_, limit := c.Resources.Limits[name]
+			if request || limit {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func resources(container *types.Container, r v1.ResourceRequirements) {
+	_, cpuLimit := r.Limits[v1.ResourceCPU]
+	_, memoryLimit := r.Limits[v1.ResourceMemory]
+
+	for name, q := range r.Requests {
+		switch name {
+		case v1.ResourceCPU:
+			// requests equal to the defaults are set again by the converters
+			if !cpuLimit && q.Cmp(deployment.DefaultCPU) == 0 {
+				continue
+			}
+			container.CPUMillis = milli(q)
+		case v1.ResourceMemory:
+			if !memoryLimit && q.Cmp(deployment.DefaultMemory) == 0 {
+				continue
+			}
+			container.MemoryBytes = value(q)
+		case v1.ResourceEphemeralStorage:
+			container.EphemeralStorageBytes = value(q)
+		default:
+			if container.ExtendedResources == nil {
+				container.ExtendedResources = v1.ResourceList{}
+			}
+			container.ExtendedResources[name] = q
+		}
+	}
+
+	for name, q := range r.Limits {
+		switch name {
+		case v1.ResourceCPU:
+			container.CPULimitMillis = milli(q)
+		case v1.ResourceMemory:
+			container.MemoryLimitBytes = value(q)
+		case v1.ResourceEphemeralStorage:
+			container.EphemeralStorageLimitBytes = value(q)
+		default:
+			if container.ExtendedResources == nil {
+				container.ExtendedResources = v1.ResourceList{}
+			}
+			container.ExtendedResources[name] = q
+		}
+	}
+}
+
+func milli(q resource.Quantity) *int64 {
+	v := q.MilliValue()
+	return &v
+}
+
+func value(q resource.Quantity) *int64 {
+	v := q.Value()
+	return &v
+}
+
+// env converts the environment variables back to plain values, $(self/...) references or ConfigMap and Secret keys
+func env(c v1.Container, w workload) (result []types.EnvVar) {
+	for _, e := range c.Env {
+		env := types.EnvVar{
+			Name:  e.Name,
+			Value: e.Value,
+		}
+
+		if from := e.ValueFrom; from != nil {
+			switch {
+			case from.ConfigMapKeyRef != nil:
+				env.ConfigMapName = from.ConfigMapKeyRef.Name
+				env.Key = from.ConfigMapKeyRef.Key
+			case from.SecretKeyRef != nil:
+				env.SecretName = from.SecretKeyRef.Name
+				env.Key = from.SecretKeyRef.Key
+			case from.FieldRef != nil:
+				ref, ok := reference(deployment.FieldRefs, from.FieldRef.FieldPath)
+				if !ok {
+					warnf(w, "environment variable %s of container %s references unsupported field %s and is dropped", e.Name, c.Name, from.FieldRef.FieldPath)
+					continue
+				}
+				env.Value = ref
+			case from.ResourceFieldRef != nil:
+				ref, ok := reference(deployment.ResourceRefs, from.ResourceFieldRef.Resource)
+				if !ok || (from.ResourceFieldRef.ContainerName != "" && from.ResourceFieldRef.ContainerName != c.Name) {
+					warnf(w, "environment variable %s of container %s references unsupported resource %s and is dropped", e.Name, c.Name, from.ResourceFieldRef.Resource)
+					continue
+				}
+				env.Value = ref
+			}
+		}
+
+		result = append(result, env)
+	}
+	return
+}
+
+// reference returns the first $(self/...) or $(limits/...) reference that converts to the field path
+func reference(refs map[string]string, path string) (string, bool) {
+	var keys []string
+	for k, v := range refs {
+		if v == path {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("$(%s)", keys[0]), true
+}
+
+// probes sets the healthcheck if the liveness and readiness probes are the same
+func probes(container *types.Container, c v1.Container) {
+	liveness := trimProbe(c.LivenessProbe)
+	readiness := trimProbe(c.ReadinessProbe)
+
+	if liveness != nil && reflect.DeepEqual(liveness, readiness) {
+		container.Healthcheck = liveness
+	} else {
+		container.LivenessProbe = liveness
+		container.ReadinessProbe = readiness
+	}
+	container.StartupProbe = trimProbe(c.StartupProbe)
+}
+
+// trimProbe removes the values Kubernetes defaults, so that the probe can be written in the short format
+func trimProbe(probe *v1.Probe) *v1.Probe {
+	if probe == nil {
+		return nil
+	}
+	probe = probe.DeepCopy()
+	if probe.TimeoutSeconds == 1 {
+		probe.TimeoutSeconds = 0
+	}
+	if probe.PeriodSeconds == 10 {
+		probe.PeriodSeconds = 0
+	}
+	if probe.SuccessThreshold == 1 {
+		probe.SuccessThreshold = 0
+	}
+	if probe.FailureThreshold == 3 {
+		probe.FailureThreshold = 0
+	}
+	if probe.HTTPGet != nil && probe.HTTPGet.Scheme == v1.URISchemeHTTP {
+		probe.HTTPGet.Scheme = ""
+	}
+	return probe
+}
+
+// lifecycle converts the hooks of the container, a preStop sleep is the drain of the container
+func lifecycle(container *types.Container, c v1.Container, w workload) {
+	if c.Lifecycle == nil {
+		return
+	}
+
+	container.PostStart = c.Lifecycle.PostStart
+	container.PreStop = c.Lifecycle.PreStop
+
+	if drain, ok := sleepSeconds(c.Lifecycle.PreStop); ok {
+		container.PreStop = nil
+		container.Drain = &metav1.Duration{Duration: time.Duration(drain) * time.Second}
+	}
+
+	for _, handler := range []*v1.Handler{container.PostStart, container.PreStop} {
+		if handler != nil && handler.TCPSocket != nil {
+			warnf(w, "tcp hook of container %s is not supported", c.Name)
+		}
+	}
+}
+
+func sleepSeconds(handler *v1.Handler) (int64, bool) {
+	if handler == nil || handler.Exec == nil || len(handler.Exec.Command) != 2 || handler.Exec.Command[0] != "sleep" {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(handler.Exec.Command[1], 10, 64)
+	return seconds, err == nil && seconds > 0
+}
+
+// longestDrain returns the longest drain of the containers of the service, which extends the default termination grace period
+func longestDrain(service *types.Service) (drain time.Duration) {
+	containers := append([]types.Container{service.Spec.Container}, sidecars(*service)...)
+	for _, c := range containers {
+		if c.Drain != nil && c.Drain.Duration > drain {
+			drain = c.Drain.Duration
+		}
+	}
+	return
+}
+
+func securityContext(c v1.Container, annotations map[string]string, restricted bool) *types.ContainerSecurityContext {
+	csc := &types.ContainerSecurityContext{
+		AppArmorProfile: annotations[appArmorAnnotationPrefix+c.Name],
+	}
+
+	if sc := c.SecurityContext; sc != nil {
+		csc.RunAsUser = sc.RunAsUser
+		csc.RunAsGroup = sc.RunAsGroup
+		csc.RunAsNonRoot = sc.RunAsNonRoot
+		csc.ReadOnlyRootFilesystem = sc.ReadOnlyRootFilesystem
+		csc.Privileged = sc.Privileged
+		csc.AllowPrivilegeEscalation = sc.AllowPrivilegeEscalation
+		csc.SeccompProfile = stringers.SeccompProfileString(sc.SeccompProfile)
+		if sc.Capabilities != nil {
+			csc.CapAdd = sc.Capabilities.Add
+			csc.CapDrop = sc.Capabilities.Drop
+		}
+	}
+
+	if restricted {
+		unrestrict(csc)
+	}
+
+	if reflect.DeepEqual(csc, &types.ContainerSecurityContext{}) {
+		return nil
+	}
+	return csc
+}
+
+// unrestrict removes the settings the restricted security preset sets again
+func unrestrict(csc *types.ContainerSecurityContext) {
+	if csc.Privileged != nil && !*csc.Privileged {
+		csc.Privileged = nil
+	}
+	if csc.AllowPrivilegeEscalation != nil && !*csc.AllowPrivilegeEscalation {
+		csc.AllowPrivilegeEscalation = nil
+	}
+	if csc.RunAsNonRoot != nil && *csc.RunAsNonRoot {
+		csc.RunAsNonRoot = nil
+	}
+	if len(csc.CapDrop) == 1 && csc.CapDrop[0] == "ALL" {
+		csc.CapDrop = nil
+	}
+	if len(csc.CapAdd) == 0 {
+		csc.CapAdd = nil
+	}
+}
+
+// appArmorAnnotations returns the AppArmor annotations of the pod, which are converted to the profile of the containers
+func appArmorAnnotations(annotations map[string]string) (result []string) {
+	for k := range annotations {
+		if strings.HasPrefix(k, appArmorAnnotationPrefix) {
+			result = append(result, k)
+		}
+	}
+	return
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// ignoredPrefixes are the prefixes of labels and annotations set by Kubernetes, kubectl or the apply of dolly itself
+	ignoredPrefixes = []string{
+		"objectset.rio.cattle.io/",
+		"deployment.kubernetes.io/",
+		"kubectl.kubernetes.io/",
+		"meta.helm.sh/",
+	}
+	// ignoredConfigs are ConfigMaps created by Kubernetes in every namespace
+	ignoredConfigs = map[string]bool{
+		"kube-root-ca.crt": true,
+	}
+)
+
+// Objects are the live objects of a namespace that are exported into a Dollyfile
+type Objects struct {
+	Deployments            []appsv1.Deployment
+	StatefulSets           []appsv1.StatefulSet
+	DaemonSets             []appsv1.DaemonSet
+	Services               []v1.Service
+	Endpoints              []v1.Endpoints
+	Ingresses              []networkingv1.Ingress
+	ConfigMaps             []v1.ConfigMap
+	PersistentVolumeClaims []v1.PersistentVolumeClaim
+	Roles                  []rbacv1.Role
+	RoleBindings           []rbacv1.RoleBinding
+	ClusterRoles           []rbacv1.ClusterRole
+	ClusterRoleBindings    []rbacv1.ClusterRoleBinding
+	DisruptionBudgets      []policyv1beta1.PodDisruptionBudget
+}
+
+// Export converts live workloads and the objects that belong to them back into a Dollyfile. Workloads are selected as
+// kind/name, such as deployment/web, and every workload is exported if none is selected, together with the external
+// services and ConfigMaps of the namespace. Values the converters would set anyway are left out.
+func Export(objs Objects, selectors ...string) (*dollyfile.DollyFile, error) {
+	workloads, err := selectWorkloads(objs, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &dollyfile.DollyFile{
+		Services: map[string]types.Service{},
+		Configs:  map[string]v1.ConfigMap{},
+	}
+
+	for _, w := range workloads {
+		rf.Services[w.meta.Name] = exportWorkload(objs, w)
+	}
+
+	if len(selectors) == 0 {
+		for _, service := range externalServices(objs) {
+			rf.Services[service.Name] = service
+		}
+	}
+
+	referenced := referencedConfigs(rf)
+	for _, cm := range objs.ConfigMaps {
+		if ignoredConfigs[cm.Name] || len(cm.OwnerReferences) > 0 {
+			continue
+		}
+		if len(selectors) > 0 && !referenced[cm.Name] {
+			continue
+		}
+		rf.Configs[cm.Name] = v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: cm.Name,
+			},
+			Data: cm.Data,
+		}
+	}
+
+	return rf, nil
+}
+
+// selectWorkloads returns the workloads matching the kind/name selectors, or all workloads if there are none
+func selectWorkloads(objs Objects, selectors []string) ([]workload, error) {
+	all := workloads(objs)
+	if len(selectors) == 0 {
+		return all, nil
+	}
+
+	var result []workload
+	for _, selector := range selectors {
+		kind, name, err := parseSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, w := range all {
+			if w.meta.Name == name && (kind == "" || w.kind == kind) {
+				result = append(result, w)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("workload %s not found", selector)
+		}
+	}
+	return result, nil
+}
+
+func parseSelector(selector string) (types.WorkloadKind, string, error) {
+	i := strings.Index(selector, "/")
+	if i < 0 {
+		return "", selector, nil
+	}
+
+	name := selector[i+1:]
+	switch strings.ToLower(selector[:i]) {
+	case "deployment", "deployments", "deploy":
+		return types.WorkloadDeployment, name, nil
+	case "statefulset", "statefulsets", "sts":
+		return types.WorkloadStatefulSet, name, nil
+	case "daemonset", "daemonsets", "ds":
+		return types.WorkloadDaemonSet, name, nil
+	}
+	return "", "", fmt.Errorf("invalid workload %s, must be deployment/<name>, statefulset/<name> or daemonset/<name>", selector)
+}
+
+// referencedConfigs returns the names of the ConfigMaps the services mount or read environment variables from
+func referencedConfigs(rf *dollyfile.DollyFile) map[string]bool {
+	result := map[string]bool{}
+	for _, service := range rf.Services {
+		containers := append([]types.Container{service.Spec.Container}, sidecars(service)...)
+		for _, c := range containers {
+			for _, mount := range c.Configs {
+				result[mount.Name] = true
+			}
+			for _, env := range c.Env {
+				if env.ConfigMapName != "" {
+					result[env.ConfigMapName] = true
+				}
+			}
+			for _, volume := range c.Volumes {
+				if volume.Projected != nil {
+					for _, name := range volume.Projected.Configs {
+						result[name] = true
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+func sidecars(service types.Service) (result []types.Container) {
+	for _, sidecar := range service.Spec.Sidecars {
+		result = append(result, sidecar.Container)
+	}
+	return
+}
+
+// cleanMap removes the labels or annotations that are managed by Kubernetes and the tools applying the objects
+func cleanMap(m map[string]string, drop ...string) map[string]string {
+	result := map[string]string{}
+	for k, v := range m {
+		if ignored(k) {
+			continue
+		}
+		result[k] = v
+	}
+	for _, k := range drop {
+		delete(result, k)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func ignored(key string) bool {
+	for _, prefix := range ignoredPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubset returns whether every key value pair of selector is in labels
+func isSubset(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]string) (result []string) {
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return
+}
+
+func warnf(w workload, format string, args ...interface{}) {
+	logrus.Warnf("%s %s: %s", strings.ToLower(string(w.kind)), w.meta.Name, fmt.Sprintf(format, args...))
+}
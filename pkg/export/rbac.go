@@ -0,0 +1,99 @@
+package export
+
+import (
+	"github.com/rancher/dolly/pkg/types"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// permissions converts the Roles and ClusterRoles bound to the service account of the pods. The converters create the
+// service account, Role and ClusterRole named after the service, other roles are referenced by name.
+func permissions(objs Objects, service *types.Service, w workload) {
+	account := w.template.Spec.ServiceAccountName
+	if account == "" || account == "default" {
+		return
+	}
+
+	for _, binding := range objs.RoleBindings {
+		if !hasSubject(binding.Subjects, account, w.meta.Namespace) {
+			continue
+		}
+		if binding.RoleRef.Kind != "Role" {
+			warnf(w, "binding %s to %s %s is not supported and is dropped", binding.Name, binding.RoleRef.Kind, binding.RoleRef.Name)
+			continue
+		}
+		if binding.RoleRef.Name != service.Name {
+			service.Spec.Permissions = append(service.Spec.Permissions, types.Permission{Role: binding.RoleRef.Name})
+			continue
+		}
+		for _, role := range objs.Roles {
+			if role.Name == binding.RoleRef.Name {
+				service.Spec.Permissions = append(service.Spec.Permissions, rulesToPermissions(role.Rules)...)
+			}
+		}
+	}
+
+	for _, binding := range objs.ClusterRoleBindings {
+		if !hasSubject(binding.Subjects, account, w.meta.Namespace) {
+			continue
+		}
+		if binding.RoleRef.Name != service.Name {
+			service.Spec.GlobalPermissions = append(service.Spec.GlobalPermissions, types.Permission{Role: binding.RoleRef.Name})
+			continue
+		}
+		for _, role := range objs.ClusterRoles {
+			if role.Name == binding.RoleRef.Name {
+				service.Spec.GlobalPermissions = append(service.Spec.GlobalPermissions, rulesToPermissions(role.Rules)...)
+			}
+		}
+	}
+
+	if account != service.Name {
+		warnf(w, "service account %s is replaced by the service account %s", account, service.Name)
+	}
+	if len(service.Spec.Permissions) == 0 && len(service.Spec.GlobalPermissions) == 0 {
+		warnf(w, "service account %s has no permissions and is dropped", account)
+	}
+}
+
+func hasSubject(subjects []rbacv1.Subject, account, namespace string) bool {
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.ServiceAccountKind && subject.Name == account && subject.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesToPermissions is the reverse of rbac.PermToPolicyRule, splitting the rules into one permission per resource
+func rulesToPermissions(rules []rbacv1.PolicyRule) (result []types.Permission) {
+	for _, rule := range rules {
+		for _, url := range rule.NonResourceURLs {
+			result = append(result, types.Permission{
+				Verbs: rule.Verbs,
+				URL:   url,
+			})
+		}
+
+		groups := rule.APIGroups
+		if len(groups) == 0 {
+			groups = []string{""}
+		}
+		names := rule.ResourceNames
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, group := range groups {
+			for _, resource := range rule.Resources {
+				for _, name := range names {
+					result = append(result, types.Permission{
+						Verbs:        rule.Verbs,
+						APIGroup:     group,
+						Resource:     resource,
+						ResourceName: name,
+					})
+				}
+			}
+		}
+	}
+	return
+}
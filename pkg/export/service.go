@@ -0,0 +1,259 @@
+package export
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/wrangler/pkg/name"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	// generatedPortName matches the port names the converters generate from the protocol and port
+	generatedPortName   = regexp.MustCompile(`^(http|http2|grpc|tcp|udp|sctp)-([0-9]+)$`)
+	serviceTypeSuffixes = map[string]types.ServiceType{
+		"clusterip": types.ServiceTypeClusterIP,
+		"nodeport":  types.ServiceTypeNodePort,
+		"lb":        types.ServiceTypeLoadBalancer,
+		"headless":  types.ServiceTypeHeadless,
+	}
+)
+
+// services converts the Services selecting the pods of the workload to the ports and Service settings of the service.
+// The Service named after the workload sets the service type, the others override the type of their ports.
+func services(objs Objects, service *types.Service, w workload) {
+	var extra []v1.Service
+	for _, svc := range objs.Services {
+		if svc.Spec.Type == v1.ServiceTypeExternalName || !isSubset(svc.Spec.Selector, w.template.Labels) {
+			continue
+		}
+		if svc.Name == service.Name {
+			mainService(service, svc, w)
+		} else {
+			extra = append(extra, svc)
+		}
+	}
+
+	for _, svc := range extra {
+		serviceType := typeOf(svc)
+		suffix := strings.TrimPrefix(svc.Name, service.Name+"-")
+		switch {
+		case serviceTypeSuffixes[suffix] == types.ServiceTypeHeadless && w.kind == types.WorkloadStatefulSet:
+			// the governing Service of a StatefulSet is created again by the converters
+			continue
+		case serviceTypeSuffixes[suffix] != serviceType:
+			warnf(w, "ports of service %s are exported as %s ports of the service, the Service is renamed to %s", svc.Name, serviceType,
+				name.SafeConcatName(service.Name, suffixOf(serviceType)))
+		}
+
+		for _, port := range svc.Spec.Ports {
+			p := toPort(port, w)
+			if existing := findPort(service, p); existing != nil {
+				if existing.ServiceType == "" && serviceType != mainType(service) {
+					existing.ServiceType = serviceType
+				}
+				continue
+			}
+			if serviceType != mainType(service) {
+				p.ServiceType = serviceType
+			}
+			service.Spec.Ports = append(service.Spec.Ports, p)
+		}
+	}
+}
+
+func mainService(service *types.Service, svc v1.Service, w workload) {
+	spec := &service.Spec
+	if t := typeOf(svc); t != types.ServiceTypeClusterIP {
+		spec.ServiceType = t
+	}
+	spec.ServiceAnnotations = cleanMap(svc.Annotations)
+	spec.SessionAffinity = svc.Spec.SessionAffinity
+	if spec.SessionAffinity == v1.ServiceAffinityNone {
+		spec.SessionAffinity = ""
+	}
+	if config := svc.Spec.SessionAffinityConfig; config != nil && config.ClientIP != nil && config.ClientIP.TimeoutSeconds != nil &&
+		*config.ClientIP.TimeoutSeconds != v1.DefaultClientIPServiceAffinitySeconds {
+		spec.SessionAffinityTimeoutSeconds = config.ClientIP.TimeoutSeconds
+	}
+	spec.LoadBalancerIP = svc.Spec.LoadBalancerIP
+	spec.LoadBalancerSourceRanges = svc.Spec.LoadBalancerSourceRanges
+	if svc.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypeLocal {
+		spec.ExternalTrafficPolicy = svc.Spec.ExternalTrafficPolicy
+	}
+
+	for _, port := range svc.Spec.Ports {
+		spec.Ports = append(spec.Ports, toPort(port, w))
+	}
+}
+
+// toPort converts a Service port, the protocol is taken from the generated port name or guessed from its prefix.
+// Node ports are left out, they are assigned again by Kubernetes.
+func toPort(port v1.ServicePort, w workload) types.ContainerPort {
+	result := types.ContainerPort{
+		Name:       port.Name,
+		Port:       port.Port,
+		TargetPort: targetPort(port.TargetPort, w),
+		Protocol:   types.Protocol(port.Protocol),
+	}
+
+	if match := generatedPortName.FindStringSubmatch(port.Name); match != nil && match[2] == strconv.Itoa(int(port.Port)) {
+		result.Name = ""
+		result.Protocol = types.Protocol(strings.ToUpper(match[1]))
+	} else if port.Protocol == v1.ProtocolTCP {
+		switch name := strings.ToLower(port.Name); {
+		case strings.HasPrefix(name, "grpc"):
+			result.Protocol = types.ProtocolGRPC
+		case strings.HasPrefix(name, "http2") || strings.HasPrefix(name, "h2c"):
+			result.Protocol = types.ProtocolHTTP2
+		case strings.HasPrefix(name, "http") || strings.HasPrefix(name, "web"):
+			result.Protocol = types.ProtocolHTTP
+		}
+	}
+
+	if result.Protocol == types.ProtocolHTTP {
+		result.Protocol = ""
+	}
+	return result
+}
+
+// targetPort resolves a named target port to the container port of the pods
+func targetPort(port intstr.IntOrString, w workload) int32 {
+	if port.Type == intstr.Int {
+		return port.IntVal
+	}
+	for _, c := range w.template.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return p.ContainerPort
+			}
+		}
+	}
+	warnf(w, "target port %s is not a port of the containers", port.StrVal)
+	return 0
+}
+
+func findPort(service *types.Service, port types.ContainerPort) *types.ContainerPort {
+	for i, p := range service.Spec.Ports {
+		if p.Port == port.Port && p.Protocol == port.Protocol {
+			return &service.Spec.Ports[i]
+		}
+	}
+	return nil
+}
+
+func typeOf(svc v1.Service) types.ServiceType {
+	switch {
+	case svc.Spec.ClusterIP == v1.ClusterIPNone:
+		return types.ServiceTypeHeadless
+	case svc.Spec.Type == v1.ServiceTypeNodePort:
+		return types.ServiceTypeNodePort
+	case svc.Spec.Type == v1.ServiceTypeLoadBalancer:
+		return types.ServiceTypeLoadBalancer
+	}
+	return types.ServiceTypeClusterIP
+}
+
+func mainType(service *types.Service) types.ServiceType {
+	if service.Spec.ServiceType == "" {
+		return types.ServiceTypeClusterIP
+	}
+	return service.Spec.ServiceType
+}
+
+func suffixOf(serviceType types.ServiceType) string {
+	for suffix, t := range serviceTypeSuffixes {
+		if t == serviceType {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// externalServices converts the Services without selector pointing to a DNS name or a single address to external services
+func externalServices(objs Objects) (result []types.Service) {
+	for _, svc := range objs.Services {
+		if len(svc.Spec.Selector) > 0 || svc.Name == "kubernetes" {
+			continue
+		}
+
+		service := types.Service{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			Labels:    cleanMap(svc.Labels),
+		}
+		service.Spec.ServiceAnnotations = cleanMap(svc.Annotations)
+
+		if svc.Spec.Type == v1.ServiceTypeExternalName {
+			service.Spec.External = svc.Spec.ExternalName
+		} else if ip, ok := endpointIP(objs, svc.Name); ok {
+			service.Spec.External = ip
+		} else {
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			service.Spec.Ports = append(service.Spec.Ports, toPort(port, workload{}))
+		}
+		result = append(result, service)
+	}
+	return
+}
+
+// endpointIP returns the address of the Endpoints of a Service, if it has exactly one
+func endpointIP(objs Objects, name string) (string, bool) {
+	for _, endpoints := range objs.Endpoints {
+		if endpoints.Name != name {
+			continue
+		}
+		if len(endpoints.Subsets) != 1 || len(endpoints.Subsets[0].Addresses) != 1 {
+			return "", false
+		}
+		return endpoints.Subsets[0].Addresses[0].IP, true
+	}
+	return "", false
+}
+
+// ingresses converts the hosts and TLS secret of the Ingresses routing to the Service of the service
+func ingresses(objs Objects, service *types.Service) {
+	for _, ingress := range objs.Ingresses {
+		routed := false
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil || rule.Host == "" {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil && routesTo(path.Backend.Service.Name, service) {
+					addHostname(service, rule.Host)
+					routed = true
+				}
+			}
+		}
+
+		if !routed {
+			continue
+		}
+		for _, tls := range ingress.Spec.TLS {
+			if tls.SecretName != "" {
+				service.Spec.TLS = tls.SecretName
+			}
+		}
+	}
+}
+
+// routesTo returns whether the backend is the service, the converters name the backend after the app of the service
+func routesTo(backend string, service *types.Service) bool {
+	return backend == service.Name || (service.Spec.App != "" && backend == service.Spec.App)
+}
+
+func addHostname(service *types.Service, hostname string) {
+	for _, h := range service.Spec.Hostnames {
+		if h == hostname {
+			return
+		}
+	}
+	service.Spec.Hostnames = append(service.Spec.Hostnames, hostname)
+}
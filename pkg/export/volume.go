@@ -0,0 +1,174 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const volumePrefix = "vol-"
+
+// mounts converts the volume mounts of the container to config and secret mounts or volumes of the container
+func mounts(container *types.Container, c v1.Container, w workload) {
+	volumes := map[string]v1.Volume{}
+	for _, volume := range w.template.Spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	claims := map[string]bool{}
+	for _, claim := range w.claims {
+		claims[claim.Name] = true
+	}
+
+	for _, mount := range c.VolumeMounts {
+		if claims[mount.Name] {
+			container.Volumes = append(container.Volumes, types.Volume{
+				Name:       trimVolumePrefix(mount.Name),
+				Path:       mount.MountPath,
+				Persistent: true,
+				ReadOnly:   mount.ReadOnly,
+			})
+			continue
+		}
+
+		volume, ok := volumes[mount.Name]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case volume.ConfigMap != nil:
+			if len(volume.ConfigMap.Items) > 0 {
+				warnf(w, "items of config volume %s are not supported, all keys are mounted", volume.Name)
+			}
+			container.Configs = append(container.Configs, dataMount(volume.ConfigMap.Name, mount, stringers.ConfigsDefaultPath))
+			continue
+		case volume.Secret != nil:
+			if len(volume.Secret.Items) > 0 {
+				warnf(w, "items of secret volume %s are not supported, all keys are mounted", volume.Name)
+			}
+			container.Secrets = append(container.Secrets, dataMount(volume.Secret.SecretName, mount, stringers.SecretsDefaultPath))
+			continue
+		}
+
+		if mount.SubPath != "" {
+			warnf(w, "sub path %s of volume %s is not supported and is dropped", mount.SubPath, volume.Name)
+		}
+
+		v, ok := toVolume(volume, w)
+		if !ok {
+			warnf(w, "volume %s of container %s is not supported and is dropped", volume.Name, c.Name)
+			continue
+		}
+		v.Path = mount.MountPath
+		v.ReadOnly = mount.ReadOnly
+		container.Volumes = append(container.Volumes, v)
+	}
+}
+
+func dataMount(name string, mount v1.VolumeMount, defaultPath string) types.DataMount {
+	dm := types.DataMount{
+		Name:   name,
+		Key:    mount.SubPath,
+		Target: mount.MountPath,
+	}
+	if dm.Target == defaultPath {
+		dm.Target = ""
+	}
+	return dm
+}
+
+func toVolume(volume v1.Volume, w workload) (result types.Volume, ok bool) {
+	result.Name = trimVolumePrefix(volume.Name)
+
+	switch {
+	case volume.EmptyDir != nil:
+		result.Tmpfs = volume.EmptyDir.Medium == v1.StorageMediumMemory
+		if volume.EmptyDir.SizeLimit != nil {
+			result.Size = volume.EmptyDir.SizeLimit.String()
+		}
+	case volume.HostPath != nil:
+		result.Name = ""
+		result.HostPath = volume.HostPath.Path
+		if t := volume.HostPath.Type; t != nil && *t != v1.HostPathUnset {
+			result.HostPathType = t
+		}
+	case volume.PersistentVolumeClaim != nil:
+		result.Name = volume.PersistentVolumeClaim.ClaimName
+		result.Persistent = true
+		for _, pvc := range w.pvcs {
+			if pvc.Name == result.Name {
+				claim(&result, pvc.Spec)
+			}
+		}
+	case volume.NFS != nil:
+		result.Name = ""
+		result.NFSServer = volume.NFS.Server
+		result.NFSPath = volume.NFS.Path
+	case volume.Projected != nil:
+		result.Projected = projected(volume.Projected, w)
+	case volume.Ephemeral != nil && volume.Ephemeral.VolumeClaimTemplate != nil:
+		spec := volume.Ephemeral.VolumeClaimTemplate.Spec
+		result.Ephemeral = true
+		claim(&result, spec)
+	case volume.CSI != nil:
+		result.Driver = volume.CSI.Driver
+		result.DriverAttributes = volume.CSI.VolumeAttributes
+	default:
+		return result, false
+	}
+
+	return result, true
+}
+
+// claim sets the size, access modes and storage class of the claim that are not the defaults of the converters
+func claim(volume *types.Volume, spec v1.PersistentVolumeClaimSpec) {
+	if storage, ok := spec.Resources.Requests[v1.ResourceStorage]; ok && storage.Cmp(resource.MustParse(utils.DefaultVolumeSize)) != 0 {
+		volume.Size = storage.String()
+	}
+	if len(spec.AccessModes) != 1 || spec.AccessModes[0] != v1.ReadWriteOnce {
+		volume.AccessModes = spec.AccessModes
+	}
+	if spec.StorageClassName != nil {
+		volume.StorageClass = *spec.StorageClassName
+	}
+}
+
+func projected(source *v1.ProjectedVolumeSource, w workload) *types.ProjectedVolume {
+	result := &types.ProjectedVolume{}
+	for _, s := range source.Sources {
+		switch {
+		case s.ConfigMap != nil:
+			result.Configs = append(result.Configs, s.ConfigMap.Name)
+		case s.Secret != nil:
+			result.Secrets = append(result.Secrets, s.Secret.Name)
+		case s.DownwardAPI != nil:
+			for _, item := range s.DownwardAPI.Items {
+				if field, ok := downwardField(item); ok {
+					result.DownwardAPI = append(result.DownwardAPI, field)
+				} else {
+					warnf(w, "downward API file %s is not supported and is dropped", item.Path)
+				}
+			}
+		default:
+			warnf(w, "projected volume source is not supported and is dropped")
+		}
+	}
+	return result
+}
+
+func downwardField(item v1.DownwardAPIVolumeFile) (string, bool) {
+	if item.FieldRef == nil {
+		return "", false
+	}
+	path, ok := stringers.DownwardAPIFields[item.Path]
+	return item.Path, ok && path == item.FieldRef.FieldPath
+}
+
+// trimVolumePrefix returns the name of the volume in the Dollyfile, the converters prefix the pod volumes with vol-
+func trimVolumePrefix(name string) string {
+	return strings.TrimPrefix(name, volumePrefix)
+}
@@ -0,0 +1,349 @@
+package export
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/rancher/dolly/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const defaultTerminationGracePeriod = 30 * time.Second
+
+var (
+	defaultMaxSurge       = intstr.FromString("25%")
+	defaultMaxUnavailable = intstr.FromString("25%")
+	topologyShorthands    = map[string]string{
+		v1.LabelZoneFailureDomainStable: "zone",
+		v1.LabelZoneRegionStable:        "region",
+		v1.LabelHostname:                "host",
+	}
+)
+
+// workload is the part of a Deployment, StatefulSet or DaemonSet that is exported
+type workload struct {
+	kind     types.WorkloadKind
+	meta     metav1.ObjectMeta
+	selector *metav1.LabelSelector
+	template v1.PodTemplateSpec
+	claims   []v1.PersistentVolumeClaim
+	pvcs     []v1.PersistentVolumeClaim
+	spec     types.ServiceSpec
+}
+
+func workloads(objs Objects) (result []workload) {
+	for _, dep := range objs.Deployments {
+		result = append(result, fromDeployment(dep))
+	}
+	for _, ss := range objs.StatefulSets {
+		result = append(result, fromStatefulSet(ss))
+	}
+	for _, ds := range objs.DaemonSets {
+		result = append(result, fromDaemonSet(ds))
+	}
+	for i := range result {
+		result[i].pvcs = objs.PersistentVolumeClaims
+	}
+	return
+}
+
+func fromDeployment(dep appsv1.Deployment) workload {
+	w := workload{
+		kind:     types.WorkloadDeployment,
+		meta:     dep.ObjectMeta,
+		selector: dep.Spec.Selector,
+		template: dep.Spec.Template,
+	}
+	w.spec.Replicas = replicas(dep.Spec.Replicas)
+
+	if dep.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		w.spec.UpdateStrategy = types.UpdateStrategyRecreate
+	} else if rolling := dep.Spec.Strategy.RollingUpdate; rolling != nil {
+		w.spec.MaxSurge = nonDefault(rolling.MaxSurge, defaultMaxSurge)
+		w.spec.MaxUnavailable = nonDefault(rolling.MaxUnavailable, defaultMaxUnavailable)
+	}
+	return w
+}
+
+func fromStatefulSet(ss appsv1.StatefulSet) workload {
+	w := workload{
+		kind:     types.WorkloadStatefulSet,
+		meta:     ss.ObjectMeta,
+		selector: ss.Spec.Selector,
+		template: ss.Spec.Template,
+		claims:   ss.Spec.VolumeClaimTemplates,
+	}
+	w.spec.Replicas = replicas(ss.Spec.Replicas)
+
+	// services with volume templates default to a StatefulSet
+	if len(ss.Spec.VolumeClaimTemplates) == 0 {
+		w.spec.Kind = types.WorkloadStatefulSet
+	}
+	if ss.Spec.PodManagementPolicy == appsv1.OrderedReadyPodManagement {
+		w.spec.PodManagement = appsv1.OrderedReadyPodManagement
+	}
+
+	if ss.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		w.spec.UpdateStrategy = types.UpdateStrategyOnDelete
+	} else if rolling := ss.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil && *rolling.Partition > 0 {
+		w.spec.Partition = rolling.Partition
+	}
+	return w
+}
+
+func fromDaemonSet(ds appsv1.DaemonSet) workload {
+	w := workload{
+		kind:     types.WorkloadDaemonSet,
+		meta:     ds.ObjectMeta,
+		selector: ds.Spec.Selector,
+		template: ds.Spec.Template,
+	}
+	w.spec.Global = true
+
+	if ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		w.spec.UpdateStrategy = types.UpdateStrategyOnDelete
+	} else if rolling := ds.Spec.UpdateStrategy.RollingUpdate; rolling != nil {
+		w.spec.MaxUnavailable = nonDefault(rolling.MaxUnavailable, intstr.FromInt(1))
+	}
+	return w
+}
+
+func replicas(scale *int32) *int {
+	if scale == nil || *scale == 1 {
+		return nil
+	}
+	n := int(*scale)
+	return &n
+}
+
+func nonDefault(value *intstr.IntOrString, def intstr.IntOrString) *intstr.IntOrString {
+	if value == nil || *value == def {
+		return nil
+	}
+	return value
+}
+
+// exportWorkload converts a workload and the Services, Ingresses and RBAC selecting it into a service of the Dollyfile
+func exportWorkload(objs Objects, w workload) types.Service {
+	selector := selectorLabels(w)
+
+	service := types.Service{
+		Name:        w.meta.Name,
+		Namespace:   w.meta.Namespace,
+		Labels:      cleanMap(w.template.Labels, sortedKeys(selector)...),
+		Annotations: cleanMap(w.template.Annotations, appArmorAnnotations(w.template.Annotations)...),
+		Spec:        w.spec,
+	}
+	if app := selector["app"]; app != service.Name {
+		service.Spec.App = app
+	}
+
+	podConfig(&service, w)
+	volumeTemplates(&service, w)
+	services(objs, &service, w)
+	ingresses(objs, &service)
+	permissions(objs, &service, w)
+	disruption(objs, &service, w)
+
+	return service
+}
+
+// selectorLabels returns the selector of the workload, which is the app label for workloads created by dolly
+func selectorLabels(w workload) map[string]string {
+	if w.selector == nil {
+		return nil
+	}
+	if len(w.selector.MatchExpressions) > 0 || len(w.selector.MatchLabels) != 1 || w.selector.MatchLabels["app"] == "" {
+		warnf(w, "selector %s is replaced by the app label, the workload must be recreated", metav1.FormatLabelSelector(w.selector))
+	}
+	return w.selector.MatchLabels
+}
+
+func podConfig(service *types.Service, w workload) {
+	spec := w.template.Spec
+	pc := &service.Spec.PodConfig
+
+	pc.Hostname = spec.Hostname
+	pc.HostAliases = spec.HostAliases
+	pc.HostNetwork = spec.HostNetwork
+	pc.NodeSelector = spec.NodeSelector
+	pc.Tolerations = spec.Tolerations
+	pc.PriorityClassName = spec.PriorityClassName
+
+	for _, secret := range spec.ImagePullSecrets {
+		pc.ImagePullSecrets = append(pc.ImagePullSecrets, secret.Name)
+	}
+
+	pc.DNS = dns(spec)
+
+	if psc := spec.SecurityContext; psc != nil {
+		pc.FSGroup = psc.FSGroup
+		pc.SupplementalGroups = psc.SupplementalGroups
+		pc.Sysctls = psc.Sysctls
+		if isRestricted(psc) {
+			pc.Security = types.SecurityPresetRestricted
+		} else if psc.SeccompProfile != nil || psc.RunAsUser != nil || psc.RunAsGroup != nil || psc.RunAsNonRoot != nil {
+			warnf(w, "pod security context is only exported for fsGroup, supplementalGroups and sysctls, set the security settings on the containers")
+		}
+	}
+
+	pc.Spread = spread(spec.TopologySpreadConstraints, w)
+	pc.Affinity, pc.AntiAffinity = antiAffinity(spec.Affinity, w)
+
+	containers(service, w)
+
+	grace := defaultTerminationGracePeriod
+	if drain := longestDrain(service); drain > 0 {
+		grace += drain
+	}
+	if spec.TerminationGracePeriodSeconds != nil && time.Duration(*spec.TerminationGracePeriodSeconds)*time.Second != grace {
+		pc.TerminationGracePeriod = &metav1.Duration{Duration: time.Duration(*spec.TerminationGracePeriodSeconds) * time.Second}
+	}
+}
+
+// isRestricted returns whether the pod security context is the one of the restricted security preset
+func isRestricted(psc *v1.PodSecurityContext) bool {
+	return psc != nil && psc.RunAsNonRoot != nil && *psc.RunAsNonRoot && psc.SeccompProfile != nil &&
+		psc.SeccompProfile.Type == v1.SeccompProfileTypeRuntimeDefault && psc.RunAsUser == nil && psc.RunAsGroup == nil
+}
+
+func dns(spec v1.PodSpec) *types.DNS {
+	result := &types.DNS{}
+	if spec.DNSPolicy != "" && spec.DNSPolicy != v1.DNSClusterFirst {
+		result.Policy = spec.DNSPolicy
+	}
+	if config := spec.DNSConfig; config != nil {
+		result.Nameservers = config.Nameservers
+		result.Searches = config.Searches
+		for _, opt := range config.Options {
+			result.Options = append(result.Options, types.PodDNSConfigOption{
+				Name:  opt.Name,
+				Value: opt.Value,
+			})
+		}
+	}
+	if reflect.DeepEqual(result, &types.DNS{}) {
+		return nil
+	}
+	return result
+}
+
+// spread converts the topology spread constraints of the pods of the workload to spread rules
+func spread(constraints []v1.TopologySpreadConstraint, w workload) (result []types.TopologyRule) {
+	for _, constraint := range constraints {
+		if constraint.LabelSelector == nil || w.selector == nil || !reflect.DeepEqual(constraint.LabelSelector.MatchLabels, w.selector.MatchLabels) {
+			warnf(w, "dropping topology spread constraint on %s that does not select the pods of the workload", constraint.TopologyKey)
+			continue
+		}
+		rule := types.TopologyRule{
+			Topology: topology(constraint.TopologyKey),
+			Required: constraint.WhenUnsatisfiable == v1.DoNotSchedule,
+		}
+		if constraint.MaxSkew > 1 {
+			rule.MaxSkew = constraint.MaxSkew
+		}
+		result = append(result, rule)
+	}
+	return
+}
+
+// antiAffinity moves the pod anti-affinity terms selecting the pods of the workload to anti-affinity rules, returning
+// the rest of the affinity
+func antiAffinity(affinity *v1.Affinity, w workload) (*v1.Affinity, []types.TopologyRule) {
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		return affinity, nil
+	}
+
+	affinity = affinity.DeepCopy()
+	anti := affinity.PodAntiAffinity
+
+	var (
+		rules     []types.TopologyRule
+		required  []v1.PodAffinityTerm
+		preferred []v1.WeightedPodAffinityTerm
+	)
+	for _, term := range anti.RequiredDuringSchedulingIgnoredDuringExecution {
+		if !selectsWorkload(term, w) {
+			required = append(required, term)
+			continue
+		}
+		rules = append(rules, types.TopologyRule{
+			Topology: topology(term.TopologyKey),
+			Required: true,
+		})
+	}
+	for _, term := range anti.PreferredDuringSchedulingIgnoredDuringExecution {
+		if !selectsWorkload(term.PodAffinityTerm, w) {
+			preferred = append(preferred, term)
+			continue
+		}
+		rule := types.TopologyRule{
+			Topology: topology(term.PodAffinityTerm.TopologyKey),
+		}
+		if term.Weight != 100 {
+			rule.Weight = term.Weight
+		}
+		rules = append(rules, rule)
+	}
+
+	anti.RequiredDuringSchedulingIgnoredDuringExecution = required
+	anti.PreferredDuringSchedulingIgnoredDuringExecution = preferred
+	if len(required) == 0 && len(preferred) == 0 {
+		affinity.PodAntiAffinity = nil
+	}
+	if reflect.DeepEqual(affinity, &v1.Affinity{}) {
+		affinity = nil
+	}
+	return affinity, rules
+}
+
+func selectsWorkload(term v1.PodAffinityTerm, w workload) bool {
+	return term.LabelSelector != nil && w.selector != nil && len(term.Namespaces) == 0 && len(term.LabelSelector.MatchExpressions) == 0 &&
+		reflect.DeepEqual(term.LabelSelector.MatchLabels, w.selector.MatchLabels)
+}
+
+func topology(key string) string {
+	if short, ok := topologyShorthands[key]; ok {
+		return short
+	}
+	return key
+}
+
+// disruption converts the PodDisruptionBudget selecting the pods of the workload
+func disruption(objs Objects, service *types.Service, w workload) {
+	for _, pdb := range objs.DisruptionBudgets {
+		if pdb.Spec.Selector == nil || w.selector == nil || !reflect.DeepEqual(pdb.Spec.Selector.MatchLabels, w.selector.MatchLabels) {
+			continue
+		}
+		service.Spec.Disruption = &types.DisruptionBudget{
+			MinAvailable:   pdb.Spec.MinAvailable,
+			MaxUnavailable: pdb.Spec.MaxUnavailable,
+		}
+	}
+}
+
+// volumeTemplates converts the volume claim templates of a StatefulSet
+func volumeTemplates(service *types.Service, w workload) {
+	for _, claim := range w.claims {
+		template := types.VolumeTemplate{
+			Name:        trimVolumePrefix(claim.Name),
+			Labels:      cleanMap(claim.Labels),
+			Annotations: cleanMap(claim.Annotations),
+			AccessModes: claim.Spec.AccessModes,
+			VolumeMode:  claim.Spec.VolumeMode,
+		}
+		if storage, ok := claim.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			template.StorageRequest = storage.Value()
+		}
+		if claim.Spec.StorageClassName != nil {
+			template.StorageClassName = *claim.Spec.StorageClassName
+		}
+		if template.VolumeMode != nil && *template.VolumeMode == v1.PersistentVolumeFilesystem {
+			template.VolumeMode = nil
+		}
+		service.Spec.VolumeTemplates = append(service.Spec.VolumeTemplates, template)
+	}
+}
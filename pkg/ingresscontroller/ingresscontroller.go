@@ -0,0 +1,218 @@
+package ingresscontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/convert/ingress"
+	"github.com/rancher/wrangler/pkg/kv"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var gatewayResource = schema.GroupVersionResource{
+	Group:    "gateway.networking.k8s.io",
+	Version:  "v1",
+	Resource: "gateways",
+}
+
+// Controller is an ingress controller found in the cluster
+type Controller struct {
+	Provider ingress.Provider
+	// Gateway the HTTPRoutes attach to as namespace/name, for the gateway provider
+	Gateway string
+	// Namespace and Selector of the pods of the controller
+	Namespace string
+	Selector  labels.Selector
+	// Port is the HTTP port of the pods
+	Port intstr.IntOrString
+}
+
+// Detect finds the ingress controller of the configuration, or the first of the providers that runs in the cluster if it
+// names none. It returns nil for the none provider. A gateway given without namespace is looked up in namespace.
+func Detect(ctx context.Context, k8s kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, config *types.Ingress) (*Controller, error) {
+	if config == nil {
+		config = &types.Ingress{}
+	}
+
+	switch config.Provider {
+	case types.IngressProviderNone:
+		return nil, nil
+	case types.IngressProviderGateway:
+		controller, err := gateway(ctx, k8s, dynamicClient, namespace, config.Gateway)
+		if err == nil && controller == nil {
+			err = fmt.Errorf("no Gateway found in the cluster, create one or set ingress.gateway in the dollyfile")
+		}
+		return controller, err
+	case "":
+	default:
+		provider, ok := ingress.ProviderOf(config)
+		if !ok || len(provider.Selectors) == 0 {
+			return nil, fmt.Errorf("unknown ingress provider %s, use traefik, nginx, contour, gateway or none", config.Provider)
+		}
+		controller, err := find(ctx, k8s, provider)
+		if err == nil && controller == nil {
+			err = fmt.Errorf("no %s ingress controller found in the cluster, install it or set the ingress provider to another "+
+				"one or to none", provider.Name)
+		}
+		return controller, err
+	}
+
+	for _, provider := range ingress.Providers {
+		controller, err := find(ctx, k8s, provider)
+		if err != nil || controller != nil {
+			return controller, err
+		}
+	}
+	controller, err := gateway(ctx, k8s, dynamicClient, namespace, config.Gateway)
+	if err == nil && controller == nil {
+		err = fmt.Errorf("no ingress controller found in the cluster, dolly looked for traefik, ingress-nginx, Contour and " +
+			"Gateway API gateways. Install one, or set the ingress provider to none to not expose the services by hostname")
+	}
+	return controller, err
+}
+
+// find looks for a pod of the provider in every namespace
+func find(ctx context.Context, k8s kubernetes.Interface, provider ingress.Provider) (*Controller, error) {
+	for i := range provider.Selectors {
+		selector, err := metav1.LabelSelectorAsSelector(&provider.Selectors[i])
+		if err != nil {
+			return nil, err
+		}
+		pods, err := k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+			Limit:         1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(pods.Items) == 0 {
+			continue
+		}
+		return &Controller{
+			Provider:  provider,
+			Namespace: pods.Items[0].Namespace,
+			Selector:  selector,
+			Port:      httpPort(pods.Items[0], provider.Ports),
+		}, nil
+	}
+	return nil, nil
+}
+
+// httpPort returns the first port of the pod with one of the names, port 80 if it has none of them
+func httpPort(pod v1.Pod, names []string) intstr.IntOrString {
+	for _, name := range names {
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name == name {
+					return intstr.FromString(name)
+				}
+			}
+		}
+	}
+	return intstr.FromInt(80)
+}
+
+// gateway finds a Gateway given as [namespace/]name, or the first Gateway of the cluster if name is empty. It returns nil
+// if the cluster doesn't serve the Gateway API or has no Gateway.
+func gateway(ctx context.Context, k8s kubernetes.Interface, dynamicClient dynamic.Interface, namespace, name string) (*Controller, error) {
+	if served, err := servesGateways(k8s); err != nil || !served {
+		return nil, err
+	}
+
+	var gw *unstructured.Unstructured
+	if name != "" {
+		ns, name := kv.RSplit(name, "/")
+		if ns == "" {
+			ns = namespace
+		}
+		obj, err := dynamicClient.Resource(gatewayResource).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("gateway %s/%s not found", ns, name)
+		} else if err != nil {
+			return nil, err
+		}
+		gw = obj
+	} else {
+		gateways, err := dynamicClient.Resource(gatewayResource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(gateways.Items) == 0 {
+			return nil, nil
+		}
+		sort.Slice(gateways.Items, func(i, j int) bool {
+			return key(&gateways.Items[i]) < key(&gateways.Items[j])
+		})
+		gw = &gateways.Items[0]
+	}
+
+	provider := ingress.GatewayProvider(gw.GetName())
+	// the pods of a gateway are in the namespace of the gateway or of its implementation
+	controller, err := find(ctx, k8s, provider)
+	if err != nil {
+		return nil, err
+	}
+	if controller == nil {
+		selector, err := metav1.LabelSelectorAsSelector(&provider.Selectors[0])
+		if err != nil {
+			return nil, err
+		}
+		controller = &Controller{
+			Provider:  provider,
+			Namespace: gw.GetNamespace(),
+			Selector:  selector,
+		}
+	}
+	controller.Gateway = key(gw)
+	// the implementations don't name the ports of their pods the same way, the port of the listener is forwarded
+	controller.Port = intstr.FromInt(int(listenerPort(gw)))
+	return controller, nil
+}
+
+// servesGateways reports whether the cluster serves the version of the Gateway API dolly uses
+func servesGateways(k8s kubernetes.Interface) (bool, error) {
+	groups, err := k8s.Discovery().ServerGroups()
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups.Groups {
+		if group.Name != gatewayResource.Group {
+			continue
+		}
+		for _, version := range group.Versions {
+			if version.Version == gatewayResource.Version {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func key(obj *unstructured.Unstructured) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// listenerPort returns the port of the first HTTP listener of a gateway, 80 if it has none
+func listenerPort(gw *unstructured.Unstructured) int64 {
+	listeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	for _, listener := range listeners {
+		listener, ok := listener.(map[string]interface{})
+		if !ok || listener["protocol"] != "HTTP" {
+			continue
+		}
+		if port, ok, _ := unstructured.NestedInt64(listener, "port"); ok {
+			return port
+		}
+	}
+	return 80
+}
@@ -0,0 +1,178 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// restartedAtAnnotation is set on the pod template to restart the pods of a workload, the same way kubectl rollout
+	// restart does
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	// replicasAnnotation keeps the replicas of a stopped workload for start
+	replicasAnnotation = "dolly.rancher.io/replicas"
+)
+
+// Workload is a deployment, statefulset or daemonset
+type Workload struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+func (w Workload) String() string {
+	return w.Kind + "/" + w.Name
+}
+
+// Workloads returns the workload of a target, the workloads named after it for a service of the dollyfile
+func Workloads(ctx context.Context, k8s kubernetes.Interface, target resolve.Target) ([]Workload, error) {
+	switch target.Type {
+	case types.DeploymentType, types.StatefulSetType, types.DaemonSetType:
+		return []Workload{{
+			Namespace: target.Namespace,
+			Kind:      target.Type,
+			Name:      target.Name,
+		}}, nil
+	case types.ServiceType:
+		var candidates []Workload
+		for _, kind := range []string{types.DeploymentType, types.StatefulSetType, types.DaemonSetType} {
+			candidates = append(candidates, Workload{
+				Namespace: target.Namespace,
+				Kind:      kind,
+				Name:      target.Name,
+			})
+		}
+		result, err := Existing(ctx, k8s, candidates)
+		if err == nil && len(result) == 0 {
+			err = fmt.Errorf("service %s has no deployment, statefulset or daemonset in namespace %s", target.Name, target.Namespace)
+		}
+		return result, err
+	}
+	return nil, fmt.Errorf("%s is not a deployment, statefulset or daemonset", target)
+}
+
+// Existing returns the workloads that exist
+func Existing(ctx context.Context, k8s kubernetes.Interface, workloads []Workload) ([]Workload, error) {
+	var result []Workload
+	for _, workload := range workloads {
+		_, _, err := get(ctx, k8s, workload.Namespace, workload.Kind, workload.Name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, workload)
+	}
+	return result, nil
+}
+
+// Restart replaces the pods of a deployment, statefulset or daemonset with a rolling update
+func Restart(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string) error {
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation,
+		time.Now().Format(time.RFC3339))
+	return patchWorkload(ctx, k8s, namespace, kind, name, patch)
+}
+
+// Scale sets the replicas of a deployment or statefulset
+func Scale(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string, replicas int32) error {
+	if kind == types.DaemonSetType {
+		return fmt.Errorf("%s/%s runs on every node and can't be scaled", kind, name)
+	}
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	return patchWorkload(ctx, k8s, namespace, kind, name, patch)
+}
+
+// Stop scales a deployment or statefulset to zero and keeps its replicas in an annotation for Start. It returns the replicas
+// it had, zero if it was stopped already.
+func Stop(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string) (int32, error) {
+	if kind == types.DaemonSetType {
+		return 0, fmt.Errorf("%s/%s runs on every node and can't be stopped", kind, name)
+	}
+	replicas, _, err := get(ctx, k8s, namespace, kind, name)
+	if err != nil || replicas == 0 {
+		return 0, err
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:"%d"}},"spec":{"replicas":0}}`, replicasAnnotation, replicas)
+	return replicas, patchWorkload(ctx, k8s, namespace, kind, name, patch)
+}
+
+// Start restores the replicas of a workload stopped by Stop and returns them. A workload that was not stopped keeps its
+// replicas.
+func Start(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string) (int32, error) {
+	if kind == types.DaemonSetType {
+		return 0, fmt.Errorf("%s/%s runs on every node and can't be started", kind, name)
+	}
+	replicas, annotations, err := get(ctx, k8s, namespace, kind, name)
+	if err != nil {
+		return 0, err
+	}
+	saved, ok := annotations[replicasAnnotation]
+	if !ok {
+		return replicas, nil
+	}
+	if replicas == 0 {
+		n, err := strconv.ParseInt(saved, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s annotation of %s/%s: %v", replicasAnnotation, kind, name, err)
+		}
+		replicas = int32(n)
+	}
+	// the annotation is dropped even if the workload was scaled up in the meantime
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}},"spec":{"replicas":%d}}`, replicasAnnotation, replicas)
+	return replicas, patchWorkload(ctx, k8s, namespace, kind, name, patch)
+}
+
+// get returns the replicas and the annotations of a workload, the replicas of a daemonset being its scheduled pods
+func get(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string) (int32, map[string]string, error) {
+	replicas := func(r *int32) int32 {
+		if r == nil {
+			return 1
+		}
+		return *r
+	}
+	switch kind {
+	case types.DeploymentType:
+		obj, err := k8s.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, nil, err
+		}
+		return replicas(obj.Spec.Replicas), obj.Annotations, nil
+	case types.StatefulSetType:
+		obj, err := k8s.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, nil, err
+		}
+		return replicas(obj.Spec.Replicas), obj.Annotations, nil
+	case types.DaemonSetType:
+		obj, err := k8s.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, nil, err
+		}
+		return obj.Status.DesiredNumberScheduled, obj.Annotations, nil
+	}
+	return 0, nil, fmt.Errorf("%s/%s is not a deployment, statefulset or daemonset", kind, name)
+}
+
+func patchWorkload(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name, patch string) error {
+	var err error
+	switch kind {
+	case types.DeploymentType:
+		_, err = k8s.AppsV1().Deployments(namespace).Patch(ctx, name, ktypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	case types.StatefulSetType:
+		_, err = k8s.AppsV1().StatefulSets(namespace).Patch(ctx, name, ktypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	case types.DaemonSetType:
+		_, err = k8s.AppsV1().DaemonSets(namespace).Patch(ctx, name, ktypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("%s/%s is not a deployment, statefulset or daemonset", kind, name)
+	}
+	return err
+}
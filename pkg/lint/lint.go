@@ -0,0 +1,222 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/utils"
+	"github.com/rancher/wrangler/pkg/gvk"
+	"gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type Level string
+
+const (
+	LevelError   = Level("error")
+	LevelWarning = Level("warning")
+)
+
+// Rule is a policy every rendered object of a dollyfile is checked against
+type Rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Level       Level  `json:"level"`
+
+	check func(t target, opts Options) []violation
+}
+
+// Finding is an object or service of the dollyfile that breaks a rule
+type Finding struct {
+	Rule      string `json:"rule"`
+	Level     Level  `json:"level"`
+	Message   string `json:"message"`
+	Service   string `json:"service,omitempty"`
+	Object    string `json:"object,omitempty"`
+	Container string `json:"container,omitempty"`
+	Line      int    `json:"line,omitempty"`
+}
+
+func (f Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s [%s]", f.Level, f.Message, f.Rule)
+	}
+	return fmt.Sprintf("%d: %s: %s [%s]", f.Line, f.Level, f.Message, f.Rule)
+}
+
+// Options configures the built-in rules
+type Options struct {
+	// Registries images must come from, such as registry.example.com or registry.example.com/team. Any registry is
+	// allowed if empty
+	AllowedRegistries []string
+	// IDs of the rules that are skipped
+	Disabled []string
+}
+
+// target is a rendered object, together with its pod spec if it runs containers and the service it was rendered from
+type target struct {
+	kind    string
+	name    string
+	content map[string]interface{}
+	podSpec *v1.PodSpec
+	service *types.Service
+}
+
+type violation struct {
+	container string
+	message   string
+}
+
+func (t target) String() string {
+	if t.service != nil {
+		return "service " + t.service.Name
+	}
+	return t.kind + " " + t.name
+}
+
+// Lint checks the services and rendered objects of the dollyfile against the rules. The contents of the dollyfile are
+// only used to find the line of a service or of the kubernetes objects, they can be nil.
+func Lint(contents []byte, rf *dollyfile.DollyFile, rules []Rule, opts Options) ([]Finding, error) {
+	targets, err := targets(rf)
+	if err != nil {
+		return nil, err
+	}
+	lines := lines(contents)
+
+	var findings []Finding
+	for _, rule := range rules {
+		if contains(opts.Disabled, rule.ID) {
+			continue
+		}
+		for _, t := range targets {
+			for _, v := range rule.check(t, opts) {
+				finding := Finding{
+					Rule:      rule.ID,
+					Level:     rule.Level,
+					Message:   v.message,
+					Container: v.container,
+				}
+				if t.service != nil {
+					finding.Service = t.service.Name
+					finding.Line = lines["services."+t.service.Name]
+				} else {
+					finding.Object = t.kind + "/" + t.name
+					finding.Line = lines["kubernetes"]
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// HasErrors returns true if one of the findings breaks a rule of level error
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Level == LevelError {
+			return true
+		}
+	}
+	return false
+}
+
+// targets returns the rendered objects, the workloads rendered from a service are matched with their service
+func targets(rf *dollyfile.DollyFile) ([]target, error) {
+	var result []target
+	for _, obj := range rf.Objects() {
+		kind, err := gvk.Get(obj)
+		if err != nil {
+			return nil, err
+		}
+		content, err := toUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		u := &unstructured.Unstructured{Object: content}
+		t := target{
+			kind:    kind.Kind,
+			name:    u.GetName(),
+			content: content,
+		}
+		if t.podSpec, err = podSpec(kind.Kind, content); err != nil {
+			return nil, fmt.Errorf("%s %s: %v", t.kind, t.name, err)
+		}
+		if service, ok := rf.Services[t.name]; ok && t.podSpec != nil && string(utils.WorkloadKind(service)) == t.kind {
+			t.service = &service
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func toUnstructured(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// podSpec returns the pod spec of the objects running containers, nil for the others
+func podSpec(kind string, content map[string]interface{}) (*v1.PodSpec, error) {
+	var path []string
+	switch kind {
+	case "Pod":
+		path = []string{"spec"}
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		path = []string{"spec", "template", "spec"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil, nil
+	}
+
+	data, ok, err := unstructured.NestedMap(content, path...)
+	if err != nil || !ok {
+		return nil, err
+	}
+	spec := &v1.PodSpec{}
+	return spec, runtime.DefaultUnstructuredConverter.FromUnstructured(data, spec)
+}
+
+// lines returns the line of every service and of the kubernetes objects in the dollyfile, the file is a template so it
+// may not parse before it is evaluated
+func lines(contents []byte) map[string]int {
+	result := map[string]int{}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(contents, doc); err != nil || len(doc.Content) == 0 {
+		return result
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "kubernetes", "manifest":
+			result["kubernetes"] = key.Line
+		case "services":
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				result["services."+value.Content[j].Value] = value.Content[j].Line
+			}
+		}
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
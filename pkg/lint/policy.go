@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/cel-go/cel"
+	"sigs.k8s.io/yaml"
+)
+
+// Policy is a file of custom rules
+type Policy struct {
+	Rules []PolicyRule `json:"rules,omitempty"`
+}
+
+// PolicyRule checks the rendered objects of the given kinds with a CEL expression
+type PolicyRule struct {
+	// ID of the rule, used to disable it and in the findings
+	ID string `json:"id,omitempty"`
+	// Description of the rule
+	Description string `json:"description,omitempty"`
+	// Message of the findings, defaults to the description
+	Message string `json:"message,omitempty"`
+	// Level of the rule, error or warning. Defaults to error
+	Level Level `json:"level,omitempty"`
+	// Kinds of the objects the rule applies to, such as Deployment or Ingress. Applies to every object if empty
+	Kinds []string `json:"kinds,omitempty"`
+	// CEL expression that must be true for every object, the object is the variable object, such as
+	// object.spec.replicas >= 2
+	Expression string `json:"expression,omitempty"`
+}
+
+// LoadPolicy reads the rules of a policy file
+func LoadPolicy(path string) ([]Rule, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(content, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %v", path, err)
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for i, p := range policy.Rules {
+		rule, err := p.rule(env)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s, rule %d: %v", path, i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (p PolicyRule) rule(env *cel.Env) (Rule, error) {
+	if p.ID == "" {
+		return Rule{}, fmt.Errorf("id is required")
+	}
+	if p.Expression == "" {
+		return Rule{}, fmt.Errorf("expression is required")
+	}
+
+	switch p.Level {
+	case "":
+		p.Level = LevelError
+	case LevelError, LevelWarning:
+	default:
+		return Rule{}, fmt.Errorf("invalid level %s, must be error or warning", p.Level)
+	}
+
+	ast, issues := env.Compile(p.Expression)
+	if issues.Err() != nil {
+		return Rule{}, fmt.Errorf("invalid expression %s: %v", p.Expression, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return Rule{}, fmt.Errorf("expression %s must return a bool, not %s", p.Expression, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid expression %s: %v", p.Expression, err)
+	}
+
+	return Rule{
+		ID:          p.ID,
+		Description: p.Description,
+		Level:       p.Level,
+		check: func(t target, opts Options) []violation {
+			if len(p.Kinds) > 0 && !contains(p.Kinds, t.kind) {
+				return nil
+			}
+
+			// a missing field fails the evaluation, like in a ValidatingAdmissionPolicy, and is reported as a finding
+			out, _, err := program.Eval(map[string]interface{}{
+				"object": t.content,
+			})
+			if err != nil {
+				return []violation{{message: p.message(t, err.Error())}}
+			}
+			if passed, ok := out.Value().(bool); !ok {
+				return []violation{{message: fmt.Sprintf("%s: expression of rule %s returned %v instead of a bool", t, p.ID, out.Value())}}
+			} else if !passed {
+				return []violation{{message: p.message(t, p.Expression)}}
+			}
+			return nil
+		},
+	}, nil
+}
+
+func (p PolicyRule) message(t target, reason string) string {
+	message := p.Message
+	if message == "" {
+		message = p.Description
+	}
+	if message == "" {
+		message = "breaks rule " + p.ID
+	}
+	return fmt.Sprintf("%s: %s (%s)", t, message, reason)
+}
@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `rules:
+- id: team-label
+  description: Objects must have a team label
+  level: warning
+  kinds: [ConfigMap]
+  expression: has(object.metadata.labels) && 'team' in object.metadata.labels
+- id: small-config
+  description: Config maps must hold at most one key
+  kinds: [ConfigMap]
+  expression: size(object.data) <= 1
+`
+
+func loadTestPolicy(t *testing.T, content string) ([]Rule, error) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadPolicy(path)
+}
+
+func TestPolicyExpressions(t *testing.T) {
+	rules, err := loadTestPolicy(t, testPolicy)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rf, err := dollyfile.Parse([]byte(`manifest: |-
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: labelled
+    labels:
+      team: web
+  data:
+    a: b
+  ---
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: unlabelled
+  data:
+    a: b
+    c: d
+`), "default", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	findings, err := Lint(nil, rf, rules, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []Finding{
+		{
+			Rule:    "team-label",
+			Level:   LevelWarning,
+			Message: "ConfigMap unlabelled: Objects must have a team label (has(object.metadata.labels) && 'team' in object.metadata.labels)",
+			Object:  "ConfigMap/unlabelled",
+		},
+		{
+			Rule:    "small-config",
+			Level:   LevelError,
+			Message: "ConfigMap unlabelled: Config maps must hold at most one key (size(object.data) <= 1)",
+			Object:  "ConfigMap/unlabelled",
+		},
+	}, findings)
+}
+
+func TestPolicyInvalidRules(t *testing.T) {
+	_, err := loadTestPolicy(t, "rules:\n- id: broken\n  expression: object.spec.replicas >=\n")
+	assert.Error(t, err)
+
+	_, err = loadTestPolicy(t, "rules:\n- id: number\n  expression: '1 + 1'\n")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "rule 1: expression 1 + 1 must return a bool, not int")
+	}
+}
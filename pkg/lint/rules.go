@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const defaultRegistry = "docker.io"
+
+// Rules are the built-in rules
+var Rules = []Rule{
+	{
+		ID:          "privileged",
+		Description: "Containers must not run privileged",
+		Level:       LevelError,
+		check: containerCheck(func(c v1.Container, t target, opts Options) string {
+			if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+				return fmt.Sprintf("container %s of %s runs privileged", c.Name, t)
+			}
+			return ""
+		}),
+	},
+	{
+		ID:          "host-network",
+		Description: "Pods must not use the network of the host",
+		Level:       LevelError,
+		check: func(t target, opts Options) []violation {
+			if t.podSpec == nil || !t.podSpec.HostNetwork {
+				return nil
+			}
+			return []violation{{message: fmt.Sprintf("%s uses the network of the host", t)}}
+		},
+	},
+	{
+		ID:          "host-path",
+		Description: "Pods must not mount paths of the host",
+		Level:       LevelError,
+		check: func(t target, opts Options) []violation {
+			if t.podSpec == nil {
+				return nil
+			}
+			var result []violation
+			for _, volume := range t.podSpec.Volumes {
+				if volume.HostPath != nil {
+					result = append(result, violation{
+						message: fmt.Sprintf("%s mounts the host path %s", t, volume.HostPath.Path),
+					})
+				}
+			}
+			return result
+		},
+	},
+	{
+		ID:          "allowed-registry",
+		Description: "Images must come from an allowed registry",
+		Level:       LevelError,
+		check: containerCheck(func(c v1.Container, t target, opts Options) string {
+			if len(opts.AllowedRegistries) == 0 || c.Image == "" || allowedImage(c.Image, opts.AllowedRegistries) {
+				return ""
+			}
+			return fmt.Sprintf("image %s of container %s of %s is not from an allowed registry (%s)", c.Image, c.Name, t,
+				strings.Join(opts.AllowedRegistries, ", "))
+		}),
+	},
+	{
+		ID:          "latest-tag",
+		Description: "Images must be pinned to a tag other than latest or to a digest",
+		Level:       LevelError,
+		check: containerCheck(func(c v1.Container, t target, opts Options) string {
+			// images built by dolly build are tagged when they are built
+			if c.Image == "" {
+				return ""
+			}
+			if tag := imageTag(c.Image); tag == "" || tag == "latest" {
+				return fmt.Sprintf("image %s of container %s of %s is not pinned to a tag other than latest", c.Image, c.Name, t)
+			}
+			return ""
+		}),
+	},
+	{
+		ID:          "memory-limit",
+		Description: "Containers must set a memory limit",
+		Level:       LevelError,
+		check: containerCheck(func(c v1.Container, t target, opts Options) string {
+			if _, ok := c.Resources.Limits[v1.ResourceMemory]; ok {
+				return ""
+			}
+			return fmt.Sprintf("container %s of %s has no memory limit", c.Name, t)
+		}),
+	},
+}
+
+// containerCheck runs the check on the containers and init containers of the target. The check returns an empty message
+// if the container passes.
+func containerCheck(check func(c v1.Container, t target, opts Options) string) func(t target, opts Options) []violation {
+	return func(t target, opts Options) []violation {
+		if t.podSpec == nil {
+			return nil
+		}
+
+		var result []violation
+		for _, containers := range [][]v1.Container{t.podSpec.InitContainers, t.podSpec.Containers} {
+			for _, c := range containers {
+				if message := check(c, t, opts); message != "" {
+					result = append(result, violation{
+						container: c.Name,
+						message:   message,
+					})
+				}
+			}
+		}
+		return result
+	}
+}
+
+// splitImage returns the registry, repository and tag of an image, the tag is empty for images referenced by digest
+// or without a tag
+func splitImage(image string) (string, string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	registry, repository := defaultRegistry, image
+	if i := strings.Index(image, "/"); i >= 0 {
+		if host := image[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry, repository = host, image[i+1:]
+		}
+	}
+	if registry == defaultRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	tag := ""
+	if i := strings.LastIndex(repository, ":"); i >= 0 {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	return registry, repository, tag
+}
+
+// imageTag returns the tag of the image, or digest if it is referenced by digest
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return "digest"
+	}
+	_, _, tag := splitImage(image)
+	return tag
+}
+
+// allowedImage returns true if the image is from one of the registries, which may include a path such as
+// registry.example.com/team
+func allowedImage(image string, registries []string) bool {
+	registry, repository, _ := splitImage(image)
+	name := registry + "/" + repository
+	for _, allowed := range registries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if name == allowed || strings.HasPrefix(name, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
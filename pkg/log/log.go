@@ -1,38 +1,109 @@
 package log
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"os"
 	"sync"
 	"text/template"
 
 	"github.com/fatih/color"
 	"github.com/stern/stern/stern"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+// colors of the pods and containers, picked by their names so a pod keeps its color
+var colors = []*color.Color{
+	color.New(color.FgHiCyan),
+	color.New(color.FgHiGreen),
+	color.New(color.FgHiMagenta),
+	color.New(color.FgHiYellow),
+	color.New(color.FgHiBlue),
+	color.New(color.FgHiRed),
+}
+
+// Streams limits the containers followed at once by the configs sharing it
+type Streams struct {
+	Max int
+
+	lock sync.Mutex
+	ids  map[string]bool
+}
+
+func (s *Streams) add(id string) error {
+	if s == nil || s.Max <= 0 {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ids == nil {
+		s.ids = map[string]bool{}
+	}
+	if !s.ids[id] && len(s.ids) >= s.Max {
+		return fmt.Errorf("more than %d containers to follow, raise the maximum with --max-log-requests", s.Max)
+	}
+	s.ids[id] = true
+	return nil
+}
+
+func (s *Streams) remove(id string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.ids, id)
+}
+
 func Output(ctx context.Context, conf *stern.Config, namespace string, k8s kubernetes.Interface) error {
+	return follow(ctx, conf, namespace, k8s, nil, nil)
+}
+
+// OutputTo writes the logs to out one line at a time, without the pods being added and removed
+func OutputTo(ctx context.Context, conf *stern.Config, namespace string, k8s kubernetes.Interface, out io.Writer) error {
+	return follow(ctx, conf, namespace, k8s, out, nil)
+}
+
+// Follow writes the logs of several configs to stdout, following at most maxRequests containers at once if it is positive
+func Follow(ctx context.Context, configs []*stern.Config, k8s kubernetes.Interface, maxRequests int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	streams := &Streams{
+		Max: maxRequests,
+	}
+	errs := make(chan error, len(configs))
+	for _, config := range configs {
+		go func(config *stern.Config) {
+			errs <- follow(ctx, config, config.Namespace, k8s, nil, streams)
+		}(config)
+	}
+	for range configs {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow writes the logs of the containers of the config to out, or to stdout with the containers being added and removed
+// on stderr if out is nil
+func follow(ctx context.Context, conf *stern.Config, namespace string, k8s kubernetes.Interface, out io.Writer, streams *Streams) error {
 	logCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	podInterface := k8s.CoreV1().Pods(namespace)
-	tails := make(map[string]*stern.Tail)
-	tailsMutex := sync.RWMutex{}
-
-	// See: https://github.com/linkerd/linkerd2/blob/c5a85e587c143d31f814d807e0e39cb4ad5e3572/cli/cmd/logs.go#L223-L227
-	logC := make(chan string, 1024)
-	go func() {
-		for {
-			select {
-			case str := <-logC:
-				fmt.Fprintf(os.Stdout, str)
-			case <-logCtx.Done():
-				break
-			}
-		}
-	}()
+	var (
+		tails     = map[string]context.CancelFunc{}
+		tailsLock sync.Mutex
+		tailsErr  error
+	)
 
 	added, removed, err := stern.Watch(
 		logCtx,
@@ -51,63 +122,129 @@ func Output(ctx context.Context, conf *stern.Config, namespace string, k8s kuber
 	go func() {
 		for a := range added {
 			id := a.GetID()
-			tailsMutex.RLock()
-			existing := tails[id]
-			tailsMutex.RUnlock()
-			if existing != nil {
-				tailsMutex.Lock()
-				tails[id].Close()
-				delete(tails, id)
-				tailsMutex.Unlock()
+			tailsLock.Lock()
+			// a target is added again on every change of its pod, it is followed already
+			if tails[id] != nil {
+				tailsLock.Unlock()
+				continue
 			}
-			tailOpts := &stern.TailOptions{
+			if err := streams.add(id); err != nil {
+				tailsErr = err
+				tailsLock.Unlock()
+				cancel()
+				continue
+			}
+			tailCtx, tailCancel := context.WithCancel(logCtx)
+			tails[id] = tailCancel
+			tailsLock.Unlock()
+
+			tail := stern.NewTail("", a.Namespace, a.Pod, a.Container, conf.Template, &stern.TailOptions{
 				SinceSeconds: int64(conf.Since.Seconds()),
 				Timestamps:   conf.Timestamps,
 				TailLines:    conf.TailLines,
 				Exclude:      conf.Exclude,
 				Include:      conf.Include,
 				Namespace:    true,
-			}
-			newTail := stern.NewTail("", a.Namespace, a.Pod, a.Container, conf.Template, tailOpts)
-			tailsMutex.Lock()
-			tails[id] = newTail
-			tailsMutex.Unlock()
-			newTail.Start(logCtx, podInterface)
+			})
+			go func() {
+				consume(tailCtx, podInterface, tail, out)
+				tailsLock.Lock()
+				delete(tails, id)
+				tailsLock.Unlock()
+				streams.remove(id)
+			}()
 		}
 	}()
 
 	go func() {
 		for r := range removed {
-			id := r.GetID()
-			tailsMutex.RLock()
-			existing := tails[id]
-			tailsMutex.RUnlock()
-			if existing == nil {
-				continue
+			tailsLock.Lock()
+			if tailCancel := tails[r.GetID()]; tailCancel != nil {
+				tailCancel()
 			}
-			tailsMutex.Lock()
-			tails[id].Close()
-			delete(tails, id)
-			tailsMutex.Unlock()
+			tailsLock.Unlock()
 		}
 	}()
 
 	<-logCtx.Done()
-	return nil
+	tailsLock.Lock()
+	defer tailsLock.Unlock()
+	return tailsErr
+}
+
+// consume writes the logs of the container of a tail to out, or to stdout if out is nil, until the context is done or the
+// container ends
+func consume(ctx context.Context, pods v1.PodInterface, tail *stern.Tail, out io.Writer) {
+	if out == nil {
+		out = os.Stdout
+		c := colorOf(tail.PodName)
+		fmt.Fprintf(os.Stderr, "%s %s %s › %s\n", color.HiGreenString("+"), c.Sprint(tail.Namespace), c.Sprint(tail.PodName),
+			colorOf(tail.ContainerName).Sprint(tail.ContainerName))
+		defer fmt.Fprintf(os.Stderr, "%s %s %s › %s\n", color.HiRedString("-"), c.Sprint(tail.Namespace), c.Sprint(tail.PodName),
+			colorOf(tail.ContainerName).Sprint(tail.ContainerName))
+	}
+
+	stream, err := pods.GetLogs(tail.PodName, &corev1.PodLogOptions{
+		Follow:       true,
+		Timestamps:   tail.Options.Timestamps,
+		Container:    tail.ContainerName,
+		SinceSeconds: &tail.Options.SinceSeconds,
+		TailLines:    tail.Options.TailLines,
+	}).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintf(out, "%s %s: %v\n", tail.PodName, tail.ContainerName, err)
+		}
+		return
+	}
+	defer stream.Close()
+
+	go func() {
+		<-ctx.Done()
+		stream.Close()
+	}()
+
+	w := &lineWriter{out: out}
+	if err := tail.ConsumeStream(stream, w); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(out, "%s %s: %v\n", tail.PodName, tail.ContainerName, err)
+	}
+}
+
+func colorOf(name string) *color.Color {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return colors[h.Sum32()%uint32(len(colors))]
+}
+
+// lineWriter writes whole lines, so the lines of several containers writing to the same writer don't mix
+type lineWriter struct {
+	out io.Writer
+	buf bytes.Buffer
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+	if i := bytes.LastIndexByte(l.buf.Bytes(), '\n'); i >= 0 {
+		if _, err := l.out.Write(l.buf.Next(i + 1)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
 }
 
-// Format is based on both wercker/stern and linkerd/stern templating
-func Format(format string, noColor bool) (*template.Template, error) {
+// Format is based on both wercker/stern and linkerd/stern templating. The lines are rendered and filtered by structured
+// if it is not nil.
+func Format(format string, noColor bool, structured *Structured) (*template.Template, error) {
 	var tpl string
 	switch format {
 	case "json":
 		tpl = "{{json .}}\n"
 	case "raw":
-		tpl = "{{.Message}}"
+		tpl = "{{render .Message}}\n"
 	default:
-		tpl = "{{color .PodColor .PodName}} {{color .ContainerColor .ContainerName}} {{.Message}}\n"
+		tpl = "{{color .PodName .PodName}} {{color .ContainerName .ContainerName}} {{render .Message}}\n"
 		if noColor {
-			tpl = "{{.PodName}} {{.ContainerName}} {{.Message}}"
+			tpl = "{{.PodName}} {{.ContainerName}} {{render .Message}}\n"
 		}
 	}
 	funk := map[string]interface{}{
@@ -118,11 +255,13 @@ func Format(format string, noColor bool) (*template.Template, error) {
 			}
 			return string(b), nil
 		},
-		"color": func(color color.Color, text string) string {
-			return color.SprintFunc()(text)
+		"color": func(name, text string) string {
+			return colorOf(name).Sprint(text)
 		},
+		"keep":   structured.keep,
+		"render": structured.render,
 	}
-	template, err := template.New("log").Funcs(funk).Parse(tpl)
+	template, err := template.New("log").Funcs(funk).Parse("{{if keep .Message}}" + tpl + "{{end}}")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,128 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Structured renders and filters the log lines that are JSON objects
+type Structured struct {
+	// Fields printed of JSON lines, in order, the whole line if empty
+	Fields []string
+	// Where are the conditions JSON lines have to meet, the other lines don't meet any
+	Where []Condition
+}
+
+// Condition compares a field of JSON lines to a value
+type Condition struct {
+	Field string
+	Value string
+	Not   bool
+}
+
+// ParseWhere parses conditions given as field=value or field!=value, the values being compared case insensitively
+func ParseWhere(exprs []string) ([]Condition, error) {
+	var result []Condition
+	for _, expr := range exprs {
+		i := strings.Index(expr, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid condition %s, expected field=value or field!=value", expr)
+		}
+		condition := Condition{
+			Field: expr[:i],
+			Value: expr[i+1:],
+		}
+		if strings.HasSuffix(condition.Field, "!") {
+			condition.Field = strings.TrimSuffix(condition.Field, "!")
+			condition.Not = true
+		}
+		result = append(result, condition)
+	}
+	return result, nil
+}
+
+func (s *Structured) keep(message string) bool {
+	if s == nil || len(s.Where) == 0 {
+		return true
+	}
+	_, fields, ok := parse(message)
+	if !ok {
+		return false
+	}
+	for _, condition := range s.Where {
+		value, found := lookup(fields, condition.Field)
+		if (found && strings.EqualFold(value, condition.Value)) == condition.Not {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Structured) render(message string) string {
+	if s == nil || len(s.Fields) == 0 {
+		return message
+	}
+	prefix, fields, ok := parse(message)
+	if !ok {
+		return message
+	}
+	var values []string
+	for _, field := range s.Fields {
+		if value, found := lookup(fields, field); found {
+			values = append(values, value)
+		}
+	}
+	return prefix + strings.Join(values, " ")
+}
+
+// parse returns the fields of a JSON line, which may start with the timestamp of the line
+func parse(message string) (string, map[string]interface{}, bool) {
+	i := strings.Index(message, "{")
+	if i < 0 || strings.Contains(strings.TrimSpace(message[:i]), " ") {
+		return "", nil, false
+	}
+	decoder := json.NewDecoder(strings.NewReader(message[i:]))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return "", nil, false
+	}
+	return message[:i], fields, true
+}
+
+// lookup returns a field as text, a field of a nested object is given as a path such as http.status
+func lookup(fields map[string]interface{}, path string) (string, bool) {
+	if value, ok := fields[path]; ok {
+		return text(value), true
+	}
+	for i := strings.Index(path, "."); i > 0; {
+		nested, ok := fields[path[:i]].(map[string]interface{})
+		if ok {
+			return lookup(nested, path[i+1:])
+		}
+		next := strings.Index(path[i+1:], ".")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return "", false
+}
+
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWhere(t *testing.T) {
+	conditions, err := ParseWhere([]string{"level=error", "http.status!=200", "msg=a=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Condition{
+		{Field: "level", Value: "error"},
+		{Field: "http.status", Value: "200", Not: true},
+		{Field: "msg", Value: "a=b"},
+	}, conditions)
+
+	for _, expr := range []string{"level", "=error", ""} {
+		_, err := ParseWhere([]string{expr})
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		message string
+		prefix  string
+		fields  map[string]interface{}
+		ok      bool
+	}{
+		{message: `{"level":"info"}`, fields: map[string]interface{}{"level": "info"}, ok: true},
+		{
+			message: `2021-03-04T05:06:07.123456789Z {"level":"info"}`,
+			prefix:  "2021-03-04T05:06:07.123456789Z ",
+			fields:  map[string]interface{}{"level": "info"},
+			ok:      true,
+		},
+		{message: `level=info msg={"a":1}`},
+		{message: `plain text`},
+		{message: `{"level":`},
+	} {
+		prefix, fields, ok := parse(test.message)
+		assert.Equal(t, test.ok, ok, test.message)
+		assert.Equal(t, test.prefix, prefix, test.message)
+		assert.Equal(t, test.fields, fields, test.message)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	_, fields, ok := parse(`{"level":"error","http":{"status":500,"path":"/api"},"a.b":"dotted","n":null,"tags":["x"]}`)
+	if !assert.True(t, ok) {
+		return
+	}
+	for _, test := range []struct {
+		path  string
+		value string
+		found bool
+	}{
+		{path: "level", value: "error", found: true},
+		{path: "http.status", value: "500", found: true},
+		{path: "http.path", value: "/api", found: true},
+		{path: "http", value: `{"path":"/api","status":500}`, found: true},
+		{path: "a.b", value: "dotted", found: true},
+		{path: "n", value: "null", found: true},
+		{path: "tags", value: `["x"]`, found: true},
+		{path: "http.method"},
+		{path: "missing"},
+	} {
+		value, found := lookup(fields, test.path)
+		assert.Equal(t, test.found, found, test.path)
+		assert.Equal(t, test.value, value, test.path)
+	}
+}
+
+func TestStructured(t *testing.T) {
+	where, err := ParseWhere([]string{"level=ERROR", "http.status!=200"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := &Structured{
+		Fields: []string{"level", "msg"},
+		Where:  where,
+	}
+
+	line := `2021-03-04T05:06:07Z {"level":"error","msg":"failed","http":{"status":500}}`
+	assert.True(t, s.keep(line))
+	assert.Equal(t, "2021-03-04T05:06:07Z error failed", s.render(line))
+
+	assert.False(t, s.keep(`{"level":"error","msg":"ok","http":{"status":200}}`))
+	assert.False(t, s.keep(`{"level":"info","msg":"failed","http":{"status":500}}`))
+	assert.False(t, s.keep("not json"))
+	assert.Equal(t, "not json", s.render("not json"))
+}
@@ -0,0 +1,299 @@
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// retryInterval is how long a forward waits before looking for a ready pod again
+const retryInterval = time.Second
+
+// Forward forwards a local port to a port of the pods of a target. The port is forwarded to a ready pod, another one
+// being picked whenever that pod is deleted or stops being ready.
+type Forward struct {
+	Target resolve.Target
+	// Port is the port of the Service, or of the containers without Service, TargetPort being the port of the containers
+	Port v1.ServicePort
+	// LocalPort is the port listened to on localhost, the one tried first by PickPorts if set
+	LocalPort int
+}
+
+// Forwards returns the ports of a target to forward. A service forwards the TCP ports of its Service, the other targets
+// the TCP ports declared by the containers of their pods.
+func Forwards(ctx context.Context, k8s kubernetes.Interface, target resolve.Target) ([]*Forward, error) {
+	var result []*Forward
+	if target.Type == types.ServiceType {
+		svc, err := k8s.CoreV1().Services(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			// a service without ports has no Service
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		// the Service of an external service has no pods to forward to
+		if len(svc.Spec.Selector) == 0 {
+			return nil, nil
+		}
+		target.Selector = labels.SelectorFromSet(svc.Spec.Selector)
+		for _, port := range svc.Spec.Ports {
+			// port-forward only supports TCP
+			if port.Protocol == v1.ProtocolTCP || port.Protocol == "" {
+				result = append(result, &Forward{
+					Target: target,
+					Port:   port,
+				})
+			}
+		}
+		return result, nil
+	}
+
+	pods, err := target.Pods(ctx, k8s)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("%s has no pods", target)
+	}
+	seen := map[int32]bool{}
+	for _, container := range pods[0].Spec.Containers {
+		for _, port := range container.Ports {
+			if seen[port.ContainerPort] || (port.Protocol != v1.ProtocolTCP && port.Protocol != "") {
+				continue
+			}
+			seen[port.ContainerPort] = true
+			result = append(result, &Forward{
+				Target: target,
+				Port: v1.ServicePort{
+					Name:       port.Name,
+					Port:       port.ContainerPort,
+					TargetPort: intstr.FromInt(int(port.ContainerPort)),
+				},
+			})
+		}
+	}
+	return result, nil
+}
+
+func (f *Forward) String() string {
+	if f.Port.Name == "" {
+		return fmt.Sprintf("%s:%d", f.Target, f.Port.Port)
+	}
+	return fmt.Sprintf("%s:%d (%s)", f.Target, f.Port.Port, f.Port.Name)
+}
+
+// Address is the local address of the forward, as a URL for HTTP ports
+func (f *Forward) Address() string {
+	address := fmt.Sprintf("localhost:%d", f.LocalPort)
+	switch {
+	case strings.HasPrefix(f.Port.Name, "https"):
+		return "https://" + address
+	case strings.HasPrefix(f.Port.Name, "http"):
+		return "http://" + address
+	}
+	return address
+}
+
+// PickPorts picks a free local port for each forward, trying the local port set, then the port itself and then any free
+// port
+func PickPorts(forwards []*Forward) error {
+	used := map[int]bool{}
+	for _, f := range forwards {
+		var port int
+		for _, candidate := range []int{f.LocalPort, int(f.Port.Port), 0} {
+			if candidate < 0 || (candidate > 0 && used[candidate]) {
+				continue
+			}
+			if free, err := listen(candidate); err == nil {
+				port = free
+				break
+			}
+		}
+		if port == 0 {
+			return fmt.Errorf("no free local port for %s", f)
+		}
+		used[port] = true
+		f.LocalPort = port
+	}
+	return nil
+}
+
+// listen checks that a local port is free, any free port being picked for 0
+func listen(port int) (int, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// Print writes a table of the local addresses and the ports they are forwarded to
+func Print(out io.Writer, forwards []*Forward) error {
+	sorted := append([]*Forward{}, forwards...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LocalPort < sorted[j].LocalPort
+	})
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "LOCAL\tSERVICE\tPORT\n")
+	for _, f := range sorted {
+		port := strconv.Itoa(int(f.Port.Port))
+		if f.Port.Name != "" {
+			port += "/" + f.Port.Name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Address(), f.Target, port)
+	}
+	return w.Flush()
+}
+
+// Run forwards the ports until the context is done
+func Run(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface, forwards []*Forward) {
+	var wg sync.WaitGroup
+	for _, f := range forwards {
+		wg.Add(1)
+		go func(f *Forward) {
+			defer wg.Done()
+			f.run(ctx, restConfig, k8s)
+		}(f)
+	}
+	wg.Wait()
+}
+
+func (f *Forward) run(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface) {
+	var (
+		connected string
+		waiting   bool
+	)
+	for ctx.Err() == nil {
+		pod, port, err := f.pod(ctx, k8s)
+		if err == nil {
+			waiting = false
+			if connected != "" && connected != pod.Name {
+				logrus.Infof("Forwarding %s to %s of pod %s", f.Address(), f.Target, pod.Name)
+			}
+			connected = pod.Name
+			err = f.forward(ctx, restConfig, k8s, pod, port)
+		} else if !waiting {
+			waiting = true
+			logrus.Infof("Waiting for a ready pod of %s to forward %s: %v", f.Target, f.Address(), err)
+		}
+		if err != nil && ctx.Err() == nil {
+			logrus.Debugf("Failed to forward %s to %s: %v", f.Address(), f, err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// pod picks a ready pod of the target and the port of its containers to forward to
+func (f *Forward) pod(ctx context.Context, k8s kubernetes.Interface) (v1.Pod, int32, error) {
+	pods, err := f.Target.Pods(ctx, k8s)
+	if err != nil {
+		return v1.Pod{}, 0, err
+	}
+	for _, pod := range pods {
+		if !resolve.Running(pod) || pod.Status.Phase != v1.PodRunning || !resolve.Ready(pod) {
+			continue
+		}
+		port, err := containerPort(pod, f.Port)
+		if err != nil {
+			return v1.Pod{}, 0, err
+		}
+		return pod, port, nil
+	}
+	return v1.Pod{}, 0, fmt.Errorf("%s has no ready pods", f.Target)
+}
+
+// containerPort returns the port of the containers of a pod a port routes to, looking up the named ports
+func containerPort(pod v1.Pod, port v1.ServicePort) (int32, error) {
+	switch {
+	case port.TargetPort.Type == intstr.String:
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == port.TargetPort.StrVal {
+					return containerPort.ContainerPort, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("pod %s has no port named %s", pod.Name, port.TargetPort.StrVal)
+	case port.TargetPort.IntVal != 0:
+		return port.TargetPort.IntVal, nil
+	}
+	return port.Port, nil
+}
+
+// forward forwards the local port to the pod until the context is done or the pod goes away
+func (f *Forward) forward(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface, pod v1.Pod, port int32) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stopChan := make(chan struct{})
+	go func() {
+		// the port forward doesn't end when the pod is deleted, it is stopped once the pod is gone
+		waitForPodGone(watchCtx, k8s, pod)
+		close(stopChan)
+	}()
+
+	return PortForward(restConfig, k8s, Option{
+		Pod:        pod,
+		Port:       strconv.Itoa(f.LocalPort),
+		TargetPort: strconv.Itoa(int(port)),
+		Stdout:     logrus.IsLevelEnabled(logrus.DebugLevel),
+		ReadyChan:  make(chan struct{}, 1),
+		StopChan:   stopChan,
+	})
+}
+
+// waitForPodGone waits until the context is done, or the pod is deleted or isn't ready anymore
+func waitForPodGone(ctx context.Context, k8s kubernetes.Interface, pod v1.Pod) {
+	pods := k8s.CoreV1().Pods(pod.Namespace)
+	resourceVersion := pod.ResourceVersion
+	for ctx.Err() == nil {
+		w, err := pods.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			return
+		}
+		for event := range w.ResultChan() {
+			current, ok := event.Object.(*v1.Pod)
+			switch {
+			case event.Type == watch.Deleted || event.Type == watch.Error:
+				w.Stop()
+				return
+			case !ok || current.Name != pod.Name:
+				continue
+			case current.UID != pod.UID || !resolve.Running(*current) || !resolve.Ready(*current):
+				w.Stop()
+				return
+			}
+			resourceVersion = current.ResourceVersion
+		}
+		// the watch ended, it is watched again from the last version seen
+	}
+}
@@ -26,7 +26,9 @@ type Option struct {
 	StopChan   chan struct{}
 }
 
-func PortForward(restConfig *rest.Config, k8s *kubernetes.Clientset, option Option) error {
+func PortForward(restConfig *rest.Config, k8s kubernetes.Interface, option Option) error {
+	// the defaults are set on a copy, several ports being forwarded at once with the same config
+	restConfig = rest.CopyConfig(restConfig)
 	if err := setConfigDefaults(restConfig); err != nil {
 		return err
 	}
@@ -0,0 +1,185 @@
+package remote
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// CopyToPod copies a local file or directory to a path of a container, which needs tar. A path ending with / is a
+// directory the source is copied into.
+func CopyToPod(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface, option Option, src, dest string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	if strings.HasSuffix(dest, "/") {
+		dest = path.Join(dest, filepath.Base(src))
+	}
+	dest = path.Clean(dest)
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTar(writer, src, path.Base(dest)))
+	}()
+
+	stderr := &bytes.Buffer{}
+	option.Command = []string{"tar", "-xmf", "-", "-C", path.Dir(dest)}
+	option.Stdin = true
+	option.TTY = false
+	option.In = reader
+	option.Out = nil
+	option.ErrOut = stderr
+	if err := Exec(ctx, restConfig, k8s, option); err != nil {
+		return tarError(err, stderr)
+	}
+	return nil
+}
+
+// CopyFromPod copies a file or directory of a container, which needs tar, to a local path. If the local path is an
+// existing directory the source is copied into it.
+func CopyFromPod(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface, option Option, src, dest string) error {
+	src = path.Clean(src)
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, path.Base(src))
+	}
+
+	reader, writer := io.Pipe()
+	stderr := &bytes.Buffer{}
+	option.Command = []string{"tar", "-cf", "-", "-C", path.Dir(src), path.Base(src)}
+	option.Stdin = false
+	option.TTY = false
+	option.Out = writer
+	option.ErrOut = stderr
+
+	result := make(chan error, 1)
+	go func() {
+		err := Exec(ctx, restConfig, k8s, option)
+		writer.CloseWithError(err)
+		result <- err
+	}()
+
+	if err := readTar(reader, path.Base(src), dest); err != nil {
+		// drain the stream so the command can finish
+		_, _ = io.Copy(ioutil.Discard, reader)
+		if execErr := <-result; execErr != nil {
+			return tarError(execErr, stderr)
+		}
+		return err
+	}
+	if err := <-result; err != nil {
+		return tarError(err, stderr)
+	}
+	return nil
+}
+
+// writeTar writes the file or directory src to the archive, renamed to name
+func writeTar(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readTar extracts the archive of the file or directory name to dest. Entries outside of name and links are skipped, so
+// a container can't write outside of dest.
+func readTar(r io.Reader, name, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := path.Clean(header.Name)
+		if entry != name && !strings.HasPrefix(entry, name+"/") {
+			logrus.Warnf("skipping %s, it is outside of %s", header.Name, name)
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(entry, name)))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		default:
+			logrus.Warnf("skipping %s, links and special files are not copied", header.Name)
+		}
+	}
+}
+
+func writeFile(file string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func tarError(err error, stderr *bytes.Buffer) error {
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return fmt.Errorf("%v: %s", err, message)
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package remote
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTar(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, entry := range []tar.Header{
+		{Name: "app/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "app/config.yaml", Typeflag: tar.TypeReg, Mode: 0644, Size: 5},
+		{Name: "app/../../etc/passwd", Typeflag: tar.TypeReg, Mode: 0644, Size: 5},
+		{Name: "/etc/shadow", Typeflag: tar.TypeReg, Mode: 0644, Size: 5},
+		{Name: "application/config.yaml", Typeflag: tar.TypeReg, Mode: 0644, Size: 5},
+		{Name: "app/link", Typeflag: tar.TypeSymlink, Linkname: "../../../etc"},
+		{Name: "app/hardlink", Typeflag: tar.TypeLink, Linkname: "/etc/passwd"},
+		{Name: "app/sub/data", Typeflag: tar.TypeReg, Mode: 0600, Size: 5},
+	} {
+		entry := entry
+		assert.NoError(t, tw.WriteHeader(&entry))
+		if entry.Size > 0 {
+			_, err := tw.Write([]byte("hello"))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, tw.Close())
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "out", "copy")
+	assert.NoError(t, readTar(buf, "app", dest))
+	assert.Equal(t, []string{
+		"out",
+		"out/copy",
+		"out/copy/config.yaml",
+		"out/copy/sub",
+		"out/copy/sub/data",
+	}, files(t, dir))
+}
+
+func TestWriteTar(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "sub", "data"), []byte("hello"), 0644))
+	assert.NoError(t, os.Symlink("/etc/passwd", filepath.Join(src, "link")))
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, writeTar(buf, src, "app"))
+
+	headers := map[string]*tar.Header{}
+	tr := tar.NewReader(bytes.NewReader(buf.Bytes()))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		headers[header.Name] = header
+	}
+
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"app", "app/link", "app/sub", "app/sub/data"}, names)
+	assert.Equal(t, byte(tar.TypeSymlink), headers["app/link"].Typeflag)
+	assert.Equal(t, "/etc/passwd", headers["app/link"].Linkname)
+
+	// the link is not followed when the archive is read back
+	dest := filepath.Join(dir, "dest")
+	assert.NoError(t, readTar(bytes.NewReader(buf.Bytes()), "app", dest))
+	_, err := os.Lstat(filepath.Join(dest, "link"))
+	assert.True(t, os.IsNotExist(err))
+	content, err := ioutil.ReadFile(filepath.Join(dest, "sub", "data"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "cp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// files lists the files and directories below dir as slash separated relative paths
+func files(t *testing.T, dir string) []string {
+	var result []string
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || file == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		result = append(result, filepath.ToSlash(rel))
+		return err
+	})
+	assert.NoError(t, err)
+	return result
+}
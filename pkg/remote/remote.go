@@ -0,0 +1,194 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+// defaultContainerAnnotation selects the container of a pod kubectl uses when none is given
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// Option selects the container of a pod and the streams connected to it
+type Option struct {
+	Namespace string
+	Pod       string
+	// Container of the pod, defaults to the one of the default-container annotation or the first one
+	Container string
+	// Command run by exec
+	Command []string
+	// Connect In to the stdin of the container
+	Stdin bool
+	// Allocate a terminal, In is set raw and the terminal size is sent when it changes
+	TTY bool
+
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+	// Terminal In reads from, if In is not the terminal itself
+	Terminal *os.File
+}
+
+// Exec runs a command in a container of a running pod
+func Exec(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface, option Option) error {
+	pod, err := runningPod(ctx, k8s, &option)
+	if err != nil {
+		return err
+	}
+	if len(option.Command) == 0 {
+		return fmt.Errorf("a command is required")
+	}
+
+	tty := option.terminal()
+	req := k8s.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: option.Container,
+			Command:   option.Command,
+			Stdin:     option.Stdin,
+			Stdout:    option.Out != nil,
+			Stderr:    option.ErrOut != nil && !tty.Raw,
+			TTY:       tty.Raw,
+		}, scheme.ParameterCodec)
+
+	return stream(restConfig, req, option, tty)
+}
+
+// Attach connects to the main process of a container of a running pod
+func Attach(ctx context.Context, restConfig *rest.Config, k8s kubernetes.Interface, option Option) error {
+	pod, err := runningPod(ctx, k8s, &option)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range pod.Spec.Containers {
+		if c.Name != option.Container {
+			continue
+		}
+		if option.Stdin && !c.Stdin {
+			logrus.Warnf("container %s does not keep stdin open, set stdin: true on the container", c.Name)
+			option.Stdin = false
+		}
+		if option.TTY && !c.TTY {
+			logrus.Warnf("container %s has no terminal, set tty: true on the container", c.Name)
+			option.TTY = false
+		}
+	}
+
+	tty := option.terminal()
+	req := k8s.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("attach").
+		VersionedParams(&v1.PodAttachOptions{
+			Container: option.Container,
+			Stdin:     option.Stdin,
+			Stdout:    option.Out != nil,
+			Stderr:    option.ErrOut != nil && !tty.Raw,
+			TTY:       tty.Raw,
+		}, scheme.ParameterCodec)
+
+	return stream(restConfig, req, option, tty)
+}
+
+// terminal returns the terminal of the streams, it is only set raw if a TTY is requested and In is a terminal
+func (o *Option) terminal() term.TTY {
+	tty := term.TTY{
+		Out: o.Out,
+	}
+	if o.Stdin {
+		tty.In = o.In
+		if o.Terminal != nil {
+			tty.In = o.Terminal
+		}
+	}
+	if o.TTY {
+		if !tty.IsTerminalIn() {
+			logrus.Warn("Unable to use a TTY, input is not a terminal")
+		} else {
+			tty.Raw = true
+		}
+	}
+	return tty
+}
+
+func stream(restConfig *rest.Config, req *rest.Request, option Option, tty term.TTY) error {
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdout: option.Out,
+		Stderr: option.ErrOut,
+		Tty:    tty.Raw,
+	}
+	if option.Stdin {
+		streamOptions.Stdin = option.In
+	}
+	if tty.Raw {
+		// a terminal merges stderr into stdout
+		streamOptions.Stderr = nil
+		streamOptions.TerminalSizeQueue = tty.MonitorSize(tty.GetSize())
+	}
+
+	return tty.Safe(func() error {
+		return executor.Stream(streamOptions)
+	})
+}
+
+// runningPod returns the pod of the option and defaults its container
+func runningPod(ctx context.Context, k8s kubernetes.Interface, option *Option) (*v1.Pod, error) {
+	pod, err := k8s.CoreV1().Pods(option.Namespace).Get(ctx, option.Pod, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return nil, fmt.Errorf("pod %s is not running, it is %s", pod.Name, pod.Status.Phase)
+	}
+	if option.Container == "" {
+		option.Container = DefaultContainer(pod)
+	} else if !hasContainer(pod, option.Container) {
+		return nil, fmt.Errorf("container %s not found in pod %s", option.Container, pod.Name)
+	}
+	return pod, nil
+}
+
+// DefaultContainer returns the container of the default-container annotation or the first container of the pod
+func DefaultContainer(pod *v1.Pod) string {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" && hasContainer(pod, name) {
+		return name
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+func hasContainer(pod *v1.Pod, name string) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
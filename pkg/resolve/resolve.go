@@ -0,0 +1,249 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/table/types"
+	dollytypes "github.com/rancher/dolly/pkg/types"
+	convertlabels "github.com/rancher/dolly/pkg/types/convert/labels"
+	"github.com/rancher/wrangler/pkg/kv"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+)
+
+// lookupTypes are looked up in order for a name without a type
+var lookupTypes = []string{types.DeploymentType, types.StatefulSetType, types.DaemonSetType, types.JobType, types.CronJobType,
+	types.PodType}
+
+// Target is a service of the dollyfile, a workload or a pod given on the command line as [namespace:][type/]name
+type Target struct {
+	Namespace string
+	Name      string
+	// Type is types.ServiceType for a service, otherwise the type of the workload or types.PodType
+	Type string
+	// Selector of the pods of a service or workload, nil for a pod
+	Selector labels.Selector
+}
+
+func (t Target) String() string {
+	if t.Type == types.ServiceType {
+		return t.Name
+	}
+	return t.Type + "/" + t.Name
+}
+
+// Resolve finds the target of an argument. A plain name is a service of the dollyfile, selected by its app label, then a
+// deployment, statefulset, daemonset, job or cronjob, then a pod of that name. If none exists it is a service selected by
+// the app label of the name, as long as pods have that label.
+func Resolve(ctx context.Context, k8s kubernetes.Interface, services map[string]dollytypes.Service, namespace, arg string) (Target, error) {
+	ns, name := kv.RSplit(arg, ":")
+	if ns == "" {
+		ns = namespace
+	}
+	kind, name := kv.RSplit(name, "/")
+	if kind != "" {
+		if alias, ok := types.Aliases[kind]; ok {
+			kind = alias
+		}
+		return resolveType(ctx, k8s, ns, kind, name)
+	}
+
+	if service, ok := services[name]; ok {
+		return Target{
+			Namespace: ns,
+			Name:      name,
+			Type:      types.ServiceType,
+			Selector:  labels.SelectorFromSet(convertlabels.SelectorLabels(service)),
+		}, nil
+	}
+
+	for _, kind := range lookupTypes {
+		target, err := resolveType(ctx, k8s, ns, kind, name)
+		if err == nil || !errors.IsNotFound(err) {
+			return target, err
+		}
+	}
+
+	selector := labels.SelectorFromSet(convertlabels.SelectorLabels(dollytypes.Service{Name: name}))
+	pods, err := k8s.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+		Limit:         1,
+	})
+	if err != nil {
+		return Target{}, err
+	}
+	if len(pods.Items) == 0 {
+		return Target{}, fmt.Errorf("no service, workload or pod named %s in namespace %s", name, ns)
+	}
+	return Target{
+		Namespace: ns,
+		Name:      name,
+		Type:      types.ServiceType,
+		Selector:  selector,
+	}, nil
+}
+
+func resolveType(ctx context.Context, k8s kubernetes.Interface, namespace, kind, name string) (Target, error) {
+	target := Target{
+		Namespace: namespace,
+		Name:      name,
+		Type:      kind,
+	}
+
+	var (
+		selector *metav1.LabelSelector
+		err      error
+	)
+	switch kind {
+	case types.PodType:
+		_, err = k8s.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		return target, err
+	case types.DeploymentType:
+		obj, getErr := k8s.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = obj.Spec.Selector
+		}
+	case types.StatefulSetType:
+		obj, getErr := k8s.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = obj.Spec.Selector
+		}
+	case types.DaemonSetType:
+		obj, getErr := k8s.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = obj.Spec.Selector
+		}
+	case types.JobType:
+		obj, getErr := k8s.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			selector = obj.Spec.Selector
+		}
+	case types.CronJobType:
+		obj, getErr := k8s.BatchV1beta1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			target.Selector, err = cronJobSelector(ctx, k8s, obj)
+		}
+		return target, err
+	default:
+		return target, fmt.Errorf("unsupported type %s, use a service name or one of pod, deploy, sts, ds, job or cronjob", kind)
+	}
+	if err != nil {
+		return target, err
+	}
+
+	target.Selector, err = metav1.LabelSelectorAsSelector(selector)
+	return target, err
+}
+
+// cronJobSelector selects the pods of the jobs of a cronjob that are kept
+func cronJobSelector(ctx context.Context, k8s kubernetes.Interface, cronJob *batchv1beta1.CronJob) (labels.Selector, error) {
+	jobs, err := k8s.BatchV1().Jobs(cronJob.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, job := range jobs.Items {
+		if owner := metav1.GetControllerOf(&job); owner != nil && owner.UID == cronJob.UID {
+			names = append(names, job.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("cronjob %s has no jobs", cronJob.Name)
+	}
+	requirement, err := labels.NewRequirement("job-name", selection.In, names)
+	if err != nil {
+		return nil, err
+	}
+	return labels.NewSelector().Add(*requirement), nil
+}
+
+// Pods returns the pods of the target sorted by name, with the pods of a statefulset in the order of their ordinals
+func (t Target) Pods(ctx context.Context, k8s kubernetes.Interface) ([]v1.Pod, error) {
+	if t.Selector == nil {
+		pod, err := k8s.CoreV1().Pods(t.Namespace).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []v1.Pod{*pod}, nil
+	}
+
+	pods, err := k8s.CoreV1().Pods(t.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: t.Selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := pods.Items
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Name) != len(result[j].Name) {
+			return len(result[i].Name) < len(result[j].Name)
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// Pod picks a running pod of the target. A non negative index picks the pod at that position of the running pods,
+// otherwise a ready pod, the one returned by choose if several are ready and choose is not nil.
+func (t Target) Pod(ctx context.Context, k8s kubernetes.Interface, index int, choose func([]v1.Pod) (int, error)) (string, error) {
+	pods, err := t.Pods(ctx, k8s)
+	if err != nil {
+		return "", err
+	}
+
+	var running, ready []v1.Pod
+	for _, pod := range pods {
+		if Running(pod) {
+			running = append(running, pod)
+			if Ready(pod) {
+				ready = append(ready, pod)
+			}
+		}
+	}
+
+	switch {
+	case len(running) == 0:
+		return "", fmt.Errorf("%s has no running pods", t)
+	case index >= len(running):
+		return "", fmt.Errorf("%s has %d running pods, the index has to be lower", t, len(running))
+	case index >= 0:
+		return running[index].Name, nil
+	case t.Selector == nil:
+		// a pod given by name doesn't have to be ready
+		return running[0].Name, nil
+	case len(ready) == 0:
+		return "", fmt.Errorf("%s has no ready pods, pick a running one with an index", t)
+	case len(ready) > 1 && choose != nil:
+		i, err := choose(ready)
+		if err != nil {
+			return "", err
+		}
+		if i < 0 || i >= len(ready) {
+			return "", fmt.Errorf("there is no pod %d", i)
+		}
+		return ready[i].Name, nil
+	}
+	return ready[0].Name, nil
+}
+
+// Running reports whether the pod has not finished
+func Running(pod v1.Pod) bool {
+	return pod.DeletionTimestamp == nil && pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed
+}
+
+// Ready reports whether the pod is ready to serve
+func Ready(pod v1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package scaffold
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/rancher/wrangler/pkg/data/convert"
+	"gopkg.in/yaml.v3"
+)
+
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+type compose struct {
+	file     string
+	services []Service
+}
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image       string                 `yaml:"image"`
+	Build       interface{}            `yaml:"build"`
+	Ports       []interface{}          `yaml:"ports"`
+	Expose      []interface{}          `yaml:"expose"`
+	Environment interface{}            `yaml:"environment"`
+	Entrypoint  interface{}            `yaml:"entrypoint"`
+	Command     interface{}            `yaml:"command"`
+	Healthcheck map[string]interface{} `yaml:"healthcheck"`
+	Deploy      struct {
+		Resources struct {
+			Limits struct {
+				CPUs   string `yaml:"cpus"`
+				Memory string `yaml:"memory"`
+			} `yaml:"limits"`
+			Reservations struct {
+				CPUs   string `yaml:"cpus"`
+				Memory string `yaml:"memory"`
+			} `yaml:"reservations"`
+		} `yaml:"resources"`
+	} `yaml:"deploy"`
+}
+
+// detectCompose reads the services of a docker compose file
+func detectCompose(dir string) (*compose, error) {
+	for _, name := range composeFiles {
+		file := filepath.Join(dir, name)
+		if !exists(file) {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cf := composeFile{}
+		if err := yaml.Unmarshal(content, &cf); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+
+		result := &compose{
+			file: name,
+		}
+		var names []string
+		for name := range cf.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			result.services = append(result.services, cf.Services[name].service(name))
+		}
+		return result, nil
+	}
+	return nil, nil
+}
+
+func (c composeService) service(name string) Service {
+	result := Service{
+		Name:               Name(name),
+		Image:              c.Image,
+		Build:              composeBuild(c.Build),
+		Command:            words(c.Entrypoint),
+		Args:               words(c.Command),
+		Env:                environment(c.Environment),
+		ComposeHealthcheck: c.Healthcheck,
+		CPUs:               requestLimit(c.Deploy.Resources.Reservations.CPUs, c.Deploy.Resources.Limits.CPUs),
+		Memory:             requestLimit(composeMemory(c.Deploy.Resources.Reservations.Memory), composeMemory(c.Deploy.Resources.Limits.Memory)),
+	}
+
+	seen := map[string]bool{}
+	for _, port := range c.Ports {
+		if p, ok := composePort(port); ok && !seen[p.key()] {
+			seen[p.key()] = true
+			result.Ports = append(result.Ports, p)
+		}
+	}
+	for _, port := range c.Expose {
+		if p, ok := exposedPort(convert.ToString(port)); ok && !seen[p.key()] {
+			seen[p.key()] = true
+			result.Ports = append(result.Ports, p)
+		}
+	}
+
+	if result.Memory == "" {
+		result.Memory = "64Mi:256Mi"
+	}
+	if result.CPUs == "" {
+		result.CPUs = "100m:500m"
+	}
+	if result.ComposeHealthcheck == nil && len(result.Ports) > 0 {
+		result.Healthcheck = fmt.Sprintf("tcp://:%d", result.Ports[0].Container)
+	}
+	return result
+}
+
+// composeBuild reads a build that is either the context or a map with the context and dockerfile
+func composeBuild(build interface{}) *Build {
+	switch build := build.(type) {
+	case string:
+		return &Build{Context: build}
+	case map[string]interface{}:
+		result := &Build{
+			Context:    convert.ToString(build["context"]),
+			Dockerfile: convert.ToString(build["dockerfile"]),
+		}
+		if result.Context == "" {
+			result.Context = "."
+		}
+		if result.Dockerfile != "" {
+			result.Dockerfile = path.Join(result.Context, result.Dockerfile)
+		}
+		return result
+	}
+	return nil
+}
+
+// composePort reads a port in the short syntax, [[ip:]published:]target[/protocol], or the long syntax. Port ranges are
+// skipped.
+func composePort(port interface{}) (Port, bool) {
+	if long, ok := port.(map[string]interface{}); ok {
+		target, err := convert.ToNumber(long["target"])
+		if err != nil || target <= 0 {
+			return Port{}, false
+		}
+		published, _ := strconv.Atoi(convert.ToString(long["published"]))
+		return Port{
+			Port:      published,
+			Container: int(target),
+			Protocol:  appProtocol(int(target), strings.ToLower(convert.ToString(long["protocol"]))),
+		}, true
+	}
+
+	value := convert.ToString(port)
+	protocol := ""
+	if i := strings.Index(value, "/"); i >= 0 {
+		value, protocol = value[:i], strings.ToLower(value[i+1:])
+	}
+
+	parts := strings.Split(value, ":")
+	target, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || target <= 0 {
+		return Port{}, false
+	}
+	result := Port{
+		Container: target,
+		Protocol:  appProtocol(target, protocol),
+	}
+	if len(parts) > 1 {
+		result.Port, _ = strconv.Atoi(parts[len(parts)-2])
+	}
+	return result, true
+}
+
+// environment reads the environment as a list of KEY=value or a map
+func environment(env interface{}) []string {
+	switch env := env.(type) {
+	case []interface{}:
+		var result []string
+		for _, v := range env {
+			result = append(result, convert.ToString(v))
+		}
+		return result
+	case map[string]interface{}:
+		var result []string
+		for k, v := range env {
+			// a variable without value is read from the host by compose, which dolly can't do
+			result = append(result, k+"="+convert.ToString(v))
+		}
+		sort.Strings(result)
+		return result
+	}
+	return nil
+}
+
+// words reads a command that is either a list or a string in the shell syntax
+func words(command interface{}) []string {
+	switch command := command.(type) {
+	case string:
+		result, err := shellwords.Parse(command)
+		if err != nil {
+			return strings.Fields(command)
+		}
+		return result
+	case []interface{}:
+		var result []string
+		for _, v := range command {
+			result = append(result, convert.ToString(v))
+		}
+		return result
+	}
+	return nil
+}
+
+// composeMemory converts a memory size of compose, such as 512m or 1gb, to a quantity
+func composeMemory(memory string) string {
+	memory = strings.ToLower(strings.TrimSpace(memory))
+	for suffix, unit := range map[string]string{"kb": "Ki", "k": "Ki", "mb": "Mi", "m": "Mi", "gb": "Gi", "g": "Gi"} {
+		if strings.HasSuffix(memory, suffix) {
+			if _, err := strconv.ParseFloat(strings.TrimSuffix(memory, suffix), 64); err == nil {
+				return strings.TrimSuffix(memory, suffix) + unit
+			}
+		}
+	}
+	return memory
+}
+
+func requestLimit(request, limit string) string {
+	switch {
+	case request == "" && limit == "":
+		return ""
+	case limit == "":
+		return request
+	case request == "":
+		return limit + ":" + limit
+	}
+	return request + ":" + limit
+}
@@ -0,0 +1,175 @@
+package scaffold
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var dockerfiles = []string{"Dockerfile", "dockerfile", "Containerfile"}
+
+type dockerfile struct {
+	file        string
+	ports       []Port
+	healthcheck string
+}
+
+func detectDockerfile(dir string) (*dockerfile, error) {
+	for _, name := range dockerfiles {
+		path := filepath.Join(dir, name)
+		if !exists(path) {
+			continue
+		}
+		result, err := parseDockerfile(path)
+		if err != nil {
+			return nil, err
+		}
+		result.file = name
+		return result, nil
+	}
+	return nil, nil
+}
+
+// parseDockerfile reads the EXPOSE and HEALTHCHECK instructions of a Dockerfile. Kubernetes ignores the healthcheck of
+// the image, so it becomes the healthcheck of the service.
+func parseDockerfile(path string) (*dockerfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		result  = &dockerfile{}
+		scanner = bufio.NewScanner(f)
+		line    string
+	)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+		// instructions continue on the next line after a backslash
+		if strings.HasSuffix(text, "\\") {
+			line += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+		line += text
+
+		instruction, fields := line, strings.Fields(line)
+		line = ""
+		if len(fields) < 2 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "FROM":
+			// only the instructions of the last stage end up in the image
+			result.ports, result.healthcheck = nil, ""
+		case "EXPOSE":
+			for _, field := range fields[1:] {
+				if port, ok := exposedPort(field); ok {
+					result.ports = append(result.ports, port)
+				}
+			}
+		case "HEALTHCHECK":
+			result.healthcheck = healthcheck(strings.TrimSpace(instruction[len(fields[0]):]))
+		}
+	}
+	return result, scanner.Err()
+}
+
+// exposedPort parses a port of an EXPOSE instruction such as 8080 or 53/udp, ports set by variables are skipped
+func exposedPort(value string) (Port, bool) {
+	protocol := ""
+	if i := strings.Index(value, "/"); i >= 0 {
+		value, protocol = value[:i], strings.ToLower(value[i+1:])
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port <= 0 {
+		return Port{}, false
+	}
+	return Port{
+		Container: port,
+		Protocol:  appProtocol(port, protocol),
+	}, true
+}
+
+// healthcheckOptions are the options of a HEALTHCHECK instruction and the probe options they become
+var healthcheckOptions = map[string]string{
+	"--interval":     "interval",
+	"--timeout":      "timeout",
+	"--retries":      "threshold",
+	"--start-period": "delay",
+}
+
+// healthcheck converts the arguments of a HEALTHCHECK instruction into a cmd: probe. The shell form runs in a shell
+// as it does in docker.
+func healthcheck(args string) string {
+	var options []string
+	fields := strings.Fields(args)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		k, v := fields[0], ""
+		if i := strings.Index(k, "="); i >= 0 {
+			k, v = k[:i], k[i+1:]
+		}
+		if option, ok := healthcheckOptions[k]; ok && v != "" {
+			options = append(options, option+"="+v)
+		}
+		args = strings.TrimSpace(strings.TrimPrefix(args, fields[0]))
+		fields = fields[1:]
+	}
+	if len(fields) < 2 || strings.ToUpper(fields[0]) != "CMD" {
+		return ""
+	}
+
+	var words []string
+	command := strings.TrimSpace(args[len(fields[0]):])
+	// the exec form is a json array
+	if err := json.Unmarshal([]byte(command), &words); err != nil {
+		words = []string{"sh", "-c", command}
+	}
+	for i, word := range words {
+		if strings.ContainsAny(word, " '\"$|&;<>*?") {
+			words[i] = "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
+		}
+	}
+
+	return strings.Join(append([]string{"cmd:" + strings.Join(words, " ")}, options...), ",")
+}
+
+// appProtocol returns the protocol of a port as dolly declares it, tcp ports are http unless they are the well known
+// port of a database, a broker or of tls
+func appProtocol(port int, protocol string) string {
+	switch protocol {
+	case "udp", "sctp":
+		return protocol
+	}
+	if tcpPorts[port] {
+		return "tcp"
+	}
+	return "http"
+}
+
+var tcpPorts = map[int]bool{
+	22:    true,
+	25:    true,
+	53:    true,
+	443:   true,
+	1433:  true,
+	1521:  true,
+	2181:  true,
+	3306:  true,
+	4222:  true,
+	5432:  true,
+	5672:  true,
+	6379:  true,
+	7000:  true,
+	8443:  true,
+	9042:  true,
+	9092:  true,
+	11211: true,
+	27017: true,
+}
@@ -0,0 +1,131 @@
+package scaffold
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// language holds the defaults of the services written in a language
+type language struct {
+	file   string
+	name   string
+	port   int
+	cpus   string
+	memory string
+	// the runtime takes a while to start, such as the JVM
+	slowStart bool
+}
+
+// languages are checked in order, the first manifest found is the language of the project
+var languages = []struct {
+	files    []string
+	defaults language
+	name     func(path string) string
+}{
+	{
+		files:    []string{"go.mod"},
+		defaults: language{port: 8080, cpus: "100m:500m", memory: "64Mi:256Mi"},
+		name:     goModule,
+	},
+	{
+		files:    []string{"package.json"},
+		defaults: language{port: 3000, cpus: "100m:1", memory: "128Mi:512Mi"},
+		name:     packageName,
+	},
+	{
+		files:    []string{"pyproject.toml", "requirements.txt", "Pipfile"},
+		defaults: language{port: 8000, cpus: "100m:1", memory: "128Mi:512Mi"},
+		name:     pyprojectName,
+	},
+	{
+		files:    []string{"pom.xml", "build.gradle", "build.gradle.kts"},
+		defaults: language{port: 8080, cpus: "250m:1", memory: "512Mi:1Gi", slowStart: true},
+	},
+	{
+		files:    []string{"Gemfile"},
+		defaults: language{port: 3000, cpus: "100m:1", memory: "256Mi:512Mi"},
+	},
+	{
+		files:    []string{"composer.json"},
+		defaults: language{port: 80, cpus: "100m:500m", memory: "128Mi:256Mi"},
+	},
+	{
+		files:    []string{"Cargo.toml"},
+		defaults: language{port: 8080, cpus: "100m:500m", memory: "64Mi:256Mi"},
+		name:     cargoName,
+	},
+}
+
+func detectLanguage(dir string) (*language, error) {
+	for _, lang := range languages {
+		for _, file := range lang.files {
+			path := filepath.Join(dir, file)
+			if !exists(path) {
+				continue
+			}
+
+			result := lang.defaults
+			result.file = file
+			if lang.name != nil {
+				result.name = lang.name(path)
+			}
+			return &result, nil
+		}
+	}
+	return nil, nil
+}
+
+// goModule returns the last element of the module path of a go.mod
+func goModule(path string) string {
+	return firstMatch(path, regexp.MustCompile(`^module\s+"?([^\s"]+)`))
+}
+
+// packageName returns the name of a package.json, without its scope
+func packageName(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	pkg := struct {
+		Name string `json:"name"`
+	}{}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Name
+}
+
+// pyprojectName returns the project name of a pyproject.toml
+func pyprojectName(path string) string {
+	if filepath.Base(path) != "pyproject.toml" {
+		return ""
+	}
+	return firstMatch(path, regexp.MustCompile(`^name\s*=\s*"([^"]+)"`))
+}
+
+// cargoName returns the package name of a Cargo.toml
+func cargoName(path string) string {
+	return firstMatch(path, regexp.MustCompile(`^name\s*=\s*"([^"]+)"`))
+}
+
+// firstMatch returns the submatch of the first line of the file matching the expression
+func firstMatch(path string, expr *regexp.Regexp) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if match := expr.FindStringSubmatch(strings.TrimSpace(scanner.Text())); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,341 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const referenceURL = "https://dolly.do.rancher.space/reference/"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Project is what was detected in a directory to scaffold a Dollyfile
+type Project struct {
+	// Files the services were detected from, such as Dockerfile or go.mod
+	Sources []string
+	// Services of the project
+	Services []Service
+}
+
+// Service is a detected service
+type Service struct {
+	Name        string
+	Image       string
+	Build       *Build
+	Ports       []Port
+	Command     []string
+	Args        []string
+	Env         []string
+	Healthcheck string
+	// Compose healthcheck, translated by dolly up
+	ComposeHealthcheck map[string]interface{}
+	StartupProbe       string
+	CPUs               string
+	Memory             string
+}
+
+// Build is where the image of a service is built from
+type Build struct {
+	Context    string
+	Dockerfile string
+}
+
+// Port is a port a service listens on
+type Port struct {
+	// Port of the service, 0 if it is the same as the container port
+	Port      int
+	Container int
+	Protocol  string
+}
+
+func (p Port) String() string {
+	if p.Port != 0 && p.Port != p.Container {
+		return fmt.Sprintf("%d:%d/%s", p.Port, p.Container, p.Protocol)
+	}
+	return fmt.Sprintf("%d/%s", p.Container, p.Protocol)
+}
+
+func (p Port) key() string {
+	return fmt.Sprintf("%d/%s", p.Container, p.Protocol)
+}
+
+// Detect inspects a directory for a compose file, a Dockerfile and the manifests of common languages. A compose file
+// defines the services, otherwise a single service named after the directory or the language manifest is detected,
+// with the ports of the EXPOSE lines of the Dockerfile and the defaults of the language.
+func Detect(dir string) (*Project, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &Project{}
+	compose, err := detectCompose(abs)
+	if err != nil {
+		return nil, err
+	}
+	if compose != nil {
+		project.Sources = append(project.Sources, compose.file)
+		project.Services = compose.services
+		return project, nil
+	}
+
+	service := Service{
+		Name: Name(filepath.Base(abs)),
+	}
+
+	lang, err := detectLanguage(abs)
+	if err != nil {
+		return nil, err
+	}
+	if lang != nil {
+		project.Sources = append(project.Sources, lang.file)
+		if lang.name != "" {
+			service.Name = Name(lang.name)
+		}
+		service.Ports = []Port{{Container: lang.port, Protocol: "http"}}
+		service.CPUs = lang.cpus
+		service.Memory = lang.memory
+	}
+
+	dockerfile, err := detectDockerfile(abs)
+	if err != nil {
+		return nil, err
+	}
+	if dockerfile != nil {
+		project.Sources = append(project.Sources, dockerfile.file)
+		service.Build = &Build{
+			Context:    ".",
+			Dockerfile: dockerfile.file,
+		}
+		if len(dockerfile.ports) > 0 {
+			service.Ports = dockerfile.ports
+		}
+		if dockerfile.healthcheck != "" {
+			service.Healthcheck = dockerfile.healthcheck
+		}
+	}
+
+	if service.CPUs == "" {
+		service.CPUs = "100m:500m"
+	}
+	if service.Memory == "" {
+		service.Memory = "64Mi:256Mi"
+	}
+	if service.Healthcheck == "" && len(service.Ports) > 0 {
+		service.Healthcheck = fmt.Sprintf("tcp://:%d", service.Ports[0].Container)
+	}
+	if lang != nil && lang.slowStart && len(service.Ports) > 0 {
+		service.StartupProbe = fmt.Sprintf("tcp://:%d,interval=5s,threshold=30", service.Ports[0].Container)
+	}
+
+	project.Services = []Service{service}
+	return project, nil
+}
+
+// FromDockerfile returns a project of a single service built from the Dockerfile, with the ports of its EXPOSE lines
+func FromDockerfile(name, path string) (*Project, error) {
+	dockerfile, err := parseDockerfile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	service := Service{
+		Name: Name(name),
+		Build: &Build{
+			Context:    filepath.Dir(path),
+			Dockerfile: path,
+		},
+		Ports:       dockerfile.ports,
+		Healthcheck: dockerfile.healthcheck,
+	}
+	if service.Healthcheck == "" && len(service.Ports) > 0 {
+		service.Healthcheck = fmt.Sprintf("tcp://:%d", service.Ports[0].Container)
+	}
+
+	return &Project{
+		Sources:  []string{path},
+		Services: []Service{service},
+	}, nil
+}
+
+// HasBuild returns true if a service can be built from source
+func (p *Project) HasBuild() bool {
+	for _, service := range p.Services {
+		if service.Build != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Dollyfile returns a commented Dollyfile of the project. With build the services that can be built from source get a
+// build section, otherwise they run an image named after the service.
+func (p *Project) Dollyfile(build bool) []byte {
+	w := &writer{}
+
+	if len(p.Sources) > 0 {
+		w.line(0, "# Generated by dolly init from %s.", strings.Join(p.Sources, ", "))
+	} else {
+		w.line(0, "# Generated by dolly init.")
+	}
+	w.line(0, "# Every option is described in %s", referenceURL)
+	w.line(0, "services:")
+
+	for i, service := range p.Services {
+		if i > 0 {
+			w.line(0, "")
+		}
+		w.line(1, "%s:", service.Name)
+		service.write(w, build)
+	}
+
+	return []byte(w.String())
+}
+
+func (s Service) write(w *writer, build bool) {
+	switch {
+	case build && s.Build != nil:
+		image := s.Image
+		if image == "" {
+			image = s.Name
+		}
+		w.line(2, "# Built from source by dolly build and dolly up and tagged as the image")
+		w.line(2, "build:")
+		w.line(3, "context: %s", s.Build.Context)
+		if s.Build.Dockerfile != "" {
+			w.line(3, "dockerfile: %s", s.Build.Dockerfile)
+		}
+		w.line(2, "image: %s", image)
+	case s.Image != "":
+		w.line(2, "image: %s", s.Image)
+	default:
+		w.line(2, "# Image to run, pin it to a version such as registry.example.com/%s:1.0.0", s.Name)
+		w.line(2, "image: %s", s.Name)
+	}
+
+	if len(s.Command) > 0 {
+		w.list(2, "command", s.Command)
+	}
+	if len(s.Args) > 0 {
+		w.list(2, "args", s.Args)
+	}
+
+	if len(s.Ports) > 0 {
+		w.line(2, "# Ports as [servicePort:]containerPort/protocol, http ports are exposed through the ingress")
+		var ports []string
+		for _, port := range s.Ports {
+			ports = append(ports, port.String())
+		}
+		w.list(2, "ports", ports)
+	}
+
+	if len(s.Env) > 0 {
+		w.list(2, "env", s.Env)
+	}
+
+	switch {
+	case s.ComposeHealthcheck != nil:
+		w.line(2, "# Used as both the liveness and readiness probe")
+		w.line(2, "healthcheck:")
+		keys := make([]string, 0, len(s.ComposeHealthcheck))
+		for key := range s.ComposeHealthcheck {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			w.line(3, "%s: %s", key, value(s.ComposeHealthcheck[key]))
+		}
+	case s.Healthcheck != "":
+		w.line(2, "# Used as both the liveness and readiness probe, an http check such as http://:%d/healthz is more precise",
+			port(s.Ports))
+		w.line(2, "healthcheck: %s", quote(s.Healthcheck))
+	}
+	if s.StartupProbe != "" {
+		w.line(2, "# Gives the container time to start before the healthcheck restarts it")
+		w.line(2, "startupProbe: %s", s.StartupProbe)
+	}
+
+	if s.CPUs != "" || s.Memory != "" {
+		w.line(2, "# Resources as request:limit")
+	}
+	if s.CPUs != "" {
+		w.line(2, "cpus: %s", s.CPUs)
+	}
+	if s.Memory != "" {
+		w.line(2, "memory: %s", s.Memory)
+	}
+}
+
+// Name returns a valid service name
+func Name(name string) string {
+	name = strings.ToLower(name)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "app"
+	}
+	return name
+}
+
+func port(ports []Port) int {
+	if len(ports) == 0 {
+		return 8080
+	}
+	return ports[0].Container
+}
+
+// value returns a value of a compose healthcheck, lists are written in the flow style
+func value(v interface{}) string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return quote(v)
+	}
+	var values []string
+	for _, item := range list {
+		values = append(values, quote(item))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+// quote returns the value as a yaml scalar, strings are only quoted if yaml would not read them as a plain string
+func quote(v interface{}) string {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%q", fmt.Sprint(v))
+	}
+	return strings.TrimSuffix(string(content), "\n")
+}
+
+type writer struct {
+	strings.Builder
+}
+
+func (w *writer) line(indent int, format string, args ...interface{}) {
+	if format == "" {
+		w.WriteString("\n")
+		return
+	}
+	w.WriteString(strings.Repeat("  ", indent))
+	fmt.Fprintf(w, format, args...)
+	w.WriteString("\n")
+}
+
+func (w *writer) list(indent int, key string, values []string) {
+	w.line(indent, "%s:", key)
+	for _, value := range values {
+		w.line(indent, "- %s", quote(value))
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	meta1 "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,6 +20,9 @@ const (
 	PodType             = "pod"
 	DeploymentType      = "deploy"
 	DaemonSetType       = "ds"
+	StatefulSetType     = "sts"
+	JobType             = "job"
+	CronJobType         = "cronjob"
 	NamespaceType       = "namespace"
 	RouterType          = "router"
 	ExternalServiceType = "externalservice"
@@ -37,6 +42,13 @@ var (
 		"deployment":       DeploymentType,
 		"deployments":      DeploymentType,
 		"deploys":          DeploymentType,
+		"daemonset":        DaemonSetType,
+		"daemonsets":       DaemonSetType,
+		"statefulset":      StatefulSetType,
+		"statefulsets":     StatefulSetType,
+		"jobs":             JobType,
+		"cj":               CronJobType,
+		"cronjobs":         CronJobType,
 		"routers":          RouterType,
 		"externalservices": ExternalServiceType,
 		"secrets":          SecretType,
@@ -97,6 +109,12 @@ func FromObject(obj runtime.Object) (Resource, error) {
 		result.Type = DeploymentType
 	case *appsv1.DaemonSet:
 		result.Type = DaemonSetType
+	case *appsv1.StatefulSet:
+		result.Type = StatefulSetType
+	case *batchv1.Job:
+		result.Type = JobType
+	case *batchv1beta1.CronJob:
+		result.Type = CronJobType
 	case *corev1.ConfigMap:
 		result.Type = ConfigType
 	default:
@@ -19,14 +19,14 @@ func NewPods(namespace, format string, quiet bool) TableWriter {
 		{"DETAIL", "{{.Obj | podDetail}}"},
 	}, namespace, quiet, format)
 
-	writer.AddFormatFunc("podReady", podReady)
-	writer.AddFormatFunc("podDetail", podDetail)
+	writer.AddFormatFunc("podReady", PodReady)
+	writer.AddFormatFunc("podDetail", PodDetail)
 	return &tableWriter{
 		writer: writer,
 	}
 }
 
-func podDetail(obj interface{}) string {
+func PodDetail(obj interface{}) string {
 	pod, _ := obj.(*v1.Pod)
 	return detail(pod)
 }
@@ -61,7 +61,7 @@ func detail(pod *v1.Pod) string {
 	return strings.Trim(output.String(), "; ")
 }
 
-func podReady(obj interface{}) (string, error) {
+func PodReady(obj interface{}) (string, error) {
 	podData, _ := obj.(*v1.Pod)
 	ready := 0
 	total := 0
@@ -2,25 +2,56 @@ package tables
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/rancher/dolly/pkg/table"
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
-func NewService(namespace, format string, quiet bool) TableWriter {
-	writer := table.NewWriter([][]string{
+// Related holds the objects of the columns that are not part of the workloads themselves
+type Related struct {
+	Pods      []*v1.Pod
+	Services  []*v1.Service
+	Ingresses []*networkingv1.Ingress
+}
+
+func NewService(namespace, format string, quiet, wide bool, related *Related) TableWriter {
+	columns := [][]string{
 		{"NAME", "{{.Obj | id}}"},
 		{"IMAGE", "{{.Obj | image}}"},
 		{"SCALE", "{{.Obj | scale}}"},
+		{"READY", "{{.Obj | ready}}"},
+		{"UPDATED", "{{.Obj | updated}}"},
+		{"RESTARTS", "{{restarts .Context .Obj}}"},
+		{"PORTS", "{{.Obj | ports}}"},
+		{"STATUS", "{{.Obj | rollout}}"},
 		{"CREATED", "{{.Obj.CreationTimestamp | ago}}"},
-	}, namespace, quiet, format)
+	}
+	if wide {
+		columns = append(columns, []string{"ENDPOINTS", "{{endpoints .Context .Obj}}"})
+	}
+	writer := table.NewWriter(columns, namespace, quiet, format)
 
 	writer.AddFormatFunc("image", FormatImage)
 	writer.AddFormatFunc("scale", formatRevisionScale)
+	writer.AddFormatFunc("ready", FormatReady)
+	writer.AddFormatFunc("updated", formatUpdated)
+	writer.AddFormatFunc("restarts", formatRestarts)
+	writer.AddFormatFunc("ports", formatPorts)
+	writer.AddFormatFunc("rollout", RolloutStatus)
+	writer.AddFormatFunc("endpoints", formatEndpoints)
 
 	return &tableWriter{
-		writer: writer,
+		writer:  writer,
+		context: related,
 	}
 }
 
@@ -40,6 +71,17 @@ func formatRevisionScale(data interface{}) (string, error) {
 		return FormatScale(scale,
 			int(v.Status.AvailableReplicas),
 			int(v.Status.UnavailableReplicas))
+	case *appsv1.StatefulSet:
+		scale := 1
+		if v.Spec.Replicas != nil {
+			scale = int(*v.Spec.Replicas)
+		}
+		ready := int(v.Status.ReadyReplicas)
+		unavailable := 0
+		if ready < scale {
+			unavailable = scale - ready
+		}
+		return FormatScale(&scale, ready, unavailable)
 	}
 	return "", nil
 }
@@ -72,28 +114,206 @@ func FormatScale(scale *int, available, unavailable int) (string, error) {
 	return fmt.Sprintf("%s%d%s", prefix, scaleNum, percentage), nil
 }
 
-func getDaemonSetImage(d *appsv1.DaemonSet) string {
-	if len(d.Spec.Template.Spec.Containers) > 0 {
-		return d.Spec.Template.Spec.Containers[0].Image
+// replicas returns the desired, ready and updated replicas of a workload
+func replicas(data interface{}) (desired, ready, updated int32, ok bool) {
+	switch v := data.(type) {
+	case *appsv1.Deployment:
+		desired = 1
+		if v.Spec.Replicas != nil {
+			desired = *v.Spec.Replicas
+		}
+		return desired, v.Status.ReadyReplicas, v.Status.UpdatedReplicas, true
+	case *appsv1.StatefulSet:
+		desired = 1
+		if v.Spec.Replicas != nil {
+			desired = *v.Spec.Replicas
+		}
+		return desired, v.Status.ReadyReplicas, v.Status.UpdatedReplicas, true
+	case *appsv1.DaemonSet:
+		return v.Status.DesiredNumberScheduled, v.Status.NumberReady, v.Status.UpdatedNumberScheduled, true
 	}
-	return ""
+	return 0, 0, 0, false
 }
 
-func getDeploymentImage(d *appsv1.Deployment) string {
-	if len(d.Spec.Template.Spec.Containers) > 0 {
-		return d.Spec.Template.Spec.Containers[0].Image
+func FormatReady(data interface{}) string {
+	desired, ready, _, ok := replicas(data)
+	if !ok {
+		return ""
 	}
-	return ""
+	return fmt.Sprintf("%d/%d", ready, desired)
 }
 
-func FormatImage(data interface{}) string {
-	image := ""
+func formatUpdated(data interface{}) string {
+	_, _, updated, ok := replicas(data)
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(int(updated))
+}
+
+// RolloutStatus returns the state of the rollout of a workload, the way kubectl rollout status judges it
+func RolloutStatus(data interface{}) string {
 	switch v := data.(type) {
 	case *appsv1.Deployment:
-		image = getDeploymentImage(v)
+		if v.Generation > v.Status.ObservedGeneration {
+			return "Pending"
+		}
+		for _, cond := range v.Status.Conditions {
+			if cond.Type == appsv1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+				return "Stalled"
+			}
+		}
+		desired, _, updated, _ := replicas(v)
+		switch {
+		case desired == 0 && v.Status.Replicas == 0:
+			return "Stopped"
+		case updated < desired, v.Status.Replicas > updated, v.Status.AvailableReplicas < updated:
+			return "Updating"
+		}
+	case *appsv1.StatefulSet:
+		if v.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			return "OnDelete"
+		}
+		if v.Generation > v.Status.ObservedGeneration {
+			return "Pending"
+		}
+		desired, ready, updated, _ := replicas(v)
+		partition := int32(0)
+		if rolling := v.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil {
+			partition = *rolling.Partition
+		}
+		switch {
+		case desired == 0 && v.Status.Replicas == 0:
+			return "Stopped"
+		case ready < desired, updated < desired-partition:
+			return "Updating"
+		case partition == 0 && v.Status.UpdateRevision != v.Status.CurrentRevision:
+			return "Updating"
+		}
 	case *appsv1.DaemonSet:
-		image = getDaemonSetImage(v)
+		if v.Generation > v.Status.ObservedGeneration {
+			return "Pending"
+		}
+		desired, _, updated, _ := replicas(v)
+		if updated < desired || v.Status.NumberAvailable < desired {
+			return "Updating"
+		}
+	default:
+		return ""
 	}
+	return "Complete"
+}
 
-	return image
+// podTemplate returns the selector and pod template of a workload
+func podTemplate(data interface{}) (*metav1.LabelSelector, *v1.PodTemplateSpec) {
+	switch v := data.(type) {
+	case *appsv1.Deployment:
+		return v.Spec.Selector, &v.Spec.Template
+	case *appsv1.StatefulSet:
+		return v.Spec.Selector, &v.Spec.Template
+	case *appsv1.DaemonSet:
+		return v.Spec.Selector, &v.Spec.Template
+	}
+	return nil, nil
+}
+
+// SelectsPod reports whether the pod belongs to the workload
+func SelectsPod(workload runtime.Object, pod *v1.Pod) bool {
+	selector, _ := podTemplate(workload)
+	obj, err := meta.Accessor(workload)
+	if selector == nil || err != nil || obj.GetNamespace() != pod.Namespace {
+		return false
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return sel.Matches(labels.Set(pod.Labels))
+}
+
+func formatRestarts(related *Related, obj runtime.Object) string {
+	if related == nil {
+		return ""
+	}
+	restarts := int32(0)
+	for _, pod := range related.Pods {
+		if !SelectsPod(obj, pod) {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+	}
+	return strconv.Itoa(int(restarts))
+}
+
+func formatPorts(data interface{}) string {
+	_, template := podTemplate(data)
+	if template == nil {
+		return ""
+	}
+	var ports []string
+	for _, container := range template.Spec.Containers {
+		for _, port := range container.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, strings.ToLower(string(port.Protocol))))
+		}
+	}
+	return strings.Join(ports, ",")
+}
+
+// formatEndpoints lists the hostnames of the ingresses and the addresses of the load balancers of the services of a
+// workload
+func formatEndpoints(related *Related, obj runtime.Object) string {
+	_, template := podTemplate(obj)
+	workload, err := meta.Accessor(obj)
+	if related == nil || template == nil || err != nil {
+		return ""
+	}
+
+	services := map[string]bool{}
+	var endpoints []string
+	for _, svc := range related.Services {
+		if len(svc.Spec.Selector) == 0 || svc.Namespace != workload.GetNamespace() ||
+			!labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(template.Labels)) {
+			continue
+		}
+		services[svc.Namespace+"/"+svc.Name] = true
+		for _, lb := range svc.Status.LoadBalancer.Ingress {
+			address := lb.IP
+			if address == "" {
+				address = lb.Hostname
+			}
+			for _, port := range svc.Spec.Ports {
+				endpoints = append(endpoints, fmt.Sprintf("%s:%d", address, port.Port))
+			}
+		}
+	}
+
+	for _, ingress := range related.Ingresses {
+		scheme := "http"
+		if len(ingress.Spec.TLS) > 0 {
+			scheme = "https"
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil || rule.Host == "" {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil && services[ingress.Namespace+"/"+path.Backend.Service.Name] {
+					endpoints = append(endpoints, fmt.Sprintf("%s://%s%s", scheme, rule.Host, path.Path))
+				}
+			}
+		}
+	}
+
+	sort.Strings(endpoints)
+	return strings.Join(endpoints, ",")
+}
+
+func FormatImage(data interface{}) string {
+	_, template := podTemplate(data)
+	if template == nil || len(template.Spec.Containers) == 0 {
+		return ""
+	}
+	return template.Spec.Containers[0].Image
 }
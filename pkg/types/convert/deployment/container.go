@@ -3,6 +3,7 @@ package deployment
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/docker/distribution/reference"
@@ -16,7 +17,7 @@ import (
 )
 
 var (
-	fieldRefs = map[string]string{
+	FieldRefs = map[string]string{
 		"self/name":           "metadata.name",
 		"self/namespace":      "metadata.namespace",
 		"self/labels":         "metadata.labels",
@@ -27,7 +28,7 @@ var (
 		"self/nodeip":         "status.hostIP",
 		"self/ip":             "status.podIP",
 	}
-	resourceRefs = map[string]string{
+	ResourceRefs = map[string]string{
 		"limits/cpu":                 "limits.cpu",
 		"limits/memory":              "limits.memory",
 		"limits/ephemeral-storage":   "limits.ephemeral-storage",
@@ -38,13 +39,15 @@ var (
 	prefix = regexp.MustCompile("^v?[0-9]")
 )
 
-func containers(service types.Service, init bool) (result []v1.Container) {
+func containers(service types.Service, init bool, defaults *types.DefaultResources) (result []v1.Container) {
 	for _, container := range utils.ToNamedContainers(service) {
 		if init != container.Init {
 			continue
 		}
 
 		c := toContainer(container.Name, &container.Container)
+		c.Resources = resources(&container.Container, service.Spec.QOS, defaults)
+		c.SecurityContext = securityContext(&container.Container, service.Spec.Security)
 		c.Name = container.Name
 		result = append(result, c)
 	}
@@ -58,62 +61,73 @@ func toContainer(containerName string, c *types.Container) v1.Container {
 		Command:         c.Command,
 		Args:            c.Args,
 		WorkingDir:      c.WorkingDir,
-		LivenessProbe:   convertProbePorts(c.LivenessProbe),
-		ReadinessProbe:  convertProbePorts(c.ReadinessProbe),
+		LivenessProbe:   convertProbePorts(probe(c.LivenessProbe, c.Healthcheck)),
+		ReadinessProbe:  convertProbePorts(probe(c.ReadinessProbe, c.Healthcheck)),
+		StartupProbe:    convertProbePorts(probe(c.StartupProbe, nil)),
+		Lifecycle:       lifecycle(containerName, c),
 		ImagePullPolicy: c.ImagePullPolicy,
 		Stdin:           c.Stdin,
 		StdinOnce:       c.StdinOnce,
 		TTY:             c.TTY,
-		Resources:       resources(c),
 		Ports:           ports(c),
 		Env:             envs(containerName, c),
 		VolumeMounts:    mounts(containerName, c),
-		SecurityContext: securityContext(c),
 	}
 
-	if c.ImagePullPolicy == "" && c.Image != "" {
-		if named, err := reference.ParseNormalizedNamed(c.Image); err == nil {
-			if t, ok := named.(reference.Tagged); ok {
-				if !prefix.MatchString(t.Tag()) {
-					con.ImagePullPolicy = v1.PullAlways
-				}
-			} else {
-				con.ImagePullPolicy = v1.PullAlways
-			}
-		}
+	if c.ImagePullPolicy == "" {
+		con.ImagePullPolicy = DefaultPullPolicy(c.Image)
+	}
+
+	return con
+}
+
+// DefaultPullPolicy is Always for images without a version tag, so that the latest image is used
+func DefaultPullPolicy(image string) v1.PullPolicy {
+	if image == "" {
+		return v1.PullIfNotPresent
 	}
 
-	if c.Image == "" {
-		con.ImagePullPolicy = v1.PullIfNotPresent
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
 	}
 
-	return con
+	if t, ok := named.(reference.Tagged); ok && prefix.MatchString(t.Tag()) {
+		return ""
+	}
+	return v1.PullAlways
 }
 
-// norman only takes string for intstr types, but K8s only takes int, so always do conversion here
+// probe returns a copy of the probe, falling back to the healthcheck
+func probe(probe, healthcheck *v1.Probe) *v1.Probe {
+	if probe == nil {
+		probe = healthcheck
+	}
+	return probe.DeepCopy()
+}
+
+// norman only takes string for intstr types, but K8s only takes int, so always do conversion here.
+// Named ports are left as strings.
 func convertProbePorts(probe *v1.Probe) *v1.Probe {
 	if probe == nil {
 		return probe
 	}
-	if probe.HTTPGet != nil && probe.HTTPGet.Port.StrVal != "" {
-		probe.HTTPGet.Port = intstr.FromInt(probe.HTTPGet.Port.IntValue())
+	if probe.HTTPGet != nil {
+		probe.HTTPGet.Port = convertProbePort(probe.HTTPGet.Port)
 	}
-	if probe.TCPSocket != nil && probe.TCPSocket.Port.StrVal != "" {
-		probe.TCPSocket.Port = intstr.FromInt(probe.TCPSocket.Port.IntValue())
+	if probe.TCPSocket != nil {
+		probe.TCPSocket.Port = convertProbePort(probe.TCPSocket.Port)
 	}
 	return probe
 }
 
-func securityContext(c *types.Container) *v1.SecurityContext {
-	if c.ContainerSecurityContext == nil {
-		return nil
-	}
-	return &v1.SecurityContext{
-		RunAsUser:              c.RunAsUser,
-		RunAsGroup:             c.RunAsGroup,
-		ReadOnlyRootFilesystem: c.ReadOnlyRootFilesystem,
-		Privileged:             c.Privileged,
+func convertProbePort(port intstr.IntOrString) intstr.IntOrString {
+	if port.Type == intstr.String {
+		if n, err := strconv.Atoi(port.StrVal); err == nil {
+			return intstr.FromInt(n)
+		}
 	}
+	return port
 }
 
 func mounts(containerName string, c *types.Container) (result []v1.VolumeMount) {
@@ -156,6 +170,8 @@ func normalizeVolumes(containerName string, volumes []types.Volume) (result []ty
 
 		if volume.HostPath != "" {
 			volume.Name = "host-" + name.Hex(volume.HostPath, 8)
+		} else if volume.NFSServer != "" {
+			volume.Name = "nfs-" + name.Hex(volume.NFSServer+volume.NFSPath, 8)
 		}
 		result = append(result, volume)
 	}
@@ -167,6 +183,7 @@ func volumeMount(containerName string, volumes []types.Volume) (result []v1.Volu
 		mount := v1.VolumeMount{
 			Name:      fmt.Sprintf("vol-%s", volume.Name),
 			MountPath: volume.Path,
+			ReadOnly:  volume.ReadOnly,
 		}
 		result = append(result, mount)
 	}
@@ -222,7 +239,7 @@ func envs(containerName string, c *types.Container) (result []v1.EnvVar) {
 
 		key := strings.ToLower(value[2 : len(value)-1])
 
-		if fieldRefValue, ok := fieldRefs[key]; ok {
+		if fieldRefValue, ok := FieldRefs[key]; ok {
 			result = append(result, v1.EnvVar{
 				Name: name,
 				ValueFrom: &v1.EnvVarSource{
@@ -234,7 +251,7 @@ func envs(containerName string, c *types.Container) (result []v1.EnvVar) {
 			continue
 		}
 
-		if resourceRefValue, ok := resourceRefs[key]; ok {
+		if resourceRefValue, ok := ResourceRefs[key]; ok {
 			result = append(result, v1.EnvVar{
 				Name: name,
 				ValueFrom: &v1.EnvVarSource{
@@ -273,31 +290,83 @@ func ports(c *types.Container) (result []v1.ContainerPort) {
 	return
 }
 
-func resources(c *types.Container) (result v1.ResourceRequirements) {
-	if c.CPUMillis == nil || *c.CPUMillis == 0 {
-		result.Requests = v1.ResourceList{
-			v1.ResourceCPU: defaultCPU,
+func resources(c *types.Container, qos v1.PodQOSClass, defaults *types.DefaultResources) (result v1.ResourceRequirements) {
+	requests := v1.ResourceList{}
+	limits := v1.ResourceList{}
+
+	// the QoS class only depends on cpu and memory, BestEffort keeps the other resources
+	if qos != v1.PodQOSBestEffort {
+		setQuantity(requests, v1.ResourceCPU, c.CPUMillis, resource.DecimalSI, true)
+		setQuantity(limits, v1.ResourceCPU, c.CPULimitMillis, resource.DecimalSI, true)
+		setQuantity(requests, v1.ResourceMemory, c.MemoryBytes, resource.BinarySI, false)
+		setQuantity(limits, v1.ResourceMemory, c.MemoryLimitBytes, resource.BinarySI, false)
+	}
+	setQuantity(requests, v1.ResourceEphemeralStorage, c.EphemeralStorageBytes, resource.BinarySI, false)
+	setQuantity(limits, v1.ResourceEphemeralStorage, c.EphemeralStorageLimitBytes, resource.BinarySI, false)
+
+	for name, q := range c.ExtendedResources {
+		requests[name] = q
+		limits[name] = q
+	}
+
+	// a limit without a request already implies the request, so defaults only fill in resources with neither
+	defaultCPU, defaultMemory := defaultRequests(defaults)
+	for name, q := range map[v1.ResourceName]*resource.Quantity{
+		v1.ResourceCPU:    defaultCPU,
+		v1.ResourceMemory: defaultMemory,
+	} {
+		_, hasRequest := requests[name]
+		_, hasLimit := limits[name]
+		if q != nil && !hasRequest && !hasLimit && qos != v1.PodQOSBestEffort {
+			requests[name] = *q
 		}
-	} else {
-		q, _ := resource.ParseQuantity(fmt.Sprintf("%dm", *c.CPUMillis))
-		result.Requests = v1.ResourceList{
-			v1.ResourceCPU: q,
+	}
+
+	if qos == v1.PodQOSGuaranteed {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			if limit, ok := limits[name]; ok {
+				requests[name] = limit
+			} else if request, ok := requests[name]; ok {
+				limits[name] = request
+			}
 		}
+	}
 
+	if len(requests) > 0 {
+		result.Requests = requests
+	}
+	if len(limits) > 0 {
+		result.Limits = limits
 	}
 
-	if c.MemoryBytes == nil || *c.MemoryBytes == 0 {
-		if result.Requests == nil {
-			result.Requests = v1.ResourceList{}
-		}
-		result.Requests[v1.ResourceMemory] = defaultMemory
+	return
+}
+
+func setQuantity(list v1.ResourceList, name v1.ResourceName, value *int64, format resource.Format, milli bool) {
+	if value == nil || *value == 0 {
+		return
+	}
+	if milli {
+		list[name] = *resource.NewMilliQuantity(*value, format)
 	} else {
-		if result.Requests == nil {
-			result.Requests = v1.ResourceList{}
-		}
-		q := resource.NewQuantity(*c.MemoryBytes, resource.BinarySI)
-		result.Requests[v1.ResourceMemory] = *q
+		list[name] = *resource.NewQuantity(*value, format)
 	}
+}
 
+func defaultRequests(defaults *types.DefaultResources) (cpu *resource.Quantity, memory *resource.Quantity) {
+	if defaults == nil {
+		return &DefaultCPU, &DefaultMemory
+	}
+	if defaults.Disabled {
+		return nil, nil
+	}
+
+	cpu, memory = &DefaultCPU, &DefaultMemory
+	if defaults.CPUMillis != nil {
+		cpu = resource.NewMilliQuantity(*defaults.CPUMillis, resource.DecimalSI)
+	}
+	if defaults.MemoryBytes != nil {
+		memory = resource.NewQuantity(*defaults.MemoryBytes, resource.BinarySI)
+	}
 	return
 }
@@ -0,0 +1,24 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestBestEffortKeepsExtendedResources(t *testing.T) {
+	cpu := int64(500)
+	c := &types.Container{
+		CPUMillis: &cpu,
+		ExtendedResources: v1.ResourceList{
+			"nvidia.com/gpu": resource.MustParse("1"),
+		},
+	}
+
+	result := resources(c, v1.PodQOSBestEffort, nil)
+	assert.Equal(t, v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}, result.Requests)
+	assert.Equal(t, v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}, result.Limits)
+}
@@ -18,14 +18,19 @@ type Plugin struct{}
 
 func (p Plugin) Convert(rf *dollyfile.DollyFile) (ret []runtime.Object) {
 	for _, svc := range rf.Services {
-		podTemplateSpec := populatePodTemplate(svc)
+		if utils.IsExternal(svc) {
+			continue
+		}
+
+		podTemplateSpec := PopulatePodTemplate(svc, rf.DefaultResources)
 
 		cp := newControllerParams(svc, podTemplateSpec)
-		if svc.Spec.Global {
+		switch utils.WorkloadKind(svc) {
+		case types.WorkloadDaemonSet:
 			ret = append(ret, daemonset(svc, cp))
-		} else if len(cp.VolumeTemplates) > 0 {
+		case types.WorkloadStatefulSet:
 			ret = append(ret, statefulset(svc, cp))
-		} else {
+		default:
 			ret = append(ret, deployment(svc, cp))
 		}
 	}
@@ -41,19 +46,32 @@ func statefulset(service types.Service, cp *controllerParams) runtime.Object {
 			Annotations: cp.Annotations,
 		},
 		Spec: appsv1.StatefulSetSpec{
-			Replicas: nil,
+			Replicas: cp.Scale.Scale,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: cp.SelectorLabels,
 			},
 			Template:             cp.PodTemplateSpec,
 			VolumeClaimTemplates: volumeClaimTemplates(cp.VolumeTemplates),
-			ServiceName:          service.Name,
+			ServiceName:          utils.GoverningServiceName(service),
 			PodManagementPolicy:  appsv1.ParallelPodManagement,
 			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
 				Type: appsv1.RollingUpdateStatefulSetStrategyType,
 			},
 		},
 	}
+
+	if service.Spec.PodManagement != "" {
+		ss.Spec.PodManagementPolicy = service.Spec.PodManagement
+	}
+
+	if service.Spec.UpdateStrategy == types.UpdateStrategyOnDelete {
+		ss.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	} else if service.Spec.Partition != nil {
+		ss.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: service.Spec.Partition,
+		}
+	}
+
 	return ss
 }
 
@@ -81,6 +99,12 @@ func deployment(service types.Service, cp *controllerParams) runtime.Object {
 		},
 	}
 
+	if service.Spec.UpdateStrategy == types.UpdateStrategyRecreate {
+		dep.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		}
+	}
+
 	return dep
 }
 
@@ -105,6 +129,13 @@ func daemonset(service types.Service, cp *controllerParams) runtime.Object {
 			},
 		},
 	}
+
+	if service.Spec.UpdateStrategy == types.UpdateStrategyOnDelete {
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.OnDeleteDaemonSetStrategyType,
+		}
+	}
+
 	return ds
 }
 
@@ -167,7 +198,8 @@ type controllerParams struct {
 	PodTemplateSpec v1.PodTemplateSpec
 }
 
-func populatePodTemplate(service types.Service) v1.PodTemplateSpec {
+// PopulatePodTemplate returns the pod template of the workload of a service, without its selector labels
+func PopulatePodTemplate(service types.Service, defaults *types.DefaultResources) v1.PodTemplateSpec {
 	pts := v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      service.Labels,
@@ -175,31 +207,43 @@ func populatePodTemplate(service types.Service) v1.PodTemplateSpec {
 		},
 	}
 
-	podSpec := podSpec(service)
+	if appArmor := appArmorAnnotations(service); len(appArmor) > 0 {
+		pts.Annotations = labels.Merge(service.Annotations, appArmor)
+	}
+
+	podSpec := podSpec(service, defaults)
 
 	pts.Spec = podSpec
 	return pts
 }
 
 var (
-	f             = false
-	t             = true
-	defaultCPU    = resource.MustParse("50m")
-	defaultMemory = resource.MustParse("64Mi")
+	f = false
+	t = true
+	// DefaultCPU and DefaultMemory are the requests of containers without resources, unless the Dollyfile overrides them
+	DefaultCPU    = resource.MustParse("50m")
+	DefaultMemory = resource.MustParse("64Mi")
 )
 
-func podSpec(service types.Service) v1.PodSpec {
+func podSpec(service types.Service, defaults *types.DefaultResources) v1.PodSpec {
 	podSpec := v1.PodSpec{
 		DNSConfig:          podDNS(service),
 		HostAliases:        service.Spec.HostAliases,
 		Hostname:           service.Spec.Hostname,
 		HostNetwork:        service.Spec.HostNetwork,
 		EnableServiceLinks: &f,
-		Containers:         containers(service, false),
-		InitContainers:     containers(service, true),
+		Containers:         containers(service, false, defaults),
+		InitContainers:     containers(service, true, defaults),
 		Volumes:            volumes(service),
-		Affinity:           service.Spec.Affinity,
+		Affinity:           affinity(service),
 		ImagePullSecrets:   pullSecrets(service.Spec.ImagePullSecrets),
+		SecurityContext:    podSecurityContext(service),
+		NodeSelector:       service.Spec.NodeSelector,
+		Tolerations:        service.Spec.Tolerations,
+		PriorityClassName:  service.Spec.PriorityClassName,
+
+		TopologySpreadConstraints:     topologySpreadConstraints(service),
+		TerminationGracePeriodSeconds: terminationGracePeriodSeconds(service),
 	}
 
 	serviceAccountName := utils.ServiceAccountName(service)
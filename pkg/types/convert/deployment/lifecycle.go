@@ -0,0 +1,78 @@
+package deployment
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/utils"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+const defaultTerminationGracePeriod = 30 * time.Second
+
+func lifecycle(containerName string, c *types.Container) *v1.Lifecycle {
+	preStop := c.PreStop
+	if drain := drainSeconds(c); drain > 0 {
+		if preStop == nil {
+			preStop = &v1.Handler{
+				Exec: &v1.ExecAction{
+					Command: []string{"sleep", strconv.FormatInt(drain, 10)},
+				},
+			}
+		} else {
+			logrus.Warnf("container %s sets both preStop and drain, ignoring drain", containerName)
+		}
+	}
+
+	if preStop == nil && c.PostStart == nil {
+		return nil
+	}
+
+	return &v1.Lifecycle{
+		PreStop:   convertHandlerPorts(preStop),
+		PostStart: convertHandlerPorts(c.PostStart),
+	}
+}
+
+func convertHandlerPorts(handler *v1.Handler) *v1.Handler {
+	if handler == nil {
+		return nil
+	}
+	handler = handler.DeepCopy()
+	if handler.HTTPGet != nil {
+		handler.HTTPGet.Port = convertProbePort(handler.HTTPGet.Port)
+	}
+	return handler
+}
+
+func drainSeconds(c *types.Container) int64 {
+	if c.Drain == nil || c.Drain.Duration <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(c.Drain.Seconds()))
+}
+
+// terminationGracePeriodSeconds defaults to the longest drain plus the default grace period, so the drain does not eat up
+// the time the container has to shut down
+func terminationGracePeriodSeconds(service types.Service) *int64 {
+	if service.Spec.TerminationGracePeriod != nil {
+		seconds := int64(math.Ceil(service.Spec.TerminationGracePeriod.Seconds()))
+		return &seconds
+	}
+
+	var drain int64
+	for _, container := range utils.ToNamedContainers(service) {
+		if seconds := drainSeconds(&container.Container); seconds > drain && container.PreStop == nil {
+			drain = seconds
+		}
+	}
+	if drain == 0 {
+		return nil
+	}
+
+	seconds := drain + int64(defaultTerminationGracePeriod/time.Second)
+	return &seconds
+}
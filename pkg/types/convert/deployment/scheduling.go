@@ -0,0 +1,74 @@
+package deployment
+
+import (
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/convert/labels"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultAntiAffinityWeight = 100
+
+func topologySpreadConstraints(service types.Service) (result []v1.TopologySpreadConstraint) {
+	for _, rule := range service.Spec.Spread {
+		constraint := v1.TopologySpreadConstraint{
+			MaxSkew:           rule.MaxSkew,
+			TopologyKey:       stringers.TopologyKey(rule.Topology),
+			WhenUnsatisfiable: v1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: labels.SelectorLabels(service),
+			},
+		}
+		if constraint.MaxSkew <= 0 {
+			constraint.MaxSkew = 1
+		}
+		if rule.Required {
+			constraint.WhenUnsatisfiable = v1.DoNotSchedule
+		}
+		result = append(result, constraint)
+	}
+	return
+}
+
+// affinity adds the antiAffinity shorthand to the raw affinity of the service
+func affinity(service types.Service) *v1.Affinity {
+	if len(service.Spec.AntiAffinity) == 0 {
+		return service.Spec.Affinity
+	}
+
+	result := &v1.Affinity{}
+	if service.Spec.Affinity != nil {
+		result = service.Spec.Affinity.DeepCopy()
+	}
+	if result.PodAntiAffinity == nil {
+		result.PodAntiAffinity = &v1.PodAntiAffinity{}
+	}
+
+	for _, rule := range service.Spec.AntiAffinity {
+		term := v1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: labels.SelectorLabels(service),
+			},
+			TopologyKey: stringers.TopologyKey(rule.Topology),
+		}
+
+		if rule.Required {
+			result.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution =
+				append(result.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+			continue
+		}
+
+		weight := rule.Weight
+		if weight <= 0 {
+			weight = defaultAntiAffinityWeight
+		}
+		result.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution =
+			append(result.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, v1.WeightedPodAffinityTerm{
+				Weight:          weight,
+				PodAffinityTerm: term,
+			})
+	}
+
+	return result
+}
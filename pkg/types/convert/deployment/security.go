@@ -0,0 +1,116 @@
+package deployment
+
+import (
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+)
+
+func securityContext(c *types.Container, preset types.SecurityPreset) *v1.SecurityContext {
+	if c.ContainerSecurityContext == nil && preset == "" {
+		return nil
+	}
+
+	csc := c.ContainerSecurityContext
+	if csc == nil {
+		csc = &types.ContainerSecurityContext{}
+	}
+
+	sc := &v1.SecurityContext{
+		RunAsUser:                csc.RunAsUser,
+		RunAsGroup:               csc.RunAsGroup,
+		RunAsNonRoot:             csc.RunAsNonRoot,
+		ReadOnlyRootFilesystem:   csc.ReadOnlyRootFilesystem,
+		Privileged:               csc.Privileged,
+		AllowPrivilegeEscalation: csc.AllowPrivilegeEscalation,
+	}
+
+	if len(csc.CapAdd) > 0 || len(csc.CapDrop) > 0 {
+		sc.Capabilities = &v1.Capabilities{
+			Add:  csc.CapAdd,
+			Drop: csc.CapDrop,
+		}
+	}
+
+	// the profile is checked when the Dollyfile is validated
+	sc.SeccompProfile, _ = stringers.ParseSeccompProfile(csc.SeccompProfile)
+
+	if preset == types.SecurityPresetRestricted {
+		restrict(sc)
+	}
+
+	return sc
+}
+
+// restrict forces the container settings required by the restricted Pod Security Standard
+func restrict(sc *v1.SecurityContext) {
+	sc.Privileged = &f
+	sc.AllowPrivilegeEscalation = &f
+	sc.RunAsNonRoot = &t
+
+	var add []v1.Capability
+	if sc.Capabilities != nil {
+		for _, capability := range sc.Capabilities.Add {
+			if types.RestrictedCapabilities[capability] {
+				add = append(add, capability)
+			}
+		}
+	}
+	sc.Capabilities = &v1.Capabilities{
+		Add:  add,
+		Drop: []v1.Capability{"ALL"},
+	}
+
+	// an unset profile falls back to the pod level runtime/default profile
+	if sc.SeccompProfile != nil && sc.SeccompProfile.Type == v1.SeccompProfileTypeUnconfined {
+		sc.SeccompProfile = nil
+	}
+}
+
+func podSecurityContext(service types.Service) *v1.PodSecurityContext {
+	spec := service.Spec
+	if spec.FSGroup == nil && len(spec.SupplementalGroups) == 0 && len(spec.Sysctls) == 0 && spec.Security == "" {
+		return nil
+	}
+
+	psc := &v1.PodSecurityContext{
+		FSGroup:            spec.FSGroup,
+		SupplementalGroups: spec.SupplementalGroups,
+		Sysctls:            spec.Sysctls,
+	}
+
+	if spec.Security == types.SecurityPresetRestricted {
+		psc.RunAsNonRoot = &t
+		psc.SeccompProfile = &v1.SeccompProfile{
+			Type: v1.SeccompProfileTypeRuntimeDefault,
+		}
+	}
+
+	return psc
+}
+
+// appArmorAnnotations returns the pod annotations setting the AppArmor profile of each container
+func appArmorAnnotations(service types.Service) map[string]string {
+	result := map[string]string{}
+	for _, container := range utils.ToNamedContainers(service) {
+		if container.ContainerSecurityContext == nil || container.AppArmorProfile == "" {
+			continue
+		}
+
+		// the profile is checked when the Dollyfile is validated
+		profile, err := stringers.ParseAppArmorProfile(container.AppArmorProfile)
+		if err != nil {
+			continue
+		}
+		if profile == stringers.AppArmorUnconfined && service.Spec.Security == types.SecurityPresetRestricted {
+			continue
+		}
+		result[appArmorAnnotationPrefix+container.Name] = profile
+	}
+	return result
+}
@@ -2,9 +2,12 @@ package deployment
 
 import (
     "fmt"
+    "github.com/rancher/dolly/pkg/dollyfile/stringers"
     "github.com/rancher/dolly/pkg/types"
     "github.com/rancher/dolly/pkg/types/utils"
+    "github.com/sirupsen/logrus"
     v1 "k8s.io/api/core/v1"
+    "k8s.io/apimachinery/pkg/api/resource"
     "sort"
 )
 
@@ -64,6 +67,7 @@ type sortedVolumes struct {
     EmptyDir map[string]types.Volume
     HostPath map[string]types.Volume
     PVC      map[string]types.Volume
+    Inline   map[string]types.Volume
 }
 
 func sortVolumes(containers []types.NamedContainer, volumeTemplates map[string]types.VolumeTemplate) (result sortedVolumes) {
@@ -71,6 +75,7 @@ func sortVolumes(containers []types.NamedContainer, volumeTemplates map[string]t
     result.EmptyDir = map[string]types.Volume{}
     result.HostPath = map[string]types.Volume{}
     result.PVC = map[string]types.Volume{}
+    result.Inline = map[string]types.Volume{}
 
     for _, container := range containers {
         for _, volume := range normalizeVolumes(container.Name, container.Volumes) {
@@ -80,6 +85,8 @@ func sortVolumes(containers []types.NamedContainer, volumeTemplates map[string]t
             result.All[volume.Name] = true
             if volume.HostPath != "" {
                 result.HostPath[volume.Name] = volume
+            } else if isInline(volume) {
+                result.Inline[volume.Name] = volume
             } else if _, ok := volumeTemplates[volume.Name]; !ok && volume.Persistent {
                 result.PVC[volume.Name] = volume
             } else if !volume.Persistent {
@@ -97,16 +104,114 @@ func emptyDirVolumes(emptyDir map[string]types.Volume) (result []v1.Volume) {
         names = append(names, name)
     }
     for _, name := range removeDuplicateAndSort(names) {
+        volume := emptyDir[name]
+        source := &v1.EmptyDirVolumeSource{}
+        if volume.Tmpfs {
+            source.Medium = v1.StorageMediumMemory
+        }
+        if volume.Size != "" {
+            if size, err := resource.ParseQuantity(volume.Size); err == nil {
+                source.SizeLimit = &size
+            } else {
+                logrus.Warnf("Ignoring invalid size %s of volume %s: %v", volume.Size, name, err)
+            }
+        }
         result = append(result, v1.Volume{
             Name: fmt.Sprintf("vol-%s", name),
             VolumeSource: v1.VolumeSource{
-                EmptyDir: &v1.EmptyDirVolumeSource{},
+                EmptyDir: source,
             },
         })
     }
     return
 }
 
+// isInline returns whether the volume is an NFS, projected, ephemeral or CSI volume defined in the pod spec
+func isInline(volume types.Volume) bool {
+    return volume.NFSServer != "" || volume.Projected != nil || volume.Ephemeral || volume.Driver != ""
+}
+
+func inlineVolumes(inline map[string]types.Volume) (result []v1.Volume) {
+    var names []string
+    for name := range inline {
+        names = append(names, name)
+    }
+    for _, name := range removeDuplicateAndSort(names) {
+        result = append(result, v1.Volume{
+            Name:         fmt.Sprintf("vol-%s", name),
+            VolumeSource: inlineVolumeSource(inline[name]),
+        })
+    }
+    return
+}
+
+func inlineVolumeSource(volume types.Volume) (source v1.VolumeSource) {
+    switch {
+    case volume.NFSServer != "":
+        source.NFS = &v1.NFSVolumeSource{
+            Server:   volume.NFSServer,
+            Path:     volume.NFSPath,
+            ReadOnly: volume.ReadOnly,
+        }
+    case volume.Projected != nil:
+        source.Projected = projectedVolumeSource(volume.Projected)
+    case volume.Ephemeral:
+        source.Ephemeral = &v1.EphemeralVolumeSource{
+            VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{
+                Spec: utils.PersistentVolumeClaimSpec(volume),
+            },
+            ReadOnly: volume.ReadOnly,
+        }
+    case volume.Driver != "":
+        source.CSI = &v1.CSIVolumeSource{
+            Driver:           volume.Driver,
+            VolumeAttributes: volume.DriverAttributes,
+        }
+        if volume.ReadOnly {
+            source.CSI.ReadOnly = &t
+        }
+    }
+    return
+}
+
+func projectedVolumeSource(projected *types.ProjectedVolume) *v1.ProjectedVolumeSource {
+    source := &v1.ProjectedVolumeSource{}
+    for _, config := range projected.Configs {
+        source.Sources = append(source.Sources, v1.VolumeProjection{
+            ConfigMap: &v1.ConfigMapProjection{
+                LocalObjectReference: v1.LocalObjectReference{
+                    Name: config,
+                },
+            },
+        })
+    }
+    for _, secret := range projected.Secrets {
+        source.Sources = append(source.Sources, v1.VolumeProjection{
+            Secret: &v1.SecretProjection{
+                LocalObjectReference: v1.LocalObjectReference{
+                    Name: secret,
+                },
+            },
+        })
+    }
+    if len(projected.DownwardAPI) > 0 {
+        downward := &v1.DownwardAPIProjection{}
+        for _, field := range projected.DownwardAPI {
+            downward.Items = append(downward.Items, v1.DownwardAPIVolumeFile{
+                Path: field,
+                FieldRef: &v1.ObjectFieldSelector{
+                    FieldPath: stringers.DownwardAPIFields[field],
+                },
+            })
+        }
+        source.Sources = append(source.Sources, v1.VolumeProjection{
+            DownwardAPI: downward,
+        })
+    }
+    return source
+}
+
+
 func hostPathVolumes(hostPath map[string]types.Volume) (result []v1.Volume) {
     var names []string
     for name := range hostPath {
@@ -162,6 +267,7 @@ func diskVolumes(containers []types.NamedContainer, service types.Service) (resu
     result = append(result, emptyDirVolumes(sorted.EmptyDir)...)
     result = append(result, hostPathVolumes(sorted.HostPath)...)
     result = append(result, pvcVolumes(sorted.PVC)...)
+    result = append(result, inlineVolumes(sorted.Inline)...)
     return
 }
 
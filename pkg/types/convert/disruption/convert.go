@@ -0,0 +1,52 @@
+package disruption
+
+import (
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/types/convert/labels"
+	"github.com/rancher/dolly/pkg/types/utils"
+	"github.com/rancher/wrangler/pkg/schemes"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	// wrangler has no generated policy controllers to register the PodDisruptionBudget type when imported
+	schemes.Register(policyv1beta1.AddToScheme)
+}
+
+type Plugin struct{}
+
+func (p Plugin) Convert(rf *dollyfile.DollyFile) (ret []runtime.Object) {
+	for _, service := range rf.Services {
+		if utils.IsExternal(service) || service.Spec.Disruption == nil {
+			continue
+		}
+
+		budget := service.Spec.Disruption
+		if budget.MinAvailable == nil && budget.MaxUnavailable == nil {
+			continue
+		}
+
+		pdb := &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:    service.Labels,
+				Name:      service.Name,
+				Namespace: service.Namespace,
+			},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: labels.SelectorLabels(service),
+				},
+			},
+		}
+
+		// setting both is rejected when the Dollyfile is validated
+		pdb.Spec.MinAvailable = budget.MinAvailable
+		pdb.Spec.MaxUnavailable = budget.MaxUnavailable
+
+		ret = append(ret, pdb)
+	}
+
+	return ret
+}
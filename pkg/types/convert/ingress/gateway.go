@@ -0,0 +1,100 @@
+package ingress
+
+import (
+	"strconv"
+
+	"github.com/rancher/wrangler/pkg/kv"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// gatewayAPIVersion is the version of the Gateway API the HTTPRoutes are created with
+const gatewayAPIVersion = "gateway.networking.k8s.io/v1"
+
+// pathTypes are the types of the path matches of HTTPRoutes by the path type of Ingresses, an implementation specific
+// path being a regex for routers
+var pathTypes = map[v1.PathType]string{
+	v1.PathTypeExact:                  "Exact",
+	v1.PathTypePrefix:                 "PathPrefix",
+	v1.PathTypeImplementationSpecific: "RegularExpression",
+}
+
+// httpRoute converts an Ingress to an HTTPRoute attached to a gateway given as [namespace/]name. The rules of the
+// Ingresses of dolly route the same paths for every host, so the hosts become the hostnames of the HTTPRoute. TLS is
+// set up on the listeners of the gateway, not on routes.
+func httpRoute(ingress *v1.Ingress, gateway string) *unstructured.Unstructured {
+	var (
+		hostnames []interface{}
+		rules     []interface{}
+		seenHosts = map[string]bool{}
+		seenRules = map[string]bool{}
+	)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" && !seenHosts[rule.Host] {
+			seenHosts[rule.Host] = true
+			hostnames = append(hostnames, rule.Host)
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backend := path.Backend.Service
+			if backend == nil {
+				continue
+			}
+			key := path.Path + ">" + backend.Name + ":" + strconv.Itoa(int(backend.Port.Number))
+			if seenRules[key] {
+				continue
+			}
+			seenRules[key] = true
+
+			routeRule := map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": backend.Name,
+						"port": int64(backend.Port.Number),
+					},
+				},
+			}
+			if path.Path != "" && path.PathType != nil {
+				routeRule["matches"] = []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  pathTypes[*path.PathType],
+							"value": path.Path,
+						},
+					},
+				}
+			}
+			rules = append(rules, routeRule)
+		}
+	}
+
+	spec := map[string]interface{}{
+		"rules": rules,
+	}
+	if len(hostnames) > 0 {
+		spec["hostnames"] = hostnames
+	}
+	if gateway != "" {
+		namespace, name := kv.RSplit(gateway, "/")
+		parent := map[string]interface{}{
+			"name": name,
+		}
+		if namespace != "" {
+			parent["namespace"] = namespace
+		}
+		spec["parentRefs"] = []interface{}{parent}
+	}
+
+	route := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	route.SetAPIVersion(gatewayAPIVersion)
+	route.SetKind("HTTPRoute")
+	route.SetName(ingress.Name)
+	route.SetNamespace(ingress.Namespace)
+	return route
+}
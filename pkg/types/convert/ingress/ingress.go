@@ -2,6 +2,7 @@ package ingress
 
 import (
 	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/types"
 	"github.com/rancher/dolly/pkg/types/utils"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,8 +11,51 @@ import (
 
 type Plugin struct{}
 
+// Convert creates an Ingress for the hostnames of each service and for each router, set up for the ingress controller of
+// the dollyfile. The gateway provider gets HTTPRoutes instead.
 func (p Plugin) Convert(rf *dollyfile.DollyFile) (ret []runtime.Object) {
+	provider, _ := ProviderOf(rf.Ingress)
+	for _, ingress := range ingresses(rf) {
+		if provider.Name == types.IngressProviderGateway {
+			ret = append(ret, httpRoute(ingress, rf.Ingress.Gateway))
+			continue
+		}
+		setProvider(ingress, provider, rf.Ingress)
+		ret = append(ret, ingress)
+	}
+	return ret
+}
+
+// setProvider sets the ingress class and annotations of the provider on an Ingress, the ones of the configuration taking
+// precedence
+func setProvider(ingress *v1.Ingress, provider Provider, config *types.Ingress) {
+	class := provider.Class
+	annotations := map[string]string{}
+	for k, v := range provider.Annotations {
+		annotations[k] = v
+	}
+	if config != nil {
+		if config.Class != "" {
+			class = config.Class
+		}
+		for k, v := range config.Annotations {
+			annotations[k] = v
+		}
+	}
+	if class != "" {
+		ingress.Spec.IngressClassName = &class
+	}
+	if len(annotations) > 0 {
+		ingress.Annotations = annotations
+	}
+}
+
+func ingresses(rf *dollyfile.DollyFile) (ret []*v1.Ingress) {
 	for _, service := range rf.Services {
+		if utils.IsExternal(service) {
+			continue
+		}
+
 		app := service.Spec.App
 		if app == "" {
 			app = service.Name
@@ -25,7 +69,7 @@ func (p Plugin) Convert(rf *dollyfile.DollyFile) (ret []runtime.Object) {
 			}
 		}
 		if servicePort == 0 {
-			return nil
+			continue
 		}
 
 		hostnames := service.Spec.Hostnames
@@ -0,0 +1,104 @@
+package ingress
+
+import (
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/wrangler/pkg/kv"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// gatewayNameLabel is set by most Gateway API implementations on the pods of a Gateway
+	gatewayNameLabel = "gateway.networking.k8s.io/gateway-name"
+	// envoyGatewayNameLabel is set by Envoy Gateway on the pods of a Gateway
+	envoyGatewayNameLabel = "gateway.envoyproxy.io/owning-gateway-name"
+)
+
+// Provider is an ingress controller, with what the Ingresses it serves need and how to find its pods
+type Provider struct {
+	Name types.IngressProvider
+	// Class is the ingress class of the controller
+	Class string
+	// Annotations set on the Ingresses served by the controller
+	Annotations map[string]string
+	// Selectors select the pods of the controller in any namespace, one per way of installing it
+	Selectors []metav1.LabelSelector
+	// Ports are the names of the HTTP port of the pods of the controller, the pods of a gateway serving the ports of its
+	// listeners
+	Ports []string
+}
+
+// Providers are the ingress controllers that serve Ingresses, in the order they are detected
+var Providers = []Provider{
+	{
+		Name:  types.IngressProviderTraefik,
+		Class: "traefik",
+		Selectors: []metav1.LabelSelector{
+			// k3s and the traefik v1 chart
+			{MatchLabels: map[string]string{"app": "traefik"}},
+			{MatchLabels: map[string]string{"app.kubernetes.io/name": "traefik"}},
+		},
+		Ports: []string{"web", "http"},
+	},
+	{
+		Name:  types.IngressProviderNginx,
+		Class: "nginx",
+		Annotations: map[string]string{
+			// the services are reached over http through the forwarded port, even when their Ingress has a TLS section
+			"nginx.ingress.kubernetes.io/ssl-redirect": "false",
+		},
+		Selectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"app.kubernetes.io/name": "ingress-nginx", "app.kubernetes.io/component": "controller"}},
+		},
+		Ports: []string{"http"},
+	},
+	{
+		Name:  types.IngressProviderContour,
+		Class: "contour",
+		Selectors: []metav1.LabelSelector{
+			// the envoy pods serve the traffic, contour only configures them
+			{MatchLabels: map[string]string{"app": "envoy"}},
+			{MatchLabels: map[string]string{"app.kubernetes.io/name": "contour", "app.kubernetes.io/component": "envoy"}},
+		},
+		Ports: []string{"http"},
+	},
+}
+
+// ProviderOf returns the provider of an ingress configuration, the HTTPRoutes of the gateway provider attaching to its
+// gateway. ok is false if the configuration doesn't name a provider.
+func ProviderOf(ingress *types.Ingress) (result Provider, ok bool) {
+	if ingress == nil {
+		return Provider{}, false
+	}
+	if ingress.Provider == types.IngressProviderGateway {
+		return GatewayProvider(ingress.Gateway), true
+	}
+	for _, provider := range Providers {
+		if provider.Name == ingress.Provider {
+			return provider, true
+		}
+	}
+	return Provider{Name: ingress.Provider}, ingress.Provider != ""
+}
+
+// GatewayProvider is the provider of the Gateway API, its pods being the ones of the gateway given as [namespace/]name
+func GatewayProvider(gateway string) Provider {
+	provider := Provider{
+		Name: types.IngressProviderGateway,
+	}
+	_, name := kv.RSplit(gateway, "/")
+	for _, label := range []string{gatewayNameLabel, envoyGatewayNameLabel} {
+		selector := metav1.LabelSelector{
+			MatchLabels: map[string]string{label: name},
+		}
+		// the pods of any gateway without a name
+		if name == "" {
+			selector = metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: label, Operator: metav1.LabelSelectorOpExists},
+				},
+			}
+		}
+		provider.Selectors = append(provider.Selectors, selector)
+	}
+	return provider
+}
@@ -0,0 +1,293 @@
+package networkpolicy
+
+import (
+	"net"
+	"sort"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/dolly/pkg/types/convert/ingress"
+	"github.com/rancher/dolly/pkg/types/convert/labels"
+	"github.com/rancher/dolly/pkg/types/utils"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultDenyName = "dolly-default-deny"
+	allowDNSName    = "dolly-allow-dns"
+)
+
+var (
+	dnsPort = intstr.FromInt(53)
+	udp     = v1.ProtocolUDP
+	tcp     = v1.ProtocolTCP
+)
+
+type Plugin struct{}
+
+func (p Plugin) Convert(rf *dollyfile.DollyFile) (ret []runtime.Object) {
+	strict := rf.Isolation == types.IsolationStrict
+	namespace, apps := stackApps(rf)
+	if len(apps) == 0 {
+		return nil
+	}
+
+	if strict {
+		ret = append(ret, defaultDeny(namespace, apps), allowDNS(namespace, apps))
+	}
+
+	sources := incomingConnections(rf)
+	for _, name := range serviceNames(rf) {
+		service := rf.Services[name]
+		if utils.IsExternal(service) {
+			continue
+		}
+		if !strict && len(sources[name]) == 0 && len(service.Spec.AllowFrom) == 0 {
+			continue
+		}
+
+		policy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:    service.Labels,
+				Name:      service.Name,
+				Namespace: service.Namespace,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: labels.SelectorLabels(service),
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				Ingress:     ingressRules(rf, service, sources[name]),
+			},
+		}
+
+		if strict {
+			policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+			policy.Spec.Egress = egressRules(rf, service)
+		}
+
+		ret = append(ret, policy)
+	}
+
+	return ret
+}
+
+func serviceNames(rf *dollyfile.DollyFile) (names []string) {
+	for name := range rf.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+func stackApps(rf *dollyfile.DollyFile) (namespace string, apps []string) {
+	seen := map[string]bool{}
+	for _, name := range serviceNames(rf) {
+		service := rf.Services[name]
+		if utils.IsExternal(service) {
+			continue
+		}
+		namespace = service.Namespace
+		app := labels.SelectorLabels(service)["app"]
+		if !seen[app] {
+			seen[app] = true
+			apps = append(apps, app)
+		}
+	}
+	return
+}
+
+func stackSelector(apps []string) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "app",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   apps,
+			},
+		},
+	}
+}
+
+func defaultDeny(namespace string, apps []string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaultDenyName,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: stackSelector(apps),
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+		},
+	}
+}
+
+func allowDNS(namespace string, apps []string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      allowDNSName,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: stackSelector(apps),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &udp, Port: &dnsPort},
+						{Protocol: &tcp, Port: &dnsPort},
+					},
+				},
+			},
+		},
+	}
+}
+
+// incomingConnections maps each service to the services that declare a connection to it
+func incomingConnections(rf *dollyfile.DollyFile) map[string][]string {
+	result := map[string][]string{}
+	for _, name := range serviceNames(rf) {
+		for _, target := range rf.Services[name].Spec.Connects {
+			result[target] = append(result[target], name)
+		}
+	}
+	return result
+}
+
+func ingressRules(rf *dollyfile.DollyFile, service types.Service, sources []string) (rules []networkingv1.NetworkPolicyIngressRule) {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, source := range append(sources, service.Spec.AllowFrom...) {
+		if _, cidr, err := net.ParseCIDR(source); err == nil {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{
+					CIDR: cidr.String(),
+				},
+			})
+			continue
+		}
+
+		from, ok := rf.Services[source]
+		if !ok || utils.IsExternal(from) {
+			continue
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: labels.SelectorLabels(from),
+			},
+		})
+	}
+
+	if len(peers) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From:  peers,
+			Ports: policyPorts(utils.ContainerPorts(service)),
+		})
+	}
+
+	var exposed []types.ContainerPort
+	for _, port := range utils.ContainerPorts(service) {
+		if port.IsExposed() && port.IsHTTP() {
+			exposed = append(exposed, port)
+		}
+	}
+	if controllers := ingressControllers(rf); len(exposed) > 0 && len(controllers) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From:  controllers,
+			Ports: policyPorts(exposed),
+		})
+	}
+
+	return
+}
+
+// ingressControllers selects the pods of the ingress controller of the dollyfile in any namespace, or the ones of every
+// known ingress controller if the dollyfile names none
+func ingressControllers(rf *dollyfile.DollyFile) (peers []networkingv1.NetworkPolicyPeer) {
+	providers := append(append([]ingress.Provider{}, ingress.Providers...), ingress.GatewayProvider(""))
+	if provider, ok := ingress.ProviderOf(rf.Ingress); ok {
+		providers = []ingress.Provider{provider}
+	}
+	for _, provider := range providers {
+		for i := range provider.Selectors {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector:       &provider.Selectors[i],
+			})
+		}
+	}
+	return
+}
+
+func egressRules(rf *dollyfile.DollyFile, service types.Service) (rules []networkingv1.NetworkPolicyEgressRule) {
+	for _, name := range service.Spec.Connects {
+		target, ok := rf.Services[name]
+		if !ok {
+			continue
+		}
+
+		if !utils.IsExternal(target) {
+			rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{
+					{
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: labels.SelectorLabels(target),
+						},
+					},
+				},
+				Ports: policyPorts(utils.ContainerPorts(target)),
+			})
+			continue
+		}
+
+		// an external DNS name can resolve to any address, so only the ports are restricted
+		rule := networkingv1.NetworkPolicyEgressRule{
+			Ports: policyPorts(externalPorts(target)),
+		}
+		if ip := net.ParseIP(target.Spec.External); ip != nil {
+			cidr := ip.String() + "/32"
+			if ip.To4() == nil {
+				cidr = ip.String() + "/128"
+			}
+			rule.To = []networkingv1.NetworkPolicyPeer{
+				{
+					IPBlock: &networkingv1.IPBlock{
+						CIDR: cidr,
+					},
+				},
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return
+}
+
+func externalPorts(service types.Service) (ports []types.ContainerPort) {
+	for _, port := range service.Spec.Ports {
+		port = stringers.NormalizeContainerPort(port)
+		if port.Port != 0 {
+			ports = append(ports, port)
+		}
+	}
+	return
+}
+
+// policyPorts returns the container ports the traffic is allowed to, no ports allows all ports
+func policyPorts(ports []types.ContainerPort) (result []networkingv1.NetworkPolicyPort) {
+	for _, port := range ports {
+		protocol := utils.Protocol(port.Protocol)
+		target := intstr.FromInt(int(port.TargetPort))
+		result = append(result, networkingv1.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &target,
+		})
+	}
+	return
+}
@@ -19,7 +19,7 @@ func (p Plugin) Convert(rf *dollyfile.DollyFile) (result []runtime.Object) {
 		labels := labels.SelectorLabels(service)
 		subject := subject(service)
 		if subject == nil {
-			return nil
+			continue
 		}
 
 		// serviceAccount
@@ -2,9 +2,12 @@ package service
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/rancher/dolly/pkg/dollyfile/stringers"
+	"github.com/rancher/wrangler/pkg/name"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/rancher/dolly/pkg/types"
@@ -15,20 +18,190 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+var (
+	serviceTypes = []types.ServiceType{
+		types.ServiceTypeClusterIP,
+		types.ServiceTypeNodePort,
+		types.ServiceTypeLoadBalancer,
+		types.ServiceTypeHeadless,
+	}
+	serviceTypeSuffixes = map[types.ServiceType]string{
+		types.ServiceTypeClusterIP:    "clusterip",
+		types.ServiceTypeNodePort:     "nodeport",
+		types.ServiceTypeLoadBalancer: "lb",
+		types.ServiceTypeHeadless:     "headless",
+	}
+)
+
 type Plugin struct{}
 
 func (p Plugin) Convert(rf *dollyfile.DollyFile) (ret []runtime.Object) {
 	for _, service := range rf.Services {
-		svc := newServiceSelector(service.Name, service.Namespace, v1.ServiceTypeClusterIP, service.Labels, labels.SelectorLabels(service))
-		if len(serviceNamedPorts(service)) > 0 {
-			svc.Spec.Ports = serviceNamedPorts(service)
+		if utils.IsExternal(service) {
+			ret = append(ret, externalService(service)...)
+			continue
 		}
-		ret = append(ret, svc)
+		ret = append(ret, services(service)...)
 	}
 
 	return ret
 }
 
+// services creates the Service of the service type, plus one extra Service per port type that differs from
+// it. Internal services keep every port so the service name can always be used inside the cluster.
+func services(service types.Service) (result []runtime.Object) {
+	serviceType := ServiceType(service)
+	internal := serviceType == types.ServiceTypeClusterIP || serviceType == types.ServiceTypeHeadless
+	statefulset := utils.WorkloadKind(service) == types.WorkloadStatefulSet
+
+	var ports []types.ContainerPort
+	portsByType := map[types.ServiceType][]types.ContainerPort{}
+	for _, port := range utils.ContainerPorts(service) {
+		portType := portServiceType(serviceType, port)
+		if portType == serviceType || internal {
+			ports = append(ports, port)
+		}
+		if portType != serviceType {
+			portsByType[portType] = append(portsByType[portType], port)
+		}
+	}
+
+	// a StatefulSet needs a headless governing Service with every port for the DNS names of its pods
+	if statefulset && serviceType != types.ServiceTypeHeadless {
+		portsByType[types.ServiceTypeHeadless] = utils.ContainerPorts(service)
+	}
+
+	// a Service without ports is invalid, only headless services may omit them
+	if len(ports) > 0 || serviceType == types.ServiceTypeHeadless {
+		result = append(result, newService(service, service.Name, serviceType, ports))
+	}
+
+	for _, portType := range serviceTypes {
+		governing := statefulset && portType == types.ServiceTypeHeadless && serviceType != types.ServiceTypeHeadless
+		if len(portsByType[portType]) == 0 && !governing {
+			continue
+		}
+		svcName := name.SafeConcatName(service.Name, serviceTypeSuffixes[portType])
+		result = append(result, newService(service, svcName, portType, portsByType[portType]))
+	}
+
+	if statefulset {
+		for _, obj := range result {
+			if svc := obj.(*v1.Service); svc.Name == utils.GoverningServiceName(service) {
+				svc.Spec.PublishNotReadyAddresses = true
+			}
+		}
+	}
+
+	return
+}
+
+// ServiceType returns the Service type of the service, defaulting to ClusterIP
+func ServiceType(service types.Service) types.ServiceType {
+	if service.Spec.ServiceType == "" {
+		return types.ServiceTypeClusterIP
+	}
+	return service.Spec.ServiceType
+}
+
+func portServiceType(serviceType types.ServiceType, port types.ContainerPort) types.ServiceType {
+	if port.ServiceType != "" {
+		return port.ServiceType
+	}
+	if port.NodePort > 0 && (serviceType == types.ServiceTypeClusterIP || serviceType == types.ServiceTypeHeadless) {
+		return types.ServiceTypeNodePort
+	}
+	return serviceType
+}
+
+func newService(service types.Service, name string, serviceType types.ServiceType, ports []types.ContainerPort) *v1.Service {
+	svc := newServiceSelector(name, service.Namespace, v1.ServiceTypeClusterIP, service.Labels, labels.SelectorLabels(service))
+	svc.Annotations = service.Spec.ServiceAnnotations
+	svc.Spec.Ports = serviceNamedPorts(ports, serviceType)
+	svc.Spec.SessionAffinity = service.Spec.SessionAffinity
+
+	if service.Spec.SessionAffinity == v1.ServiceAffinityClientIP && service.Spec.SessionAffinityTimeoutSeconds != nil {
+		svc.Spec.SessionAffinityConfig = &v1.SessionAffinityConfig{
+			ClientIP: &v1.ClientIPConfig{
+				TimeoutSeconds: service.Spec.SessionAffinityTimeoutSeconds,
+			},
+		}
+	}
+
+	switch serviceType {
+	case types.ServiceTypeHeadless:
+		svc.Spec.ClusterIP = v1.ClusterIPNone
+	case types.ServiceTypeLoadBalancer:
+		svc.Spec.LoadBalancerIP = service.Spec.LoadBalancerIP
+		svc.Spec.LoadBalancerSourceRanges = service.Spec.LoadBalancerSourceRanges
+		fallthrough
+	case types.ServiceTypeNodePort:
+		svc.Spec.Type = v1.ServiceType(serviceType)
+		svc.Spec.ExternalTrafficPolicy = service.Spec.ExternalTrafficPolicy
+	}
+
+	return svc
+}
+
+// externalService creates an ExternalName Service for a DNS name, or a Service without selector and a
+// matching Endpoints for an IP address
+func externalService(service types.Service) []runtime.Object {
+	ports := externalPorts(service)
+
+	svc := newServiceSelector(service.Name, service.Namespace, v1.ServiceTypeClusterIP, service.Labels, nil)
+	svc.Annotations = service.Spec.ServiceAnnotations
+	svc.Spec.Ports = serviceNamedPorts(ports, types.ServiceTypeClusterIP)
+
+	ip := net.ParseIP(service.Spec.External)
+	if ip == nil {
+		svc.Spec.Type = v1.ServiceTypeExternalName
+		svc.Spec.ExternalName = service.Spec.External
+		return []runtime.Object{svc}
+	}
+
+	if len(ports) == 0 {
+		svc.Spec.ClusterIP = v1.ClusterIPNone
+	}
+
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:    service.Labels,
+			Name:      service.Name,
+			Namespace: service.Namespace,
+		},
+		Subsets: []v1.EndpointSubset{
+			{
+				Addresses: []v1.EndpointAddress{
+					{
+						IP: ip.String(),
+					},
+				},
+			},
+		},
+	}
+	for _, port := range svc.Spec.Ports {
+		endpoints.Subsets[0].Ports = append(endpoints.Subsets[0].Ports, v1.EndpointPort{
+			Name:     port.Name,
+			Port:     port.TargetPort.IntVal,
+			Protocol: port.Protocol,
+		})
+	}
+
+	return []runtime.Object{svc, endpoints}
+}
+
+// externalPorts returns the ports of an external service, which has no container to read them from
+func externalPorts(service types.Service) (ports []types.ContainerPort) {
+	for _, port := range service.Spec.Ports {
+		port = stringers.NormalizeContainerPort(port)
+		if port.Port == 0 {
+			continue
+		}
+		ports = append(ports, port)
+	}
+	return
+}
+
 func newServiceSelector(name, namespace string, serviceType v1.ServiceType, labels, selectorLabels map[string]string) *v1.Service {
 	return &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -39,20 +212,12 @@ func newServiceSelector(name, namespace string, serviceType v1.ServiceType, labe
 		Spec: v1.ServiceSpec{
 			Type:     serviceType,
 			Selector: selectorLabels,
-			Ports: []v1.ServicePort{
-				{
-					Name:       "default",
-					Protocol:   v1.ProtocolTCP,
-					TargetPort: intstr.FromInt(80),
-					Port:       80,
-				},
-			},
 		},
 	}
 }
 
-func serviceNamedPorts(service types.Service) (servicePorts []v1.ServicePort) {
-	for _, port := range utils.ContainerPorts(service) {
+func serviceNamedPorts(ports []types.ContainerPort, serviceType types.ServiceType) (servicePorts []v1.ServicePort) {
+	for _, port := range ports {
 		servicePort := v1.ServicePort{
 			Name:     port.Name,
 			Port:     port.Port,
@@ -66,6 +231,10 @@ func serviceNamedPorts(service types.Service) (servicePorts []v1.ServicePort) {
 			servicePort.Name = strings.ToLower(fmt.Sprintf("%s-%d", port.Protocol, port.Port))
 		}
 
+		if serviceType == types.ServiceTypeNodePort || serviceType == types.ServiceTypeLoadBalancer {
+			servicePort.NodePort = port.NodePort
+		}
+
 		servicePorts = append(servicePorts, servicePort)
 	}
 
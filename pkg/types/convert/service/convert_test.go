@@ -0,0 +1,65 @@
+package service
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/rancher/dolly/pkg/dollyfile"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestStatefulSetServices(t *testing.T) {
+	type expected struct {
+		name      string
+		typ       v1.ServiceType
+		clusterIP string
+		ports     int
+		governing bool
+	}
+
+	for serviceType, services := range map[string][]expected{
+		"ClusterIP": {
+			{name: "db", typ: v1.ServiceTypeClusterIP, ports: 1},
+			{name: "db-headless", typ: v1.ServiceTypeClusterIP, clusterIP: v1.ClusterIPNone, ports: 1, governing: true},
+		},
+		"NodePort": {
+			{name: "db", typ: v1.ServiceTypeNodePort, ports: 1},
+			{name: "db-headless", typ: v1.ServiceTypeClusterIP, clusterIP: v1.ClusterIPNone, ports: 1, governing: true},
+		},
+		"LoadBalancer": {
+			{name: "db", typ: v1.ServiceTypeLoadBalancer, ports: 1},
+			{name: "db-headless", typ: v1.ServiceTypeClusterIP, clusterIP: v1.ClusterIPNone, ports: 1, governing: true},
+		},
+		"None": {
+			{name: "db", typ: v1.ServiceTypeClusterIP, clusterIP: v1.ClusterIPNone, ports: 1, governing: true},
+		},
+	} {
+		rf, err := dollyfile.Parse([]byte(`services:
+  db:
+    image: postgres
+    kind: StatefulSet
+    serviceType: `+serviceType+`
+    ports: 5432/tcp
+`), "default", nil)
+		if !assert.NoError(t, err, serviceType) {
+			continue
+		}
+
+		var result []expected
+		for _, obj := range (Plugin{}).Convert(rf) {
+			svc := obj.(*v1.Service)
+			result = append(result, expected{
+				name:      svc.Name,
+				typ:       svc.Spec.Type,
+				clusterIP: svc.Spec.ClusterIP,
+				ports:     len(svc.Spec.Ports),
+				governing: svc.Spec.PublishNotReadyAddresses,
+			})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].name < result[j].name
+		})
+		assert.Equal(t, services, result, serviceType)
+	}
+}
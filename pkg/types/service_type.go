@@ -1,7 +1,9 @@
 package types
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -21,6 +23,39 @@ type ServiceSpec struct {
 	// Hostname specified to access the service
 	Hostnames []string `json:"hostname,omitempty" alias:"hostname"`
 
+	// Kubernetes Service type used to expose the service ports. One of ClusterIP, NodePort, LoadBalancer or None(headless). Defaults to ClusterIP.
+	// A single port can override this type, in which case an extra Service of that type is created for the port.
+	ServiceType ServiceType `json:"serviceType,omitempty" mapper:"enum=ClusterIP|NodePort|LoadBalancer|None|headless=None,alias=type,output=type"`
+
+	// Denotes if this Service desires to route external traffic to node-local or cluster-wide endpoints. Only applies to NodePort and LoadBalancer. One of Cluster or Local.
+	ExternalTrafficPolicy v1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty" mapper:"enum=Cluster|Local"`
+
+	// Supports "ClientIP" and "None". Used to maintain session affinity. Enable client IP based session affinity. Defaults to None.
+	SessionAffinity v1.ServiceAffinity `json:"sessionAffinity,omitempty" mapper:"enum=None|ClientIP"`
+
+	// The seconds of ClientIP type session sticky time. Only applies if SessionAffinity is ClientIP. Defaults to 10800 (3 hours).
+	SessionAffinityTimeoutSeconds *int32 `json:"sessionAffinityTimeoutSeconds,omitempty"`
+
+	// Only applies to LoadBalancer. The LoadBalancer will get created with the IP specified in this field, if supported by the cloud provider.
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// Only applies to LoadBalancer. Restricts traffic through the cloud-provider load-balancer to the specified client IPs.
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+
+	// Annotations to be applied to the created Services, typically used to configure cloud load balancers
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// External DNS name or IP address of a service running outside of the cluster, such as a managed database.
+	// No workload is created for an external service. A DNS name creates an ExternalName Service and an IP address creates
+	// a Service with a matching Endpoints so that other services can reference it by name.
+	External string `json:"external,omitempty"`
+
+	// Services of the Dollyfile this service connects to. Network policies allow this traffic on the ports of the target service
+	Connects []string `json:"connects,omitempty" mapper:"stringSlice,alias=connect"`
+
+	// Services of the Dollyfile or CIDRs allowed to connect to this service, in addition to the services that declare a connection to it
+	AllowFrom []string `json:"allowFrom,omitempty" mapper:"stringSlice,alias=allow_from"`
+
 	// Place one pod per node that matches the scheduling rules
 	Global bool `json:"global,omitempty"`
 
@@ -54,8 +89,24 @@ type ServiceSpec struct {
 	// App name of the deployment. If empty defaults to service name
 	App string `json:"app,omitempty"`
 
+	// Kind of workload running the service, one of Deployment, StatefulSet or DaemonSet. Defaults to DaemonSet for global services,
+	// StatefulSet for services with volume templates and Deployment otherwise
+	Kind WorkloadKind `json:"kind,omitempty" mapper:"enum=Deployment|StatefulSet|DaemonSet"`
+
 	// The replicas of deployment
-	Replicas *int `json:"replicas,omitempty" mapper:"alias=scale"`
+	Replicas *int `json:"replicas,omitempty" mapper:"alias=scale,output=scale"`
+
+	// How the pods of a StatefulSet are created and deleted, OrderedReady one at a time in order or Parallel all at once. Defaults to Parallel
+	PodManagement appsv1.PodManagementPolicyType `json:"podManagement,omitempty" mapper:"enum=OrderedReady|Parallel|ordered=OrderedReady,alias=podManagementPolicy"`
+
+	// Strategy used to replace old pods, RollingUpdate, Recreate for Deployments or OnDelete for StatefulSets and DaemonSets. Defaults to RollingUpdate
+	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty" mapper:"enum=RollingUpdate|Recreate|OnDelete"`
+
+	// Only pods of a StatefulSet with an ordinal greater or equal to the partition are updated by a rolling update, used to stage updates
+	Partition *int32 `json:"partition,omitempty"`
+
+	// PodDisruptionBudget of the service, limiting how many pods can be evicted at once by voluntary disruptions such as node drains
+	Disruption *DisruptionBudget `json:"disruption,omitempty" mapper:"alias=pdb"`
 
 	// Permissions to the Services. It will create corresponding ServiceAccounts, Roles and RoleBinding.
 	Permissions []Permission `json:"permissions,omitempty" mapper:"permissions,alias=permission"`
@@ -88,11 +139,74 @@ type PodConfig struct {
 	// DNS settings for this Pod
 	DNS *DNS `json:"dns,omitempty"`
 
+	// A special supplemental group that applies to all containers in a pod. Volumes that support ownership management are owned and writable by this group.
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// A list of groups applied to the first process run in each container, in addition to the container's primary GID.
+	SupplementalGroups []int64 `json:"supplementalGroups,omitempty"`
+
+	// Namespaced sysctls used for the pod, format `name=value`
+	Sysctls []v1.Sysctl `json:"sysctls,omitempty" mapper:"sysctls,envmap=sep==,alias=sysctl"`
+
+	// Security preset applied to the pod and all of its containers. The only preset is restricted, which makes the pod pass the restricted Pod Security Standard:
+	// privilege escalation is disallowed, all capabilities but NET_BIND_SERVICE are dropped, containers must run as non-root and the runtime/default seccomp profile is used unless another profile is set.
+	// The host network, host ports, host paths, unsafe sysctls and capabilities other than NET_BIND_SERVICE are rejected with it.
+	Security SecurityPreset `json:"security,omitempty" mapper:"enum=restricted"`
+
+	// Quality of Service class of the pod. One of Guaranteed, Burstable or BestEffort.
+	// Guaranteed sets the cpu and memory limits equal to the requests of every container, the limit wins if both are set.
+	// BestEffort drops the cpu and memory requests and limits, other resources such as GPUs are kept. Burstable is the default behavior.
+	QOS v1.PodQOSClass `json:"qos,omitempty" mapper:"enum=Guaranteed|Burstable|BestEffort"`
+
+	// Node labels a node must have for the pod to be scheduled on it, format `key=value`
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" mapper:"stringMap"`
+
+	// Tolerations of node taints, format `key[=value][:effect][,seconds=N]`. A toleration without value tolerates any value of the key and `*` tolerates every taint
+	Tolerations []v1.Toleration `json:"tolerations,omitempty" mapper:"tolerations,alias=toleration"`
+
+	// Name of the PriorityClass of the pod
+	PriorityClassName string `json:"priorityClassName,omitempty" mapper:"alias=priorityClass"`
+
+	// Spread the pods of the service evenly across topology domains, format `topology[,required][,maxSkew=N]`.
+	// Topology is a node label key or one of the zone, region or host shorthands. Spreading is a preference unless required is set.
+	Spread []TopologyRule `json:"spread,omitempty" mapper:"topologyRules"`
+
+	// Avoid placing two pods of the service in the same topology domain, format `topology[,required][,weight=N]`.
+	// Topology is a node label key or one of the zone, region or host shorthands. Anti-affinity is a preference unless required is set.
+	AntiAffinity []TopologyRule `json:"antiAffinity,omitempty" mapper:"topologyRules"`
+
 	*v1.Affinity `json:",inline"`
 
+	// Time the pod is given to shut down gracefully before it is killed, such as 60s. Defaults to 30s, or the longest drain time plus 30s
+	TerminationGracePeriod *metav1.Duration `json:"terminationGracePeriod,omitempty" mapper:"duration,alias=gracePeriod|stop_grace_period"`
+
 	Container
 }
 
+// DisruptionBudget sets either the minimum number of available pods or the maximum number of unavailable pods during voluntary disruptions
+type DisruptionBudget struct {
+	// Number or percentage of pods that must remain available, such as 1 or 50%
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Number or percentage of pods that can be unavailable, such as 1 or 25%
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// TopologyRule spreads the pods of a service across the domains of a topology such as zones or hosts
+type TopologyRule struct {
+	// Node label key of the topology domain. zone, region and host are shorthands for the well known labels
+	Topology string `json:"topology,omitempty"`
+
+	// Whether the rule must be satisfied to schedule the pod, otherwise it is only a preference
+	Required bool `json:"required,omitempty"`
+
+	// For spread, the maximum difference of the number of pods between two topology domains. Defaults to 1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// For anti-affinity preferences, the weight of the rule in the range 1-100. Defaults to 100
+	Weight int32 `json:"weight,omitempty"`
+}
+
 type NamedContainer struct {
 	// The name of the container
 	Name string `json:"name,omitempty"`
@@ -137,11 +251,26 @@ type Container struct {
 	// List of environment variables to set in the container. Cannot be updated.
 	Env []EnvVar `json:"env,omitempty" mapper:"env,envmap=sep==,alias=environment"`
 
-	// CPU, in milliCPU (e.g. 500 = .5 CPU cores)
-	CPUMillis *int64 `json:"cpuMillis,omitempty" mapper:"quantity,alias=cpu|cpus"`
+	// CPU request, stored in milliCPU (e.g. 500 = .5 CPU cores). Accepts a quantity (0.5 or 500m), a `request:limit` pair or a {request, limit} object. Plain numbers are CPU cores
+	CPUMillis *int64 `json:"cpuMillis,omitempty" mapper:"quantity=milli,requestLimit=cpuLimitMillis|milli,alias=cpu|cpus,output=cpus"`
+
+	// CPU limit, in milliCPU
+	CPULimitMillis *int64 `json:"cpuLimitMillis,omitempty" mapper:"quantity=milli"`
+
+	// Memory request, in bytes. Accepts a quantity (64Mi), a `request:limit` pair or a {request, limit} object
+	MemoryBytes *int64 `json:"memoryBytes,omitempty" mapper:"quantity,requestLimit=memoryLimitBytes,alias=mem|memory,output=memory"`
+
+	// Memory limit, in bytes
+	MemoryLimitBytes *int64 `json:"memoryLimitBytes,omitempty" mapper:"quantity"`
+
+	// Local ephemeral storage request, in bytes. Accepts a quantity (1Gi), a `request:limit` pair or a {request, limit} object
+	EphemeralStorageBytes *int64 `json:"ephemeralStorageBytes,omitempty" mapper:"quantity,requestLimit=ephemeralStorageLimitBytes,alias=ephemeralStorage,output=ephemeralStorage"`
+
+	// Local ephemeral storage limit, in bytes
+	EphemeralStorageLimitBytes *int64 `json:"ephemeralStorageLimitBytes,omitempty" mapper:"quantity"`
 
-	// Memory, in bytes
-	MemoryBytes *int64 `json:"memoryBytes,omitempty" mapper:"quantity,alias=mem|memory"`
+	// Extended resources such as nvidia.com/gpu. Extended resources can't be overcommitted, so both the request and limit are set to the value
+	ExtendedResources v1.ResourceList `json:"extendedResources,omitempty"`
 
 	// Secrets Mounts
 	Secrets []DataMount `json:"secrets,omitempty" mapper:"secrets,envmap=sep=:,alias=secret"`
@@ -149,11 +278,27 @@ type Container struct {
 	// Configmaps Mounts
 	Configs []DataMount `json:"configs,omitempty" mapper:"configs,envmap=sep=:,alias=config"`
 
+	// Probe used as both the liveness and readiness probe when those are not set. Accepts the short probe format and docker compose healthchecks
+	Healthcheck *v1.Probe `json:"healthcheck,omitempty" mapper:"probe,alias=health"`
+
 	// Periodic probe of container liveness. Container will be restarted if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
-	LivenessProbe *v1.Probe `json:"livenessProbe,omitempty" mapper:"alias=liveness"`
+	LivenessProbe *v1.Probe `json:"livenessProbe,omitempty" mapper:"probe,alias=liveness"`
 
 	// Periodic probe of container service readiness. Container will be removed from service endpoints if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
-	ReadinessProbe *v1.Probe `json:"readinessProbe,omitempty" mapper:"alias=readiness"`
+	ReadinessProbe *v1.Probe `json:"readinessProbe,omitempty" mapper:"probe,alias=readiness"`
+
+	// Hook run before the container is stopped, either a command or an `http://[host]:port/path` url
+	PreStop *v1.Handler `json:"preStop,omitempty" mapper:"handler"`
+
+	// Hook run right after the container is created, either a command or an `http://[host]:port/path` url
+	PostStart *v1.Handler `json:"postStart,omitempty" mapper:"handler"`
+
+	// Time to keep the container running after it is removed from the service endpoints, so in-flight connections can finish.
+	// Adds a preStop sleep of this duration unless preStop is set, such as 10s
+	Drain *metav1.Duration `json:"drain,omitempty" mapper:"duration"`
+
+	// Probe that must succeed before the liveness and readiness probes start, for containers that are slow to start. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes
+	StartupProbe *v1.Probe `json:"startupProbe,omitempty" mapper:"probe,alias=startup"`
 
 	// Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always if tag is does not start with v[0-9] or [0-9], or IfNotPresent otherwise. Cannot be updated. More info: https://kubernetes.io/docs/concepts/containers/images#updating-images
 	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty" mapper:"enum=Always|IfNotPresent|Never,alias=pullPolicy"`
@@ -174,6 +319,18 @@ type Container struct {
 	*ContainerSecurityContext
 }
 
+// DefaultResources are the resource requests set on containers that specify neither a request nor a limit
+type DefaultResources struct {
+	// Don't set any default resource requests
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Default CPU request, stored in milliCPU. Plain numbers are CPU cores. Defaults to 50m
+	CPUMillis *int64 `json:"cpuMillis,omitempty" mapper:"quantity=milli,alias=cpu|cpus,output=cpu"`
+
+	// Default memory request, in bytes. Defaults to 64Mi
+	MemoryBytes *int64 `json:"memoryBytes,omitempty" mapper:"quantity,alias=mem|memory,output=memory"`
+}
+
 type DataMount struct {
 	// The directory or file to mount the value to in the container
 	Target string `json:"target,omitempty"`
@@ -192,7 +349,7 @@ type VolumeTemplate struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 
 	// Name of the VolumeTemplate. A volume entry will use this name to refer to the created volume
-	Name string
+	Name string `json:"name,omitempty"`
 
 	// AccessModes contains the desired access modes the volume should have.
 	// More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1
@@ -235,8 +392,49 @@ type Volume struct {
 	// then Persistent is assumed to be true
 	Persistent bool `json:"persistent,omitempty"`
 
-	// Size specifies the size of the volume
+	// Size specifies the size of a persistent or ephemeral volume, or the size limit of an emptyDir volume
 	Size string `json:"size,omitempty"`
+
+	// Mount the volume read-only
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Back an emptyDir volume by memory instead of disk. The size counts against the memory limit of the container
+	Tmpfs bool `json:"tmpfs,omitempty"`
+
+	// Server of an NFS volume
+	NFSServer string `json:"nfsServer,omitempty"`
+
+	// Path exported by the NFS server
+	NFSPath string `json:"nfsPath,omitempty"`
+
+	// Sources of a projected volume, combining ConfigMaps, Secrets and pod metadata into a single directory
+	Projected *ProjectedVolume `json:"projected,omitempty"`
+
+	// Provision a volume per pod that is deleted together with the pod, using a generic ephemeral volume
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// CSI driver providing an inline ephemeral volume
+	Driver string `json:"driver,omitempty"`
+
+	// Attributes passed to the CSI driver of an inline volume
+	DriverAttributes map[string]string `json:"driverAttributes,omitempty"`
+
+	// Access modes of a persistent or ephemeral volume. Defaults to ReadWriteOnce
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// StorageClass of a persistent or ephemeral volume. Defaults to the default StorageClass of the cluster
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+type ProjectedVolume struct {
+	// Names of the ConfigMaps to project
+	Configs []string `json:"configs,omitempty"`
+
+	// Names of the Secrets to project
+	Secrets []string `json:"secrets,omitempty"`
+
+	// Pod metadata to project, one file per field named after it. One of labels, annotations, name, namespace or uid
+	DownwardAPI []string `json:"downwardAPI,omitempty"`
 }
 
 type EnvVar struct {
@@ -277,7 +475,10 @@ type ContainerSecurityContext struct {
 
 	// The GID to run the entrypoint of the container process. Uses runtime default if unset. May also be set in SecurityContext.
 	// If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence for that container.
-	RunAsGroup *int64 `json:"runAsGroup,omitempty" mapper:"alias=user"`
+	RunAsGroup *int64 `json:"runAsGroup,omitempty" mapper:"alias=group"`
+
+	// Indicates that the container must run as a non-root user. If true, the Kubelet will validate the image at runtime to ensure that it does not run as UID 0 (root) and fail to start the container if it does.
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
 
 	// Whether this container has a read-only root filesystem. Default is false.
 	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
@@ -287,8 +488,102 @@ type ContainerSecurityContext struct {
 	// Defaults to false.
 	// +optional
 	Privileged *bool `json:"privileged,omitempty"`
+
+	// AllowPrivilegeEscalation controls whether a process can gain more privileges than its parent process. This bool directly controls if the no_new_privs flag will be set on the container process.
+	AllowPrivilegeEscalation *bool `json:"allowPrivilegeEscalation,omitempty"`
+
+	// Capabilities to add to the container, for example NET_ADMIN
+	CapAdd []v1.Capability `json:"capAdd,omitempty" mapper:"alias=cap_add"`
+
+	// Capabilities to drop from the container, ALL drops every capability
+	CapDrop []v1.Capability `json:"capDrop,omitempty" mapper:"alias=cap_drop"`
+
+	// The seccomp profile of the container. One of runtime/default, unconfined or localhost/<path>, where path is relative to the kubelet's seccomp profile location.
+	// Overrides the pod level seccomp profile.
+	SeccompProfile string `json:"seccompProfile,omitempty" mapper:"alias=seccomp"`
+
+	// The AppArmor profile of the container. One of runtime/default, unconfined or localhost/<profile>, where profile is loaded on the node.
+	AppArmorProfile string `json:"appArmorProfile,omitempty" mapper:"alias=apparmor"`
+}
+
+type SecurityPreset string
+
+const (
+	// SecurityPresetRestricted configures the pod to pass the restricted Pod Security Standard
+	SecurityPresetRestricted SecurityPreset = "restricted"
+)
+
+var (
+	// RestrictedCapabilities are the only capabilities the restricted Pod Security Standard allows to add
+	RestrictedCapabilities = map[v1.Capability]bool{
+		"NET_BIND_SERVICE": true,
+	}
+	// RestrictedSysctls are the only sysctls the restricted Pod Security Standard allows
+	RestrictedSysctls = map[string]bool{
+		"kernel.shm_rmid_forced":              true,
+		"net.ipv4.ip_local_port_range":        true,
+		"net.ipv4.ip_unprivileged_port_start": true,
+		"net.ipv4.tcp_syncookies":             true,
+		"net.ipv4.ping_group_range":           true,
+	}
+)
+
+// WorkloadKind is the kind of the workload running the pods of a service
+type WorkloadKind string
+
+const (
+	WorkloadDeployment  WorkloadKind = "Deployment"
+	WorkloadStatefulSet WorkloadKind = "StatefulSet"
+	WorkloadDaemonSet   WorkloadKind = "DaemonSet"
+)
+
+// UpdateStrategy is the strategy used to replace the pods of a workload
+type UpdateStrategy string
+
+const (
+	UpdateStrategyRollingUpdate UpdateStrategy = "RollingUpdate"
+	UpdateStrategyRecreate      UpdateStrategy = "Recreate"
+	UpdateStrategyOnDelete      UpdateStrategy = "OnDelete"
+)
+
+// Isolation is the network isolation of the services of a Dollyfile
+type Isolation string
+
+const (
+	// IsolationStrict denies all traffic of the services except DNS, declared connections and ingress traffic to exposed http ports
+	IsolationStrict Isolation = "strict"
+	// IsolationNone only isolates the services that other services declare connections to
+	IsolationNone Isolation = "none"
+)
+
+// Ingress is the ingress controller exposing the hostnames of the services of a Dollyfile
+type Ingress struct {
+	// Ingress controller exposing the services, options: traefik, nginx, contour, gateway or none. dolly up detects the controller of the cluster if not set
+	Provider IngressProvider `json:"provider,omitempty" mapper:"enum=traefik|nginx|contour|gateway|none|ingress-nginx=nginx"`
+
+	// Ingress class of the Ingresses, defaults to the class of the provider
+	Class string `json:"class,omitempty" mapper:"alias=ingressClass"`
+
+	// Annotations of the Ingresses, added to the ones of the provider
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Gateway the HTTPRoutes of the gateway provider attach to, as [namespace/]name. Defaults to the first Gateway of the cluster
+	Gateway string `json:"gateway,omitempty"`
 }
 
+// IngressProvider is an ingress controller the services can be exposed with
+type IngressProvider string
+
+const (
+	IngressProviderTraefik IngressProvider = "traefik"
+	IngressProviderNginx   IngressProvider = "nginx"
+	IngressProviderContour IngressProvider = "contour"
+	// IngressProviderGateway exposes the services with HTTPRoutes of the Gateway API instead of Ingresses
+	IngressProviderGateway IngressProvider = "gateway"
+	// IngressProviderNone doesn't expose the services through an ingress controller
+	IngressProviderNone IngressProvider = "none"
+)
+
 type Protocol string
 
 const (
@@ -300,6 +595,15 @@ const (
 	ProtocolGRPC  Protocol = "GRPC"
 )
 
+type ServiceType string
+
+const (
+	ServiceTypeClusterIP    ServiceType = "ClusterIP"
+	ServiceTypeNodePort     ServiceType = "NodePort"
+	ServiceTypeLoadBalancer ServiceType = "LoadBalancer"
+	ServiceTypeHeadless     ServiceType = "None"
+)
+
 type ContainerPort struct {
 	Name string `json:"name,omitempty"`
 	// Expose will make the port available outside the cluster. All http/https ports will be set to true by default
@@ -309,6 +613,11 @@ type ContainerPort struct {
 	Port       int32    `json:"port"`
 	TargetPort int32    `json:"targetPort,omitempty"`
 	HostPort   bool     `json:"hostport,omitempty"`
+	// The port on each node on which this port is exposed. Only valid for NodePort and LoadBalancer, a port
+	// with a NodePort on a ClusterIP service is exposed through an extra NodePort Service
+	NodePort int32 `json:"nodePort,omitempty"`
+	// Overrides the Service type of the service for this port
+	ServiceType ServiceType `json:"serviceType,omitempty"`
 }
 
 func (in ContainerPort) IsHTTP() bool {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rancher/dolly/pkg/dollyfile/stringers"
 	"github.com/rancher/dolly/pkg/types"
+	"github.com/rancher/wrangler/pkg/name"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -73,3 +74,31 @@ func ContainerPorts(service types.Service) []types.ContainerPort {
 
 	return ports
 }
+
+func IsExternal(service types.Service) bool {
+	return service.Spec.External != ""
+}
+
+// WorkloadKind returns the kind of workload of the service, a service with volume templates defaults to a StatefulSet
+func WorkloadKind(service types.Service) types.WorkloadKind {
+	if service.Spec.Global {
+		return types.WorkloadDaemonSet
+	}
+	if service.Spec.Kind != "" {
+		return service.Spec.Kind
+	}
+	for _, template := range service.Spec.VolumeTemplates {
+		if template.Name != "" {
+			return types.WorkloadStatefulSet
+		}
+	}
+	return types.WorkloadDeployment
+}
+
+// GoverningServiceName returns the name of the headless Service that gives the pods of a StatefulSet stable DNS names
+func GoverningServiceName(service types.Service) string {
+	if service.Spec.ServiceType == types.ServiceTypeHeadless {
+		return service.Name
+	}
+	return name.SafeConcatName(service.Name, "headless")
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"github.com/rancher/dolly/pkg/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// DefaultVolumeSize is the size of persistent and ephemeral volumes that don't specify one
+const DefaultVolumeSize = "10G"
+
+// PersistentVolumeClaimSpec returns the claim of a persistent or ephemeral volume, defaulting to ReadWriteOnce and 10G
+func PersistentVolumeClaimSpec(volume types.Volume) v1.PersistentVolumeClaimSpec {
+	size := volume.Size
+	if size == "" {
+		size = DefaultVolumeSize
+	}
+
+	spec := v1.PersistentVolumeClaimSpec{
+		AccessModes: volume.AccessModes,
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceStorage: resource.MustParse(size),
+			},
+		},
+	}
+	if len(spec.AccessModes) == 0 {
+		spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	}
+	if volume.StorageClass != "" {
+		spec.StorageClassName = &volume.StorageClass
+	}
+	return spec
+}
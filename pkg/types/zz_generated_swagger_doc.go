@@ -0,0 +1,286 @@
+// Code generated by pkg/codegen. DO NOT EDIT.
+
+package types
+
+// The SwaggerDoc methods return the doc comments of the types and their fields by json name, the same as the Kubernetes
+// types do. They are the descriptions of the JSON Schema of the Dollyfile.
+
+func (ServiceSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"tls":                           "Secret used for tls",
+		"hostname":                      "Hostname specified to access the service",
+		"serviceType":                   "Kubernetes Service type used to expose the service ports. One of ClusterIP, NodePort, LoadBalancer or None(headless). Defaults to ClusterIP. A single port can override this type, in which case an extra Service of that type is created for the port.",
+		"externalTrafficPolicy":         "Denotes if this Service desires to route external traffic to node-local or cluster-wide endpoints. Only applies to NodePort and LoadBalancer. One of Cluster or Local.",
+		"sessionAffinity":               "Supports \"ClientIP\" and \"None\". Used to maintain session affinity. Enable client IP based session affinity. Defaults to None.",
+		"sessionAffinityTimeoutSeconds": "The seconds of ClientIP type session sticky time. Only applies if SessionAffinity is ClientIP. Defaults to 10800 (3 hours).",
+		"loadBalancerIP":                "Only applies to LoadBalancer. The LoadBalancer will get created with the IP specified in this field, if supported by the cloud provider.",
+		"loadBalancerSourceRanges":      "Only applies to LoadBalancer. Restricts traffic through the cloud-provider load-balancer to the specified client IPs.",
+		"serviceAnnotations":            "Annotations to be applied to the created Services, typically used to configure cloud load balancers",
+		"external":                      "External DNS name or IP address of a service running outside of the cluster, such as a managed database. No workload is created for an external service. A DNS name creates an ExternalName Service and an IP address creates a Service with a matching Endpoints so that other services can reference it by name.",
+		"connects":                      "Services of the Dollyfile this service connects to. Network policies allow this traffic on the ports of the target service",
+		"allowFrom":                     "Services of the Dollyfile or CIDRs allowed to connect to this service, in addition to the services that declare a connection to it",
+		"global":                        "Place one pod per node that matches the scheduling rules",
+		"maxUnavailable":                "The maximum number of pods that can be unavailable during the update. The value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). An absolute number is calculated from percentage by rounding down. This cannot be 0 if MaxSurge is 0. Defaults to 25%. Example: when this is set to 30%, the old ReplicaSet can be scaled down to 70% of desired pods immediately when the rolling update starts. Once new pods are ready, the old ReplicaSet can be scaled down further, followed by scaling up the new ReplicaSet, ensuring that the total number of pods available at all times during the update is at least 70% of desired pods.",
+		"maxSurge":                      "The maximum number of pods that can be scheduled above the desired number of pods. The value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). This can not be 0 if MaxUnavailable is 0. An absolute number is calculated from percentage by rounding up. Defaults to 25%. Example: when this is set to 30%, the new ReplicaSet can be scaled up immediately when the rolling update starts, such that the total number of old and new pods do not exceed 130% of desired pods. Once the old pods have been killed, the new ReplicaSet can be scaled up further, ensuring that total number of pods running at any time during the update is at most 130% of desired pods.",
+		"app":                           "App name of the deployment. If empty defaults to service name",
+		"kind":                          "Kind of workload running the service, one of Deployment, StatefulSet or DaemonSet. Defaults to DaemonSet for global services, StatefulSet for services with volume templates and Deployment otherwise",
+		"replicas":                      "The replicas of deployment",
+		"podManagement":                 "How the pods of a StatefulSet are created and deleted, OrderedReady one at a time in order or Parallel all at once. Defaults to Parallel",
+		"updateStrategy":                "Strategy used to replace old pods, RollingUpdate, Recreate for Deployments or OnDelete for StatefulSets and DaemonSets. Defaults to RollingUpdate",
+		"partition":                     "Only pods of a StatefulSet with an ordinal greater or equal to the partition are updated by a rolling update, used to stage updates",
+		"disruption":                    "PodDisruptionBudget of the service, limiting how many pods can be evicted at once by voluntary disruptions such as node drains",
+		"permissions":                   "Permissions to the Services. It will create corresponding ServiceAccounts, Roles and RoleBinding.",
+		"globalPermissions":             "GlobalPermissions to the Services. It will create corresponding ServiceAccounts, ClusterRoles and ClusterRoleBinding.",
+	}
+}
+
+func (PodConfig) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"containers":             "List of containers belonging to the pod. Containers cannot currently be added or removed. There must be at least one container in a Pod. Cannot be updated.",
+		"hostname":               "Specifies the hostname of the Pod If not specified, the pod's hostname will be set to a system-defined value.",
+		"hostAliases":            "HostAliases is an optional list of hosts and IPs that will be injected into the pod's hosts file if specified. This is only valid for non-hostNetwork pods.",
+		"hostNetwork":            "Host networking requested for this pod. Use the host's network namespace. If this option is set, the ports that will be used must be specified. Default to false.",
+		"imagePullSecrets":       "Image pull secret",
+		"volumeTemplates":        "Volumes to create per replica",
+		"dns":                    "DNS settings for this Pod",
+		"fsGroup":                "A special supplemental group that applies to all containers in a pod. Volumes that support ownership management are owned and writable by this group.",
+		"supplementalGroups":     "A list of groups applied to the first process run in each container, in addition to the container's primary GID.",
+		"sysctls":                "Namespaced sysctls used for the pod, format `name=value`",
+		"security":               "Security preset applied to the pod and all of its containers. The only preset is restricted, which makes the pod pass the restricted Pod Security Standard: privilege escalation is disallowed, all capabilities but NET_BIND_SERVICE are dropped, containers must run as non-root and the runtime/default seccomp profile is used unless another profile is set. The host network, host ports, host paths, unsafe sysctls and capabilities other than NET_BIND_SERVICE are rejected with it.",
+		"qos":                    "Quality of Service class of the pod. One of Guaranteed, Burstable or BestEffort. Guaranteed sets the cpu and memory limits equal to the requests of every container, the limit wins if both are set. BestEffort drops the cpu and memory requests and limits, other resources such as GPUs are kept. Burstable is the default behavior.",
+		"nodeSelector":           "Node labels a node must have for the pod to be scheduled on it, format `key=value`",
+		"tolerations":            "Tolerations of node taints, format `key[=value][:effect][,seconds=N]`. A toleration without value tolerates any value of the key and `*` tolerates every taint",
+		"priorityClassName":      "Name of the PriorityClass of the pod",
+		"spread":                 "Spread the pods of the service evenly across topology domains, format `topology[,required][,maxSkew=N]`. Topology is a node label key or one of the zone, region or host shorthands. Spreading is a preference unless required is set.",
+		"antiAffinity":           "Avoid placing two pods of the service in the same topology domain, format `topology[,required][,weight=N]`. Topology is a node label key or one of the zone, region or host shorthands. Anti-affinity is a preference unless required is set.",
+		"terminationGracePeriod": "Time the pod is given to shut down gracefully before it is killed, such as 60s. Defaults to 30s, or the longest drain time plus 30s",
+	}
+}
+
+func (DisruptionBudget) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":               "DisruptionBudget sets either the minimum number of available pods or the maximum number of unavailable pods during voluntary disruptions",
+		"minAvailable":   "Number or percentage of pods that must remain available, such as 1 or 50%",
+		"maxUnavailable": "Number or percentage of pods that can be unavailable, such as 1 or 25%",
+	}
+}
+
+func (TopologyRule) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":         "TopologyRule spreads the pods of a service across the domains of a topology such as zones or hosts",
+		"topology": "Node label key of the topology domain. zone, region and host are shorthands for the well known labels",
+		"required": "Whether the rule must be satisfied to schedule the pod, otherwise it is only a preference",
+		"maxSkew":  "For spread, the maximum difference of the number of pods between two topology domains. Defaults to 1",
+		"weight":   "For anti-affinity preferences, the weight of the rule in the range 1-100. Defaults to 100",
+	}
+}
+
+func (NamedContainer) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"name": "The name of the container",
+		"init": "List of initialization containers belonging to the pod. Init containers are executed in order prior to containers being started. If any init container fails, the pod is considered to have failed and is handled according to its restartPolicy. The name for an init container or normal container must be unique among all containers. Init containers may not have Lifecycle actions, Readiness probes, or Liveness probes. The resourceRequirements of an init container are taken into account during scheduling by finding the highest request/limit for each resource type, and then using the max of of that value or the sum of the normal containers. Limits are applied to init containers in a similar fashion. Init containers cannot currently be added or removed. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/init-containers/",
+	}
+}
+
+func (Container) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"image":                      "Docker image name. More info: https://kubernetes.io/docs/concepts/containers/images This field is optional to allow higher level config management to default or override container images in workload controllers like Deployments and StatefulSets.",
+		"command":                    "Entrypoint array. Not executed within a shell. The docker image's ENTRYPOINT is used if this is not provided. Variable references $(VAR_NAME) are expanded using the container's environment. If a variable cannot be resolved, the reference in the input string will be unchanged. The $(VAR_NAME) syntax can be escaped with a double $$, ie: $$(VAR_NAME). Escaped references will never be expanded, regardless of whether the variable exists or not. Cannot be updated. More info: https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell",
+		"args":                       "Arguments to the entrypoint. The docker image's CMD is used if this is not provided. Variable references $(VAR_NAME) are expanded using the container's environment. If a variable cannot be resolved, the reference in the input string will be unchanged. The $(VAR_NAME) syntax can be escaped with a double $$, ie: $$(VAR_NAME). Escaped references will never be expanded, regardless of whether the variable exists or not. Cannot be updated. More info: https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/#running-a-command-in-a-shell",
+		"workingDir":                 "Container's working directory. If not specified, the container runtime's default will be used, which might be configured in the container image. Cannot be updated.",
+		"ports":                      "List of ports to expose from the container. Exposing a port here gives the system additional information about the network connections a container uses, but is primarily informational. Not specifying a port here DOES NOT prevent that port from being exposed. Any port which is listening on the default \"0.0.0.0\" address inside a container will be accessible from the network. Cannot be updated.",
+		"env":                        "List of environment variables to set in the container. Cannot be updated.",
+		"cpuMillis":                  "CPU request, stored in milliCPU (e.g. 500 = .5 CPU cores). Accepts a quantity (0.5 or 500m), a `request:limit` pair or a {request, limit} object. Plain numbers are CPU cores",
+		"cpuLimitMillis":             "CPU limit, in milliCPU",
+		"memoryBytes":                "Memory request, in bytes. Accepts a quantity (64Mi), a `request:limit` pair or a {request, limit} object",
+		"memoryLimitBytes":           "Memory limit, in bytes",
+		"ephemeralStorageBytes":      "Local ephemeral storage request, in bytes. Accepts a quantity (1Gi), a `request:limit` pair or a {request, limit} object",
+		"ephemeralStorageLimitBytes": "Local ephemeral storage limit, in bytes",
+		"extendedResources":          "Extended resources such as nvidia.com/gpu. Extended resources can't be overcommitted, so both the request and limit are set to the value",
+		"secrets":                    "Secrets Mounts",
+		"configs":                    "Configmaps Mounts",
+		"healthcheck":                "Probe used as both the liveness and readiness probe when those are not set. Accepts the short probe format and docker compose healthchecks",
+		"livenessProbe":              "Periodic probe of container liveness. Container will be restarted if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+		"readinessProbe":             "Periodic probe of container service readiness. Container will be removed from service endpoints if the probe fails. Cannot be updated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+		"preStop":                    "Hook run before the container is stopped, either a command or an `http://[host]:port/path` url",
+		"postStart":                  "Hook run right after the container is created, either a command or an `http://[host]:port/path` url",
+		"drain":                      "Time to keep the container running after it is removed from the service endpoints, so in-flight connections can finish. Adds a preStop sleep of this duration unless preStop is set, such as 10s",
+		"startupProbe":               "Probe that must succeed before the liveness and readiness probes start, for containers that are slow to start. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
+		"imagePullPolicy":            "Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always if tag is does not start with v[0-9] or [0-9], or IfNotPresent otherwise. Cannot be updated. More info: https://kubernetes.io/docs/concepts/containers/images#updating-images",
+		"stdin":                      "Whether this container should allocate a buffer for stdin in the container runtime. If this is not set, reads from stdin in the container will always result in EOF. Default is false.",
+		"stdinOnce":                  "Whether the container runtime should close the stdin channel after it has been opened by a single attach. When stdin is true the stdin stream will remain open across multiple attach sessions. If stdinOnce is set to true, stdin is opened on container start, is empty until the first client attaches to stdin, and then remains open and accepts data until the client disconnects, at which time stdin is closed and remains closed until the container is restarted. If this flag is false, a container processes that reads from stdin will never receive an EOF. Default is false",
+		"tty":                        "Whether this container should allocate a TTY for itself, also requires 'stdin' to be true. Default is false.",
+		"volumes":                    "Pod volumes to mount into the container's filesystem",
+	}
+}
+
+func (DefaultResources) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "DefaultResources are the resource requests set on containers that specify neither a request nor a limit",
+		"disabled":    "Don't set any default resource requests",
+		"cpuMillis":   "Default CPU request, stored in milliCPU. Plain numbers are CPU cores. Defaults to 50m",
+		"memoryBytes": "Default memory request, in bytes. Defaults to 64Mi",
+	}
+}
+
+func (DataMount) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"target": "The directory or file to mount the value to in the container",
+		"name":   "The name of the ConfigMap or Secret to mount",
+		"key":    "The key in the data of the ConfigMap or Secret to mount to a file. If Key is set the Target must be a file.  If key is set the target must be a directory and will contain one file per key from the Secret/ConfigMap data field.",
+	}
+}
+
+func (VolumeTemplate) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"labels":           "Labels to be applied to the created PVC",
+		"annotations":      "Annotations to be applied to the created PVC",
+		"name":             "Name of the VolumeTemplate. A volume entry will use this name to refer to the created volume",
+		"accessModes":      "AccessModes contains the desired access modes the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#access-modes-1",
+		"storage":          "Resources represents the minimum resources the volume should have. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#resources",
+		"storageClassName": "Name of the StorageClass required by the claim. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#class-1",
+		"volumeMode":       "volumeMode defines what type of volume is required by the claim. Value of Filesystem is implied when not included in claim spec. This is a beta feature.",
+	}
+}
+
+func (Volume) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"name":             "Name is the name of the volume. If multiple Volumes in the same pod share the same name they will be the same underlying storage. If persistent is set to true Name is required and will be used to reference a PersistentVolumeClaim in the current namespace. If Name matches the name of a VolumeTemplate on this service then the VolumeTemplate will be used as the source of the volume.",
+		"path":             "That path within the container to mount the volume to",
+		"hostpath":         "That path on the host to mount into this container",
+		"hostPathType":     "HostPathType specify HostPath type",
+		"persistent":       "If Persistent is true then this volume refers to a PersistentVolumeClaim in this namespace. The Name field is used to reference PersistentVolumeClaim.  If the Name of this Volume matches a VolumeTemplate then Persistent is assumed to be true",
+		"size":             "Size specifies the size of a persistent or ephemeral volume, or the size limit of an emptyDir volume",
+		"readOnly":         "Mount the volume read-only",
+		"tmpfs":            "Back an emptyDir volume by memory instead of disk. The size counts against the memory limit of the container",
+		"nfsServer":        "Server of an NFS volume",
+		"nfsPath":          "Path exported by the NFS server",
+		"projected":        "Sources of a projected volume, combining ConfigMaps, Secrets and pod metadata into a single directory",
+		"ephemeral":        "Provision a volume per pod that is deleted together with the pod, using a generic ephemeral volume",
+		"driver":           "CSI driver providing an inline ephemeral volume",
+		"driverAttributes": "Attributes passed to the CSI driver of an inline volume",
+		"accessModes":      "Access modes of a persistent or ephemeral volume. Defaults to ReadWriteOnce",
+		"storageClass":     "StorageClass of a persistent or ephemeral volume. Defaults to the default StorageClass of the cluster",
+	}
+}
+
+func (ProjectedVolume) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"configs":     "Names of the ConfigMaps to project",
+		"secrets":     "Names of the Secrets to project",
+		"downwardAPI": "Pod metadata to project, one file per field named after it. One of labels, annotations, name, namespace or uid",
+	}
+}
+
+func (DNS) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"policy":      "Set DNS policy for the pod. Defaults to \"ClusterFirst\". Valid values are 'ClusterFirstWithHostNet', 'ClusterFirst', 'Default' or 'None'. DNS parameters given in DNSConfig will be merged with the policy selected with DNSPolicy. To have DNS options set along with hostNetwork, you have to specify DNS policy explicitly to 'ClusterFirstWithHostNet'.",
+		"nameservers": "A list of DNS name server IP addresses. This will be appended to the base nameservers generated from DNSPolicy. Duplicated nameservers will be removed.",
+		"searches":    "A list of DNS search domains for host-name lookup. This will be appended to the base search paths generated from DNSPolicy. Duplicated search paths will be removed.",
+		"options":     "A list of DNS resolver options. This will be merged with the base options generated from DNSPolicy. Duplicated entries will be removed. Resolution options given in Options will override those that appear in the base DNSPolicy.",
+	}
+}
+
+func (ContainerSecurityContext) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":                         "ContainerSecurityContext holds pod-level security attributes and common container constants. Optional: Defaults to empty. See type description for default values of each field.",
+		"runAsUser":                "The UID to run the entrypoint of the container process. Defaults to user specified in image metadata if unspecified. May also be set in SecurityContext. If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence for that container",
+		"runAsGroup":               "The GID to run the entrypoint of the container process. Uses runtime default if unset. May also be set in SecurityContext. If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence for that container.",
+		"runAsNonRoot":             "Indicates that the container must run as a non-root user. If true, the Kubelet will validate the image at runtime to ensure that it does not run as UID 0 (root) and fail to start the container if it does.",
+		"readOnlyRootFilesystem":   "Whether this container has a read-only root filesystem. Default is false.",
+		"privileged":               "Run container in privileged mode. Processes in privileged containers are essentially equivalent to root on the host. Defaults to false.",
+		"allowPrivilegeEscalation": "AllowPrivilegeEscalation controls whether a process can gain more privileges than its parent process. This bool directly controls if the no_new_privs flag will be set on the container process.",
+		"capAdd":                   "Capabilities to add to the container, for example NET_ADMIN",
+		"capDrop":                  "Capabilities to drop from the container, ALL drops every capability",
+		"seccompProfile":           "The seccomp profile of the container. One of runtime/default, unconfined or localhost/<path>, where path is relative to the kubelet's seccomp profile location. Overrides the pod level seccomp profile.",
+		"appArmorProfile":          "The AppArmor profile of the container. One of runtime/default, unconfined or localhost/<profile>, where profile is loaded on the node.",
+	}
+}
+
+func (Ingress) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":            "Ingress is the ingress controller exposing the hostnames of the services of a Dollyfile",
+		"provider":    "Ingress controller exposing the services, options: traefik, nginx, contour, gateway or none. dolly up detects the controller of the cluster if not set",
+		"class":       "Ingress class of the Ingresses, defaults to the class of the provider",
+		"annotations": "Annotations of the Ingresses, added to the ones of the provider",
+		"gateway":     "Gateway the HTTPRoutes of the gateway provider attach to, as [namespace/]name. Defaults to the first Gateway of the cluster",
+	}
+}
+
+func (ContainerPort) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"expose":      "Expose will make the port available outside the cluster. All http/https ports will be set to true by default if Expose is nil.  All other protocols are set to false by default",
+		"nodePort":    "The port on each node on which this port is exposed. Only valid for NodePort and LoadBalancer, a port with a NodePort on a ClusterIP service is exposed through an extra NodePort Service",
+		"serviceType": "Overrides the Service type of the service for this port",
+	}
+}
+
+func (RouterSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"secret":    "Secret used for tls",
+		"hostnames": "Hostname specified to access the service",
+		"routes":    "An ordered list of route rules for HTTP traffic. The first rule matching an incoming request is used.",
+		"internal":  "By default all Routers are public and exposed outside of the cluster. Setting internal to true will cause the Router to not be exposed",
+	}
+}
+
+func (RouterStatus) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"endpoints":  "The endpoint to access the router",
+		"conditions": "Represents the latest available observations of a PublicDomain's current state.",
+	}
+}
+
+func (RouteSpec) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"match":          "Match conditions to be satisfied for the rule to be activated. All conditions inside a single match block have AND semantics, while the list of match blocks have OR semantics. The rule is matched if any one of the match blocks succeed.",
+		"to":             "An http rule can either redirect or forward (default) traffic. The forwarding target can be one of several versions of a service (see glossary in beginning of document). Weights associated with the service version determine the proportion of traffic it receives.",
+		"redirect":       "An http rule can either redirect or forward (default) traffic. If traffic passthrough option is specified in the rule, route/redirect will be ignored. The redirect primitive can be used to send a HTTP 301 redirect to a different URI or Authority.",
+		"rewrite":        "Rewrite HTTP URIs and Authority headers. Rewrite cannot be used with Redirect primitive. Rewrite will be performed before forwarding.",
+		"retry":          "Retries specifies the retry logic for each route",
+		"headers":        "Header manipulation rules",
+		"fault":          "Fault injection policy to apply on HTTP traffic at the client side. Note that timeouts or retries will not be enabled when faults are enabled on the client side.",
+		"mirror":         "Mirror HTTP traffic to a another destination in addition to forwarding the requests to the intended destination. Mirrored traffic is on a best effort basis where the sidecar/gateway will not wait for the mirrored cluster to respond before returning the response from the original destination. Statistics will be generated for the mirrored destination.",
+		"timeoutSeconds": "TimeoutSeconds specifies timeout setting for each route",
+	}
+}
+
+func (HeaderOperations) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"":       "HeaderOperations Describes the header manipulations to apply",
+		"add":    "Append the given values to the headers specified by keys (will create a comma-separated list of values)",
+		"set":    "Append the given values to the headers specified by keys (will create a comma-separated list of values)",
+		"remove": "Remove a the specified headers",
+	}
+}
+
+func (WeightedDestination) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"weight": "Weight for the Destination",
+	}
+}
+
+func (Destination) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"app":     "Destination Service",
+		"version": "Destination Revision",
+		"port":    "Destination Port",
+	}
+}
+
+func (Fault) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"percentage":      "Percentage of requests on which the delay will be injected.",
+		"delayMillis":     "REQUIRED. Add a fixed delay before forwarding the request. Units: milliseconds",
+		"abortHTTPStatus": "Abort Http request attempts and return error codes back to downstream service, giving the impression that the upstream service is faulty.",
+	}
+}
+
+func (Match) SwaggerDoc() map[string]string {
+	return map[string]string{
+		"path":    "URI to match values are case-sensitive and formatted as follows: exact: \"value\" for exact string match prefix: \"value\" for prefix-based match regex: \"value\" for ECMAscript style regex-based match",
+		"schema":  "Schema defines schema based match",
+		"methods": "HTTP Method values are case-sensitive and formatted as follows: exact: \"value\" for exact string match prefix: \"value\" for prefix-based match regex: \"value\" for ECMAscript style regex-based match",
+		"headers": "The header keys must be lowercase and use hyphen as the separator, e.g. x-request-id. Header values are case-sensitive and formatted as follows: exact: \"value\" for exact string match prefix: \"value\" for prefix-based match regex: \"value\" for ECMAscript style regex-based match Note: The keys uri, scheme, method, and authority will be ignored.",
+	}
+}
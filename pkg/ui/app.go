@@ -0,0 +1,594 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rancher/dolly/pkg/describe"
+	"github.com/rancher/dolly/pkg/lifecycle"
+	"github.com/rancher/dolly/pkg/log"
+	"github.com/rancher/dolly/pkg/resolve"
+	"github.com/rancher/dolly/pkg/table/types"
+	"github.com/rancher/dolly/pkg/tables"
+	"github.com/stern/stern/stern"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	logsPane   = "logs"
+	eventsPane = "events"
+
+	// maxLines of the logs kept for the logs pane
+	maxLines = 500
+
+	reverse = "\033[7m"
+	reset   = "\033[0m"
+)
+
+var (
+	escapes = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+	logTemplate = template.Must(template.New("log").Parse("{{.PodName}} {{.ContainerName}} {{.Message}}\n"))
+
+	help = "↑/↓ select  l logs  e events  x shell  r restart  +/- scale  q quit"
+)
+
+// Row is a line of the list, a workload or one of its pods
+type Row struct {
+	Type      string
+	Namespace string
+	Name      string
+	// Workload is the workload of the row, the one owning the pod for a pod
+	Workload runtime.Object
+	// Pod is set on the rows of pods
+	Pod *v1.Pod
+}
+
+func (r Row) String() string {
+	return r.Type + "/" + r.Name
+}
+
+// App is the state of the dashboard. Key and Render are called from a single goroutine, the terminal drawing it.
+type App struct {
+	// Exec runs a shell in a pod, with the terminal handed over to it
+	Exec func(namespace, pod string) error
+
+	k8s       kubernetes.Interface
+	namespace string
+	filter    map[string]bool
+	factory   informers.SharedInformerFactory
+	changed   chan struct{}
+
+	selected int
+	offset   int
+	pane     string
+	message  string
+	// confirm runs the action waiting for y
+	confirm func() error
+
+	logs       *buffer
+	logsOf     string
+	cancelLogs context.CancelFunc
+}
+
+// New returns the dashboard of the workloads of the namespace, only the ones of filter as type/name if it is not nil
+func New(k8s kubernetes.Interface, namespace string, filter map[string]bool) *App {
+	return &App{
+		k8s:       k8s,
+		namespace: namespace,
+		filter:    filter,
+		factory:   informers.NewSharedInformerFactoryWithOptions(k8s, 0, informers.WithNamespace(namespace)),
+		changed:   make(chan struct{}, 1),
+		pane:      logsPane,
+	}
+}
+
+// Start watches the workloads, pods and events until the context is done
+func (a *App) Start(ctx context.Context) error {
+	if err := a.checkAccess(ctx); err != nil {
+		return err
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { a.notify() },
+		UpdateFunc: func(interface{}, interface{}) { a.notify() },
+		DeleteFunc: func(interface{}) { a.notify() },
+	}
+	for _, informer := range []cache.SharedIndexInformer{
+		a.factory.Apps().V1().Deployments().Informer(),
+		a.factory.Apps().V1().StatefulSets().Informer(),
+		a.factory.Apps().V1().DaemonSets().Informer(),
+		a.factory.Core().V1().Pods().Informer(),
+		a.factory.Core().V1().Events().Informer(),
+	} {
+		informer.AddEventHandler(handler)
+	}
+
+	a.factory.Start(ctx.Done())
+	for informer, synced := range a.factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to list %v", informer)
+		}
+	}
+	a.follow(ctx)
+	return nil
+}
+
+// checkAccess lists every watched resource once, the informers retry a list that fails forever instead of returning
+// its error
+func (a *App) checkAccess(ctx context.Context) error {
+	opts := metav1.ListOptions{Limit: 1}
+	for _, list := range []func() error{
+		func() error { _, err := a.k8s.AppsV1().Deployments(a.namespace).List(ctx, opts); return err },
+		func() error { _, err := a.k8s.AppsV1().StatefulSets(a.namespace).List(ctx, opts); return err },
+		func() error { _, err := a.k8s.AppsV1().DaemonSets(a.namespace).List(ctx, opts); return err },
+		func() error { _, err := a.k8s.CoreV1().Pods(a.namespace).List(ctx, opts); return err },
+		func() error { _, err := a.k8s.CoreV1().Events(a.namespace).List(ctx, opts); return err },
+	} {
+		if err := list(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops following the logs
+func (a *App) Stop() {
+	if a.cancelLogs != nil {
+		a.cancelLogs()
+	}
+}
+
+// Changed is notified when the objects or the logs shown change
+func (a *App) Changed() <-chan struct{} {
+	return a.changed
+}
+
+func (a *App) notify() {
+	select {
+	case a.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Rows returns the workloads sorted by name, each followed by its pods
+func (a *App) Rows() []Row {
+	var workloads []runtime.Object
+	if deployments, err := a.factory.Apps().V1().Deployments().Lister().List(labels.Everything()); err == nil {
+		for _, obj := range deployments {
+			workloads = append(workloads, obj)
+		}
+	}
+	if statefulsets, err := a.factory.Apps().V1().StatefulSets().Lister().List(labels.Everything()); err == nil {
+		for _, obj := range statefulsets {
+			workloads = append(workloads, obj)
+		}
+	}
+	if daemonsets, err := a.factory.Apps().V1().DaemonSets().Lister().List(labels.Everything()); err == nil {
+		for _, obj := range daemonsets {
+			workloads = append(workloads, obj)
+		}
+	}
+	pods, _ := a.factory.Core().V1().Pods().Lister().List(labels.Everything())
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	var rows []Row
+	for _, workload := range workloads {
+		r, err := types.FromObject(workload)
+		if err != nil || (a.filter != nil && !a.filter[r.Type+"/"+r.Name]) {
+			continue
+		}
+		rows = append(rows, Row{
+			Type:      r.Type,
+			Namespace: r.Namespace,
+			Name:      r.Name,
+			Workload:  workload,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Namespace != rows[j].Namespace {
+			return rows[i].Namespace < rows[j].Namespace
+		}
+		return rows[i].Name < rows[j].Name
+	})
+
+	var result []Row
+	for _, row := range rows {
+		result = append(result, row)
+		for _, pod := range pods {
+			if tables.SelectsPod(row.Workload, pod) {
+				result = append(result, Row{
+					Type:      types.PodType,
+					Namespace: pod.Namespace,
+					Name:      pod.Name,
+					Workload:  row.Workload,
+					Pod:       pod,
+				})
+			}
+		}
+	}
+	return result
+}
+
+// Selected returns the selected row, false if there are no rows
+func (a *App) Selected() (Row, bool) {
+	rows := a.Rows()
+	if len(rows) == 0 {
+		return Row{}, false
+	}
+	if a.selected >= len(rows) {
+		a.selected = len(rows) - 1
+	}
+	return rows[a.selected], true
+}
+
+// Key handles a key, such as up, down, enter, ctrl-c or a character, and returns false to quit
+func (a *App) Key(ctx context.Context, key string) bool {
+	if a.confirm != nil {
+		confirm := a.confirm
+		a.confirm = nil
+		a.message = ""
+		if key == "y" {
+			a.report(confirm())
+		}
+		return true
+	}
+	a.message = ""
+
+	switch key {
+	case "q", "ctrl-c":
+		return false
+	case "up", "k":
+		a.move(ctx, -1)
+	case "down", "j":
+		a.move(ctx, 1)
+	case "l":
+		a.pane = logsPane
+	case "e":
+		a.pane = eventsPane
+	case "tab":
+		if a.pane == logsPane {
+			a.pane = eventsPane
+		} else {
+			a.pane = logsPane
+		}
+	case "x":
+		a.report(a.shell(ctx))
+	case "r":
+		if row, ok := a.Selected(); ok {
+			r, err := types.FromObject(row.Workload)
+			if err != nil {
+				a.report(err)
+				break
+			}
+			a.message = fmt.Sprintf("Restart %s/%s? (y/n)", r.Type, r.Name)
+			a.confirm = func() error {
+				if err := lifecycle.Restart(ctx, a.k8s, r.Namespace, r.Type, r.Name); err != nil {
+					return err
+				}
+				a.message = fmt.Sprintf("Restarting %s/%s", r.Type, r.Name)
+				return nil
+			}
+		}
+	case "+", "=":
+		a.report(a.scale(ctx, 1))
+	case "-":
+		a.report(a.scale(ctx, -1))
+	}
+	return true
+}
+
+func (a *App) report(err error) {
+	if err != nil {
+		a.message = "Error: " + err.Error()
+	}
+}
+
+func (a *App) move(ctx context.Context, delta int) {
+	rows := a.Rows()
+	a.selected += delta
+	if a.selected >= len(rows) {
+		a.selected = len(rows) - 1
+	}
+	if a.selected < 0 {
+		a.selected = 0
+	}
+	a.follow(ctx)
+}
+
+// follow tails the logs of the selected row, if it changed
+func (a *App) follow(ctx context.Context) {
+	row, ok := a.Selected()
+	if !ok || row.String() == a.logsOf {
+		return
+	}
+	a.Stop()
+	a.logsOf = row.String()
+	a.logs = &buffer{notify: a.notify}
+
+	tail := int64(100)
+	config := &stern.Config{
+		Namespace:      row.Namespace,
+		LabelSelector:  labels.Everything(),
+		PodQuery:       regexp.MustCompile("^" + regexp.QuoteMeta(row.Name) + "$"),
+		ContainerQuery: regexp.MustCompile(""),
+		ContainerState: stern.RUNNING,
+		InitContainers: true,
+		Since:          48 * time.Hour,
+		TailLines:      &tail,
+		Template:       logTemplate,
+	}
+	if row.Pod == nil {
+		selector, err := workloadSelector(row.Workload)
+		if err != nil {
+			a.logs.Write([]byte(err.Error() + "\n"))
+			return
+		}
+		config.PodQuery = regexp.MustCompile("")
+		config.LabelSelector = selector
+	}
+
+	logCtx, cancel := context.WithCancel(ctx)
+	a.cancelLogs = cancel
+	logs := a.logs
+	go func() {
+		if err := log.OutputTo(logCtx, config, row.Namespace, a.k8s, logs); err != nil && logCtx.Err() == nil {
+			logs.Write([]byte(err.Error() + "\n"))
+		}
+	}()
+}
+
+func workloadSelector(workload runtime.Object) (labels.Selector, error) {
+	var selector *metav1.LabelSelector
+	switch v := workload.(type) {
+	case *appsv1.Deployment:
+		selector = v.Spec.Selector
+	case *appsv1.StatefulSet:
+		selector = v.Spec.Selector
+	case *appsv1.DaemonSet:
+		selector = v.Spec.Selector
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// shell execs a shell in the selected pod, or a ready pod of the selected workload
+func (a *App) shell(ctx context.Context) error {
+	row, ok := a.Selected()
+	if !ok {
+		return nil
+	}
+	if a.Exec == nil {
+		return fmt.Errorf("no terminal to open a shell in")
+	}
+	target := resolve.Target{
+		Namespace: row.Namespace,
+		Name:      row.Name,
+		Type:      row.Type,
+	}
+	if row.Pod == nil {
+		selector, err := workloadSelector(row.Workload)
+		if err != nil {
+			return err
+		}
+		target.Selector = selector
+	}
+	pod, err := target.Pod(ctx, a.k8s, -1, nil)
+	if err != nil {
+		return err
+	}
+	return a.Exec(row.Namespace, pod)
+}
+
+// scale adds delta to the replicas of the selected workload
+func (a *App) scale(ctx context.Context, delta int32) error {
+	row, ok := a.Selected()
+	if !ok {
+		return nil
+	}
+	var replicas int32 = 1
+	switch v := row.Workload.(type) {
+	case *appsv1.Deployment:
+		if v.Spec.Replicas != nil {
+			replicas = *v.Spec.Replicas
+		}
+	case *appsv1.StatefulSet:
+		if v.Spec.Replicas != nil {
+			replicas = *v.Spec.Replicas
+		}
+	}
+	replicas += delta
+	if replicas < 0 {
+		return nil
+	}
+
+	r, err := types.FromObject(row.Workload)
+	if err != nil {
+		return err
+	}
+	if err := lifecycle.Scale(ctx, a.k8s, r.Namespace, r.Type, r.Name, replicas); err != nil {
+		return err
+	}
+	a.message = fmt.Sprintf("Scaling %s/%s to %d", r.Type, r.Name, replicas)
+	return nil
+}
+
+// Render draws the screen of the given size, starting at the top left corner
+func (a *App) Render(width, height int) string {
+	if width < 20 || height < 8 {
+		return "\033[H\033[2J" + fit("window too small", width)
+	}
+	rows := a.Rows()
+	if a.selected >= len(rows) {
+		a.selected = len(rows) - 1
+	}
+	if a.selected < 0 {
+		a.selected = 0
+	}
+
+	listHeight := (height - 3) / 2
+	if len(rows) < listHeight {
+		listHeight = len(rows)
+	}
+	if listHeight == 0 {
+		listHeight = 1
+	}
+	if a.selected < a.offset {
+		a.offset = a.selected
+	}
+	if a.selected >= a.offset+listHeight {
+		a.offset = a.selected - listHeight + 1
+	}
+	paneHeight := height - 3 - listHeight
+
+	namespace := a.namespace
+	if namespace == "" {
+		namespace = "all namespaces"
+	}
+	lines := []string{
+		reverse + fit(fmt.Sprintf(" dolly ui  %s", namespace), width) + reset,
+	}
+	for i := a.offset; i < a.offset+listHeight; i++ {
+		switch {
+		case i >= len(rows):
+			lines = append(lines, "")
+		case i == a.selected:
+			lines = append(lines, reverse+fit(formatRow(rows[i]), width)+reset)
+		default:
+			lines = append(lines, fit(formatRow(rows[i]), width))
+		}
+	}
+
+	title := a.pane
+	if row, ok := a.Selected(); ok {
+		title += ": " + row.String()
+	}
+	lines = append(lines, fit("── "+title+" "+strings.Repeat("─", width), width))
+
+	var pane []string
+	if a.pane == eventsPane {
+		pane = a.events()
+	} else if a.logs != nil {
+		pane = a.logs.Lines()
+	}
+	if len(pane) > paneHeight {
+		pane = pane[len(pane)-paneHeight:]
+	}
+	for i := 0; i < paneHeight; i++ {
+		if i < len(pane) {
+			lines = append(lines, fit(pane[i], width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	footer := help
+	if a.message != "" {
+		footer = a.message
+	}
+	lines = append(lines, reverse+fit(" "+footer, width)+reset)
+
+	return "\033[H" + strings.Join(lines, "\033[K\r\n") + "\033[K"
+}
+
+func formatRow(row Row) string {
+	if row.Pod != nil {
+		ready, _ := tables.PodReady(row.Pod)
+		restarts := int32(0)
+		for _, status := range row.Pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		status := string(row.Pod.Status.Phase)
+		if row.Pod.DeletionTimestamp != nil {
+			status = "Terminating"
+		}
+		return fmt.Sprintf("  %-38s %-7s %-12s %3d restarts  %s", row.Name, ready, status, restarts,
+			tables.PodDetail(row.Pod))
+	}
+	return fmt.Sprintf("%-40s %-7s %-12s %s", row.String(), tables.FormatReady(row.Workload),
+		tables.RolloutStatus(row.Workload), tables.FormatImage(row.Workload))
+}
+
+// events returns the events of the selected row, the events of a workload include the ones of its replicasets and pods
+func (a *App) events() []string {
+	row, ok := a.Selected()
+	if !ok {
+		return nil
+	}
+	events, err := a.factory.Core().V1().Events().Lister().Events(row.Namespace).List(labels.Everything())
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var matched []*v1.Event
+	for _, event := range events {
+		name := event.InvolvedObject.Name
+		if name == row.Name || (row.Pod == nil && strings.HasPrefix(name, row.Name+"-")) {
+			matched = append(matched, event)
+		}
+	}
+	describe.SortEvents(matched)
+
+	var lines []string
+	for _, event := range matched {
+		lines = append(lines, describe.FormatEvent(event))
+	}
+	return lines
+}
+
+// fit cuts or pads a line to the width of the screen
+func fit(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	if n := utf8.RuneCountInString(line); n < width {
+		return line + strings.Repeat(" ", width-n)
+	}
+	return string([]rune(line)[:width])
+}
+
+// buffer keeps the last lines written to it
+type buffer struct {
+	lock   sync.Mutex
+	lines  []string
+	notify func()
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	text := escapes.ReplaceAllString(string(p), "")
+	text = strings.ReplaceAll(text, "\r", "")
+
+	b.lock.Lock()
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		b.lines = append(b.lines, line)
+	}
+	if len(b.lines) > maxLines {
+		b.lines = b.lines[len(b.lines)-maxLines:]
+	}
+	b.lock.Unlock()
+
+	if b.notify != nil {
+		b.notify()
+	}
+	return len(p), nil
+}
+
+// Lines returns a copy of the lines
+func (b *buffer) Lines() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]string(nil), b.lines...)
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newApp(t *testing.T, filter map[string]bool) (*App, *fake.Clientset, context.Context) {
+	replicas := int32(2)
+	labels := map[string]string{"app": "api"}
+	k8s := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{{Name: "api", Image: "api:v1"}},
+					},
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", Labels: labels},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:  "api",
+					State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				}},
+			},
+		},
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "api-1.1", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "api-1", Namespace: "default"},
+			Type:           v1.EventTypeWarning,
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+			LastTimestamp:  metav1.Now(),
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	app := New(k8s, "default", filter)
+	assert.NoError(t, app.Start(ctx))
+	t.Cleanup(app.Stop)
+	return app, k8s, ctx
+}
+
+func TestRows(t *testing.T) {
+	app, _, _ := newApp(t, map[string]bool{"deploy/api": true})
+
+	var names []string
+	for _, row := range app.Rows() {
+		names = append(names, row.String())
+	}
+	assert.Equal(t, []string{"deploy/api", "pod/api-1"}, names)
+
+	screen := app.Render(100, 20)
+	assert.Contains(t, screen, "deploy/api")
+	assert.Contains(t, screen, "api:v1")
+	assert.Contains(t, screen, "api-1")
+	assert.NotContains(t, screen, "deploy/other")
+	assert.Equal(t, 20, strings.Count(screen, "\033[K"))
+}
+
+func TestEvents(t *testing.T) {
+	app, _, ctx := newApp(t, nil)
+
+	assert.True(t, app.Key(ctx, "e"))
+	assert.Contains(t, app.Render(120, 20), "BackOff")
+	assert.True(t, app.Key(ctx, "down"))
+	assert.True(t, app.Key(ctx, "down"))
+	assert.Contains(t, app.Render(120, 20), "events: deploy/other")
+	assert.NotContains(t, app.Render(120, 20), "BackOff")
+}
+
+func TestLogs(t *testing.T) {
+	app, k8s, ctx := newApp(t, nil)
+
+	// the watches of the fake clientset only see the changes made after they started
+	pod, err := k8s.CoreV1().Pods("default").Get(ctx, "api-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, err := k8s.CoreV1().Pods("default").Update(ctx, pod, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+		return strings.Contains(app.Render(120, 20), "api-1 api fake logs")
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestScaleAndRestart(t *testing.T) {
+	app, k8s, ctx := newApp(t, nil)
+
+	assert.True(t, app.Key(ctx, "+"))
+	deployment, err := k8s.AppsV1().Deployments("default").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+
+	assert.True(t, app.Key(ctx, "r"))
+	assert.Contains(t, app.Render(120, 20), "Restart deploy/api? (y/n)")
+	assert.True(t, app.Key(ctx, "y"))
+	deployment, err = k8s.AppsV1().Deployments("default").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+	assert.False(t, app.Key(ctx, "q"))
+}
+
+func TestStartForbidden(t *testing.T) {
+	k8s := fake.NewSimpleClientset()
+	k8s.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", nil)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := New(k8s, "default", nil).Start(ctx)
+	assert.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+}
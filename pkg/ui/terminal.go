@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"k8s.io/kubectl/pkg/util/term"
+)
+
+const (
+	enterScreen = "\033[?1049h\033[?25l\033[2J"
+	leaveScreen = "\033[?25h\033[?1049l"
+)
+
+// keys of the escape sequences of the terminal
+var keys = map[string]string{
+	"\x1b[A": "up",
+	"\x1b[B": "down",
+	"\x1bOA": "up",
+	"\x1bOB": "down",
+	"\x03":   "ctrl-c",
+	"\t":     "tab",
+	"\r":     "enter",
+}
+
+// ExecFunc runs a shell in a pod, reading from in, the input of the terminal
+type ExecFunc func(ctx context.Context, namespace, pod string, terminal *os.File, in io.Reader) error
+
+// Run draws the app full screen in the terminal of in and out until it quits or the context is done. The shells
+// opened by the app are run by exec, with in handed over to them.
+func Run(ctx context.Context, app *App, in, out *os.File, exec ExecFunc) error {
+	tty := term.TTY{
+		In:  in,
+		Out: out,
+		Raw: true,
+	}
+	if !tty.IsTerminalIn() || !tty.IsTerminalOut() {
+		return fmt.Errorf("dolly ui needs a terminal")
+	}
+
+	return tty.Safe(func() error {
+		input := make(chan []byte)
+		go read(in, input)
+
+		fmt.Fprint(out, enterScreen)
+		defer fmt.Fprint(out, leaveScreen)
+
+		app.Exec = func(namespace, pod string) error {
+			fmt.Fprint(out, leaveScreen)
+			defer fmt.Fprint(out, enterScreen)
+			r := &inputReader{
+				input: input,
+				done:  make(chan struct{}),
+			}
+			defer close(r.done)
+			return exec(ctx, namespace, pod, in, r)
+		}
+		defer app.Stop()
+
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		var (
+			width, height int
+			dirty         = true
+		)
+		for {
+			if size := tty.GetSize(); size != nil && (int(size.Width) != width || int(size.Height) != height) {
+				width, height = int(size.Width), int(size.Height)
+				dirty = true
+			}
+			if dirty {
+				fmt.Fprint(out, app.Render(width, height))
+				dirty = false
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case b := <-input:
+				for _, key := range parseKeys(b) {
+					if !app.Key(ctx, key) {
+						return nil
+					}
+				}
+				dirty = true
+			case <-ticker.C:
+				// redraw the changes at most four times a second, the logs change a lot
+				select {
+				case <-app.Changed():
+					dirty = true
+				default:
+				}
+			}
+		}
+	})
+}
+
+// read sends the input of the terminal to the channel, it never ends as reading the terminal can't be interrupted
+func read(in io.Reader, input chan<- []byte) {
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			input <- append([]byte(nil), buf[:n]...)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKeys splits the input in keys, the escape sequences of the arrows being one key
+func parseKeys(b []byte) []string {
+	var result []string
+	for len(b) > 0 {
+		if len(b) >= 3 {
+			if key, ok := keys[string(b[:3])]; ok {
+				result = append(result, key)
+				b = b[3:]
+				continue
+			}
+		}
+		if key, ok := keys[string(b[:1])]; ok {
+			result = append(result, key)
+		} else {
+			result = append(result, string(b[:1]))
+		}
+		b = b[1:]
+	}
+	return result
+}
+
+// inputReader hands the input of the terminal over to a shell until done is closed, the terminal is read by a single
+// goroutine
+type inputReader struct {
+	input   <-chan []byte
+	done    chan struct{}
+	pending []byte
+}
+
+func (r *inputReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		case b := <-r.input:
+			r.pending = b
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}